@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
+	"github.com/mwhite7112/woodpantry-recipes/internal/config"
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+const usage = `Usage: recipes [command]
+
+Commands:
+  serve                                  serve the API (the default)
+  config print                           print the effective configuration
+  migrate up [VERSION]                   apply migrations, up to VERSION or the latest
+  migrate down VERSION                   revert migrations down to VERSION (0 reverts all)
+  migrate goto VERSION                   migrate up or down to VERSION
+  migrate force VERSION                  record VERSION as applied and clean, running nothing
+  migrate version                        print the schema version
+  recipe export [-o FILE] [ID...]        write recipes as JSON lines (every owned one without IDs)
+  recipe import [-force] FILE...         create recipes from JSON lines ("-" reads stdin)
+  job list [-status S] [-limit N]        list ingestion jobs, newest first
+  job confirm JOB_ID...                  commit staged jobs as recipes
+  job reject JOB_ID...                   reject pending, staged or failed jobs
+  job resolve [-all] JOB_ID...           resolve staged jobs' ingredients through the Dictionary
+  dlq replay [-queue Q] [-limit N]       move dead-lettered events back to their queues
+
+The recipe and job commands act for a household, the default one unless
+-household is given, and record the caller as -actor (cli:$USER).
+Configuration is read as for serve; see recipes config print.
+`
+
+// cliFlags returns a flag set for the subcommand name that reports errors
+// instead of exiting.
+func cliFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("recipes "+name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+// flagError is what a command returns when fs.Parse fails: nothing after
+// -h, which has printed the usage, and the parse error otherwise.
+func flagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// scope holds the flags that say whom a command acts for.
+type scope struct {
+	household string
+	actor     string
+}
+
+func (s *scope) register(fs *flag.FlagSet) {
+	name := "cli"
+	if u, err := user.Current(); err == nil {
+		name = "cli:" + u.Username
+	}
+	fs.StringVar(&s.household, "household", tenant.DefaultHousehold.String(), "household to act for")
+	fs.StringVar(&s.actor, "actor", name, "recorded as created_by/updated_by")
+}
+
+// context returns ctx scoped to the household and carrying the actor as an
+// admin principal.
+func (s *scope) context(ctx context.Context) (context.Context, error) {
+	household, err := uuid.Parse(s.household)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -household %q", s.household)
+	}
+	ctx = tenant.WithHousehold(ctx, household)
+	return auth.WithPrincipal(ctx, auth.Principal{
+		Subject: s.actor,
+		Roles:   []auth.Role{auth.RoleAdmin},
+	}), nil
+}
+
+// cliContext logs to stderr, keeping stdout for command output, and returns
+// a context cancelled on SIGINT or SIGTERM.
+func cliContext(cfg *config.Config) (context.Context, context.CancelFunc) {
+	slog.SetDefault(slog.New(logging.NewContextHandler(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: logging.ParseLevel(cfg.Log.Level),
+	}))))
+	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+}
+
+// openService connects to the database and returns a Service over it, as
+// serve builds it but without the event bus: recipe events go through the
+// outbox, which the running service relays. It refuses a database whose
+// schema is not at the latest migration.
+func openService(ctx context.Context, cfg *config.Config) (*service.Service, *sql.DB, error) {
+	sqlDB, err := openDB(cfg.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		_ = sqlDB.Close()
+		return nil, nil, fmt.Errorf("connect to database: %w", err)
+	}
+	if err := db.CheckSchema(ctx, sqlDB); err != nil {
+		_ = sqlDB.Close()
+		return nil, nil, fmt.Errorf("%w; run recipes migrate up", err)
+	}
+
+	resolver := service.NewDictionaryResolver(cfg.Dictionary.URL, &http.Client{
+		Transport: logging.Transport(http.DefaultTransport),
+		Timeout:   cfg.Dictionary.Timeout,
+	})
+	svc := service.New(db.New(sqlDB), sqlDB, nil, resolver)
+	svc.SetQuerierDecorator(func(q db.Querier) db.Querier {
+		return db.WithQueryTimeout(q, cfg.Database.QueryTimeout)
+	})
+	return svc, sqlDB, nil
+}
+
+// openDB opens the connection pool configured by cfg.
+func openDB(cfg config.Database) (*sql.DB, error) {
+	return db.Open(cfg.URL, db.PoolConfig{
+		MaxOpenConns:     cfg.MaxOpenConns,
+		MaxIdleConns:     cfg.MaxIdleConns,
+		ConnMaxLifetime:  cfg.ConnMaxLifetime,
+		ConnMaxIdleTime:  cfg.ConnMaxIdleTime,
+		StatementTimeout: cfg.StatementTimeout,
+	})
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/config"
+)
+
+// configCommand runs "recipes config print", which writes the effective
+// configuration as YAML with secrets redacted. An invalid configuration is
+// still printed, followed by every problem found in it.
+func configCommand(cfg *config.Config, loadErr error, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errors.New("usage: recipes config print")
+	}
+	if err := cfg.Write(os.Stdout); err != nil {
+		return err
+	}
+	if loadErr != nil {
+		return fmt.Errorf("invalid configuration:\n%w", loadErr)
+	}
+	return nil
+}
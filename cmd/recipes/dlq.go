@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/config"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+)
+
+// dlqCommand runs "recipes dlq replay", which republishes dead-lettered
+// events to the queues they were dead-lettered from.
+func dlqCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "replay" {
+		return errors.New("usage: recipes dlq replay [-queue Q] [-limit N]")
+	}
+	fs := cliFlags("dlq replay")
+	queue := fs.String("queue", cfg.RabbitMQ.DeadLetterQueue, "dead-letter queue to replay")
+	limit := fs.Int("limit", 0, "most messages to replay; 0 replays those queued at the start")
+	if err := fs.Parse(args[1:]); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: recipes dlq replay [-queue Q] [-limit N]")
+	}
+	if cfg.RabbitMQ.URL == "" {
+		return errors.New("RABBITMQ_URL is not set")
+	}
+
+	ctx, stop := cliContext(cfg)
+	defer stop()
+
+	bus, err := events.NewRabbitMQBus(cfg.RabbitMQ.URL, events.WithExchange(cfg.RabbitMQ.Exchange))
+	if err != nil {
+		return fmt.Errorf("connect event bus: %w", err)
+	}
+	defer bus.Close()
+
+	replayed, err := bus.Replay(ctx, *queue, *limit)
+	slog.InfoContext(ctx, "replayed dead-lettered events", "queue", *queue, "count", replayed)
+	fmt.Fprintln(os.Stdout, replayed)
+	return err
+}
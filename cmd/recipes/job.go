@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/config"
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// jobCommand runs "recipes job list|confirm|reject|resolve".
+func jobCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: recipes job list|confirm|reject|resolve ...")
+	}
+	switch args[0] {
+	case "list":
+		return jobList(cfg, args[1:])
+	case "confirm":
+		return jobEach(cfg, "confirm", args[1:], nil,
+			func(ctx context.Context, svc *service.Service, job db.IngestionJob) (string, error) {
+				recipe, err := svc.ConfirmIngestionJob(ctx, job)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("confirmed as recipe %s", recipe.ID), nil
+			})
+	case "reject":
+		return jobEach(cfg, "reject", args[1:], nil,
+			func(ctx context.Context, svc *service.Service, job db.IngestionJob) (string, error) {
+				if _, err := svc.RejectIngestionJob(ctx, job); err != nil {
+					return "", err
+				}
+				return "rejected", nil
+			})
+	case "resolve":
+		var all bool
+		return jobEach(cfg, "resolve", args[1:],
+			func(fs *flag.FlagSet) {
+				fs.BoolVar(&all, "all", false, "re-resolve ingredients that already have an ID")
+			},
+			func(ctx context.Context, svc *service.Service, job db.IngestionJob) (string, error) {
+				_, changed, err := svc.ResolveStagedIngredients(ctx, job, all)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d ingredient IDs changed", changed), nil
+			})
+	default:
+		return fmt.Errorf("unknown job command %q; want list, confirm, reject or resolve", args[0])
+	}
+}
+
+func jobList(cfg *config.Config, args []string) error {
+	fs := cliFlags("job list")
+	var who scope
+	who.register(fs)
+	status := fs.String("status", "", "only list jobs in this status (pending, staged, confirmed, failed, rejected)")
+	limit := fs.Int("limit", 50, "most jobs to list")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: recipes job list [-status S] [-limit N]")
+	}
+	if *limit < 1 || *limit > math.MaxInt32 {
+		return fmt.Errorf("invalid -limit %d", *limit)
+	}
+
+	ctx, stop := cliContext(cfg)
+	defer stop()
+	ctx, err := who.context(ctx)
+	if err != nil {
+		return err
+	}
+	svc, sqlDB, err := openService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	jobs, err := svc.ListIngestionJobs(ctx, *status, int32(*limit))
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tSTATUS\tCREATED\tRECIPE")
+	for _, job := range jobs {
+		recipe := "-"
+		if job.RecipeID.Valid {
+			recipe = job.RecipeID.UUID.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			job.ID, job.Type, job.Status, job.CreatedAt.Format(time.RFC3339), recipe)
+	}
+	return w.Flush()
+}
+
+// jobEach loads each job named in args from the caller's household and
+// applies fn to it, reporting every job's outcome. A failed job does not
+// stop the others, but makes the command fail.
+func jobEach(
+	cfg *config.Config,
+	name string,
+	args []string,
+	extraFlags func(fs *flag.FlagSet),
+	fn func(ctx context.Context, svc *service.Service, job db.IngestionJob) (string, error),
+) error {
+	fs := cliFlags("job " + name)
+	var who scope
+	who.register(fs)
+	if extraFlags != nil {
+		extraFlags(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: recipes job %s JOB_ID...", name)
+	}
+	ids := make([]uuid.UUID, 0, fs.NArg())
+	for _, arg := range fs.Args() {
+		id, err := uuid.Parse(arg)
+		if err != nil {
+			return fmt.Errorf("invalid job ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	ctx, stop := cliContext(cfg)
+	defer stop()
+	ctx, err := who.context(ctx)
+	if err != nil {
+		return err
+	}
+	svc, sqlDB, err := openService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	failed := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		job, err := svc.Queries().GetIngestionJob(ctx, db.GetIngestionJobParams{
+			ID:          id,
+			HouseholdID: tenant.FromContext(ctx),
+		})
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: get job: %v\n", id, err)
+			continue
+		}
+		outcome, err := fn(ctx, svc, job)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %v\n", id, err)
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "%s\t%s\n", id, outcome)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d jobs failed to %s", failed, len(ids), name)
+	}
+	return nil
+}
@@ -2,55 +2,99 @@ package main
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	"github.com/golang-migrate/migrate/v4/source/iofs"
 	_ "github.com/lib/pq"
 
 	"github.com/mwhite7112/woodpantry-recipes/internal/api"
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
+	"github.com/mwhite7112/woodpantry-recipes/internal/config"
 	"github.com/mwhite7112/woodpantry-recipes/internal/db"
 	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/extract"
+	"github.com/mwhite7112/woodpantry-recipes/internal/health"
 	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+	"github.com/mwhite7112/woodpantry-recipes/internal/metrics"
+	"github.com/mwhite7112/woodpantry-recipes/internal/scrape"
 	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tracing"
 )
 
+// tracingShutdownTimeout bounds how long exiting waits to flush spans.
+const tracingShutdownTimeout = 5 * time.Second
+
 func main() {
-	if err := run(); err != nil {
-		slog.Error("recipes service failed", "error", err)
+	if err := run(os.Args[1:]); err != nil {
+		slog.Error("recipes failed", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
-	logging.Setup()
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+// run dispatches the subcommand named by args[0]. Without one it serves the
+// API.
+func run(args []string) error {
+	cfg, err := config.Load()
+	command := "serve"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+	switch command {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	case "config":
+		return configCommand(cfg, err, args)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
 	}
 
-	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
-		return errors.New("DB_URL is required")
+	switch command {
+	case "serve":
+		return serve(cfg)
+	case "migrate":
+		return migrateCommand(cfg, args)
+	case "recipe":
+		return recipeCommand(cfg, args)
+	case "job":
+		return jobCommand(cfg, args)
+	case "dlq":
+		return dlqCommand(cfg, args)
+	default:
+		return fmt.Errorf("unknown command %q; see recipes help", command)
 	}
+}
 
-	dictionaryURL := os.Getenv("DICTIONARY_URL")
-	if dictionaryURL == "" {
-		return errors.New("DICTIONARY_URL is required")
+func serve(cfg *config.Config) error {
+	logging.Setup(cfg.Log.Level)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Warn("failed to flush traces", "error", err)
+		}
+	}()
 
-	rabbitMQURL := os.Getenv("RABBITMQ_URL")
+	authenticators, err := setupAuth(cfg.Auth)
+	if err != nil {
+		return err
+	}
 
-	sqlDB, err := sql.Open("postgres", dbURL)
+	sqlDB, err := openDB(cfg.Database)
 	if err != nil {
-		return fmt.Errorf("open database: %w", err)
+		return err
 	}
 	defer sqlDB.Close()
 
@@ -58,124 +102,238 @@ func run() error {
 		return fmt.Errorf("connect to database: %w", err)
 	}
 
-	if err := runMigrations(sqlDB); err != nil {
-		return fmt.Errorf("run migrations: %w", err)
+	if cfg.Database.MigrateOnStart {
+		if err := runMigrations(cfg.Database.URL); err != nil {
+			return fmt.Errorf("run migrations: %w", err)
+		}
+	}
+	if err := db.CheckSchema(context.Background(), sqlDB); err != nil {
+		return fmt.Errorf("%w; run recipes migrate up, or set MIGRATE_ON_START", err)
 	}
 
-	queries := db.New(sqlDB)
-	resolver := service.NewDictionaryResolver(dictionaryURL)
-
-	importPublisher := setupImportRequestedPublisher(rabbitMQURL)
-	defer importPublisher.Close()
-
-	svc := service.New(queries, sqlDB, nil, resolver, importPublisher)
-	handler := api.NewRouter(svc)
-
-	importedSubscriber := setupRecipeImportedSubscriber(rabbitMQURL, svc)
-	defer importedSubscriber.Close()
+	readiness := health.NewChecker(0)
+	readiness.AddDetailed("database", func(ctx context.Context) (any, error) {
+		return db.Stats(sqlDB), sqlDB.PingContext(ctx)
+	})
+	readiness.Add("migrations", func(ctx context.Context) error { return db.CheckSchema(ctx, sqlDB) })
 
-	if rabbitMQURL != "" {
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-		go func() {
-			if err := importedSubscriber.Run(ctx); err != nil {
-				slog.Error("recipe.imported subscriber stopped", "error", err)
-			}
-		}()
+	queries := db.New(sqlDB)
+	dictionary := service.NewDictionaryResolver(cfg.Dictionary.URL, &http.Client{
+		Transport: tracing.Transport(logging.Transport(http.DefaultTransport)),
+		Timeout:   cfg.Dictionary.Timeout,
+	})
+	if cfg.Dictionary.CheckReady {
+		readiness.Add("dictionary", dictionary.Ping)
 	}
-
-	addr := fmt.Sprintf(":%s", port)
-	slog.Info("recipes service listening", "addr", addr)
-	if err := http.ListenAndServe(addr, handler); err != nil {
-		return fmt.Errorf("serve HTTP: %w", err)
+	resolver := metrics.InstrumentResolver(dictionary)
+	instrument := func(q db.Querier) db.Querier {
+		return tracing.TraceQuerier(metrics.InstrumentQuerier(db.WithQueryTimeout(q, cfg.Database.QueryTimeout)))
 	}
 
-	return nil
-}
+	bus, err := setupBus(cfg.RabbitMQ)
+	if err != nil {
+		return err
+	}
+	defer bus.Close()
+	if rabbit, ok := bus.(*events.RabbitMQBus); ok {
+		readiness.Add("rabbitmq", rabbit.Check)
+	}
+	bus = metrics.InstrumentBus(bus)
 
-type importRequestedPublisher interface {
-	service.ImportRequestPublisher
-	Close() error
-}
+	publisher, err := events.NewPublisher(bus)
+	if err != nil {
+		return fmt.Errorf("create event publisher: %w", err)
+	}
 
-func setupImportRequestedPublisher(rabbitMQURL string) importRequestedPublisher {
-	if rabbitMQURL == "" {
-		slog.Info("RABBITMQ_URL not set; recipe.import.requested publishing disabled")
-		return nopImportRequestedPublisher{}
+	extractor := extract.New()
+	svc := service.New(queries, sqlDB, extractor, resolver, publisher)
+	svc.SetScraper(scrape.New(nil))
+	svc.SetIngestPrefill(cfg.Features.IngestPrefill)
+	svc.SetBatchScraping(cfg.Ingest.ScrapeWorkers, cfg.Ingest.BatchTimeout)
+	svc.SetQuerierDecorator(instrument)
+	if err := metrics.RegisterDBStats(sqlDB); err != nil {
+		return fmt.Errorf("register database metrics: %w", err)
+	}
+	if err := metrics.RegisterIngestionJobs(svc.Queries(), slog.Default()); err != nil {
+		return fmt.Errorf("register ingestion job metrics: %w", err)
 	}
+	importedSubscriber, err := events.NewRecipeImportedSubscriber(
+		bus,
+		svc,
+		slog.Default(),
+		events.WithQueue(cfg.RabbitMQ.RecipeImportedQueue),
+		events.WithPrefetch(cfg.RabbitMQ.Prefetch),
+		events.WithWorkers(cfg.RabbitMQ.Workers),
+	)
+	if err != nil {
+		return fmt.Errorf("create recipe.imported subscriber: %w", err)
+	}
+	readiness.Add("recipe_imported_subscriber", importedSubscriber.Check)
 
-	pub, err := events.NewRecipeImportRequestedPublisher(rabbitMQURL)
+	relay, err := service.NewOutboxRelay(sqlDB, publisher, slog.Default())
 	if err != nil {
-		slog.Warn("failed to initialize RabbitMQ publisher; recipe.import.requested publishing disabled", "error", err)
-		return nopImportRequestedPublisher{}
+		return fmt.Errorf("create outbox relay: %w", err)
 	}
+	relay.SetQuerierDecorator(instrument)
 
-	slog.Info("RabbitMQ recipe.import.requested publisher enabled")
-	return pub
-}
+	purger, err := service.NewTrashPurger(svc.Queries(), cfg.Trash.Retention, slog.Default())
+	if err != nil {
+		return fmt.Errorf("create trash purger: %w", err)
+	}
 
-type nopImportRequestedPublisher struct{}
+	// Workers get their own context so that they keep running while HTTP
+	// requests drain and are only stopped once the server has shut down.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var background sync.WaitGroup
+	startWorker := func(name string, run func(context.Context) error) {
+		background.Go(func() {
+			if err := run(workerCtx); err != nil {
+				slog.Error(name+" stopped", "error", err)
+			}
+		})
+	}
+	startWorker("recipe.imported subscriber", importedSubscriber.Run)
+	startWorker("outbox relay", relay.Run)
+	startWorker("trash purger", purger.Run)
+
+	if cfg.RabbitMQ.URL == "" {
+		// No broker means no external Ingestion Pipeline; answer import
+		// requests in-process with the rule-based extractor.
+		pipeline, err := events.NewRecipeImportRequestedSubscriber(
+			bus,
+			extract.NewLocalPipeline(extractor, publisher),
+			slog.Default(),
+		)
+		if err != nil {
+			return fmt.Errorf("create local pipeline: %w", err)
+		}
+		readiness.Add("local_pipeline", pipeline.Check)
+		startWorker("local pipeline", pipeline.Run)
+	}
 
-func (nopImportRequestedPublisher) PublishRecipeImportRequested(
-	_ context.Context,
-	_ events.RecipeImportRequestedEvent,
-) error {
-	return nil
-}
+	handler := api.NewRouter(
+		svc,
+		api.RequireHousehold(cfg.Features.RequireHousehold),
+		api.WithAuthenticators(authenticators...),
+		api.WithReadiness(readiness),
+		api.WithRequestTimeout(cfg.HTTP.RequestTimeout),
+	)
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.HTTP.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
 
-func (nopImportRequestedPublisher) Close() error {
-	return nil
-}
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
 
-type recipeImportedSubscriber interface {
-	Run(ctx context.Context) error
-	Close() error
-}
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("recipes service listening", "addr", server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
 
-func setupRecipeImportedSubscriber(
-	rabbitMQURL string,
-	svc events.RecipeImportedEventHandler,
-) recipeImportedSubscriber {
-	if rabbitMQURL == "" {
-		slog.Info("RABBITMQ_URL not set; recipe.imported subscriber disabled")
-		return nopRecipeImportedSubscriber{}
+	select {
+	case err := <-serveErr:
+		stopWorkers()
+		background.Wait()
+		return fmt.Errorf("serve HTTP: %w", err)
+	case <-signalCtx.Done():
 	}
+	stopSignals()
 
-	sub, err := events.NewRecipeImportedSubscriber(rabbitMQURL, svc, slog.Default())
-	if err != nil {
-		slog.Warn("failed to initialize recipe.imported subscriber; subscriber disabled", "error", err)
-		return nopRecipeImportedSubscriber{}
+	slog.Info("shutting down", "timeout", cfg.HTTP.ShutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Warn("HTTP requests did not drain before the shutdown timeout", "error", err)
 	}
 
-	slog.Info("RabbitMQ recipe.imported subscriber enabled")
-	return sub
+	// Subscribers finish the delivery they are handling and hand unstarted
+	// ones back to the queue; the relay and purger finish their batch. Batch
+	// URLs still being fetched get the rest of the timeout to finish.
+	stopWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		background.Wait()
+		svc.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		slog.Info("shutdown complete")
+	case <-ctx.Done():
+		slog.Warn("background workers did not stop before the shutdown timeout")
+	}
+	return nil
 }
 
-type nopRecipeImportedSubscriber struct{}
+// setupBus connects to RabbitMQ when a URL is configured and otherwise falls
+// back to an in-process bus, so events still loop locally without a broker.
+func setupBus(cfg config.RabbitMQ) (events.Bus, error) {
+	if cfg.URL == "" {
+		slog.Info("RABBITMQ_URL not set; using in-memory event bus")
+		return events.NewMemoryBus(), nil
+	}
 
-func (nopRecipeImportedSubscriber) Run(_ context.Context) error {
-	return nil
-}
+	bus, err := events.NewRabbitMQBus(cfg.URL, events.WithExchange(cfg.Exchange))
+	if err != nil {
+		return nil, fmt.Errorf("connect event bus: %w", err)
+	}
 
-func (nopRecipeImportedSubscriber) Close() error {
-	return nil
+	slog.Info("RabbitMQ event bus enabled")
+	return bus, nil
 }
 
-func runMigrations(sqlDB *sql.DB) error {
-	srcDriver, err := iofs.New(db.MigrationsFS, "migrations")
-	if err != nil {
-		return fmt.Errorf("create migration source: %w", err)
+// setupAuth builds the authenticators for the configured JWT keys and API
+// keys. Config validation ensures at most one JWT key source is set. With
+// none of them the API is served without authentication.
+func setupAuth(cfg config.Auth) ([]auth.Authenticator, error) {
+	var authenticators []auth.Authenticator
+
+	var (
+		keys auth.KeySet
+		err  error
+	)
+	switch {
+	case cfg.JWKSFile != "":
+		keys, err = auth.LoadJWKS(cfg.JWKSFile)
+	case cfg.PublicKeyFile != "":
+		keys, err = auth.LoadPublicKey(cfg.PublicKeyFile)
+	case cfg.HMACSecret != "":
+		keys = auth.HMACKey([]byte(cfg.HMACSecret))
 	}
-	dbDriver, err := postgres.WithInstance(sqlDB, &postgres.Config{})
 	if err != nil {
-		return fmt.Errorf("create migration driver: %w", err)
+		return nil, fmt.Errorf("load JWT keys: %w", err)
 	}
-	m, err := migrate.NewWithInstance("iofs", srcDriver, "postgres", dbDriver)
-	if err != nil {
-		return fmt.Errorf("create migrator: %w", err)
+	if keys != nil {
+		verifier, err := auth.NewJWTVerifier(
+			keys,
+			auth.WithIssuer(cfg.Issuer),
+			auth.WithAudience(cfg.Audience),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create JWT verifier: %w", err)
+		}
+		authenticators = append(authenticators, verifier)
+		slog.Info("JWT authentication enabled", "keys", len(keys))
 	}
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("run migrations: %w", err)
+
+	if cfg.APIKeysFile != "" {
+		apiKeys, err := auth.LoadAPIKeys(cfg.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, apiKeys)
+		slog.Info("API key authentication enabled")
 	}
-	return nil
+
+	if len(authenticators) == 0 {
+		slog.Warn("no JWT keys or API keys configured; serving the API without authentication")
+	}
+	return authenticators, nil
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/config"
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+)
+
+const migrateUsage = "usage: recipes migrate up [VERSION] | down VERSION | goto VERSION | force VERSION | version"
+
+// runMigrations applies every pending migration.
+func runMigrations(dsn string) error {
+	m, err := db.NewMigrator(dsn)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+	return nil
+}
+
+// migrateCommand runs "recipes migrate". up, down and goto move the schema
+// to a version, refusing a dirty schema; force records a version without
+// running anything, to clear a dirty state by hand; version prints the
+// current version.
+func migrateCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	action, args := args[0], args[1:]
+	var (
+		target    uint
+		hasTarget = len(args) == 1
+	)
+	switch {
+	case len(args) > 1:
+		return errors.New(migrateUsage)
+	case action == "version" && hasTarget:
+		return errors.New("usage: recipes migrate version")
+	case (action == "down" || action == "goto" || action == "force") && !hasTarget:
+		return fmt.Errorf("migrate %s needs a VERSION; 0 is before every migration", action)
+	case hasTarget:
+		v, err := strconv.ParseUint(args[0], 10, 0)
+		if err != nil {
+			return fmt.Errorf("invalid migration version %q", args[0])
+		}
+		target = uint(v)
+	}
+
+	ctx, stop := cliContext(cfg)
+	defer stop()
+
+	m, err := db.NewMigrator(cfg.Database.URL)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	go func() {
+		<-ctx.Done()
+		m.GracefulStop <- true
+	}()
+
+	current, dirty, err := schemaVersion(m)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "version":
+		if dirty {
+			fmt.Fprintf(os.Stdout, "%d (dirty)\n", current)
+		} else {
+			fmt.Fprintln(os.Stdout, current)
+		}
+		return nil
+	case "force":
+		if target == 0 {
+			err = m.Force(database.NilVersion)
+		} else {
+			err = m.Force(int(target)) //nolint:gosec // parsed as a uint that fits an int.
+		}
+		if err != nil {
+			return fmt.Errorf("force version %d: %w", target, err)
+		}
+		slog.Warn("forced schema version", "from", current, "dirty", dirty, "to", target)
+		fmt.Fprintln(os.Stdout, target)
+		return nil
+	case "up", "down", "goto":
+	default:
+		return fmt.Errorf("unknown migrate command %q; %s", action, migrateUsage)
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty; repair it, then record the version with migrate force", current)
+	}
+	switch {
+	case action == "up" && !hasTarget:
+		err = m.Up()
+	case action == "up" && target < current:
+		return fmt.Errorf("schema is at version %d, above %d; use migrate down", current, target)
+	case action == "down" && target > current:
+		return fmt.Errorf("schema is at version %d, below %d; use migrate up", current, target)
+	case target == 0:
+		err = m.Down()
+	default:
+		err = m.Migrate(target)
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("migrate %s: %w", action, err)
+	}
+
+	version, _, err := schemaVersion(m)
+	if err != nil {
+		return err
+	}
+	slog.Info("migrated", "from", current, "to", version)
+	fmt.Fprintln(os.Stdout, version)
+	return nil
+}
+
+// schemaVersion returns m's schema version, 0 before any migration.
+func schemaVersion(m *migrate.Migrate) (version uint, dirty bool, err error) {
+	version, dirty, err = m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/config"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+// recipeCommand runs "recipes recipe export" and "recipes recipe import".
+// Export files hold one service.ExportedRecipe JSON object per line.
+func recipeCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: recipes recipe export|import ...")
+	}
+	switch args[0] {
+	case "export":
+		return recipeExport(cfg, args[1:])
+	case "import":
+		return recipeImport(cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown recipe command %q; want export or import", args[0])
+	}
+}
+
+func recipeExport(cfg *config.Config, args []string) (err error) {
+	fs := cliFlags("recipe export")
+	var who scope
+	who.register(fs)
+	output := fs.String("o", "-", `file to write, or "-" for stdout`)
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	ids := make([]uuid.UUID, 0, fs.NArg())
+	for _, arg := range fs.Args() {
+		id, err := uuid.Parse(arg)
+		if err != nil {
+			return fmt.Errorf("invalid recipe ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	ctx, stop := cliContext(cfg)
+	defer stop()
+	ctx, err = who.context(ctx)
+	if err != nil {
+		return err
+	}
+	svc, sqlDB, err := openService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	recipes, err := svc.ExportRecipes(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("create %s: %w", *output, err)
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	for _, recipe := range recipes {
+		if err := enc.Encode(recipe); err != nil {
+			return fmt.Errorf("write recipe: %w", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "exported %d recipes\n", len(recipes))
+	return nil
+}
+
+func recipeImport(cfg *config.Config, args []string) error {
+	fs := cliFlags("recipe import")
+	var who scope
+	who.register(fs)
+	force := fs.Bool("force", false, "import recipes that look like duplicates")
+	if err := fs.Parse(args); err != nil {
+		return flagError(err)
+	}
+	if fs.NArg() == 0 {
+		return errors.New(`usage: recipes recipe import [-force] FILE... ("-" reads stdin)`)
+	}
+
+	ctx, stop := cliContext(cfg)
+	defer stop()
+	ctx, err := who.context(ctx)
+	if err != nil {
+		return err
+	}
+	svc, sqlDB, err := openService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	imported, failed := 0, 0
+	for _, name := range fs.Args() {
+		err := readRecipes(name, func(line int, recipe service.ExportedRecipe) error {
+			created, err := svc.ImportRecipe(ctx, recipe, *force)
+			if err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				failed++
+				fmt.Fprintf(os.Stderr, "%s:%d: %q: %v\n", name, line, recipe.Title, err)
+				return nil
+			}
+			imported++
+			fmt.Fprintf(os.Stdout, "%s\t%s\n", created.ID, created.Title)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "imported %d recipes, %d failed\n", imported, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d recipes were not imported", failed)
+	}
+	return nil
+}
+
+// readRecipes calls fn with each recipe in the named file, or stdin for
+// "-", and the 1-based position of the recipe in it.
+func readRecipes(name string, fn func(n int, recipe service.ExportedRecipe) error) error {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", name, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	for n := 1; ; n++ {
+		var recipe service.ExportedRecipe
+		if err := dec.Decode(&recipe); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%s: recipe %d: %w", name, n, err)
+		}
+		if err := fn(n, recipe); err != nil {
+			return err
+		}
+	}
+}
@@ -1,44 +1,176 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
-	"log/slog"
+	"fmt"
+	"io"
 	"math"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/google/uuid"
 
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
 	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/document"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/health"
+	"github.com/mwhite7112/woodpantry-recipes/internal/httperr"
 	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+	"github.com/mwhite7112/woodpantry-recipes/internal/metrics"
+	"github.com/mwhite7112/woodpantry-recipes/internal/parse"
 	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tracing"
 )
 
+// RouterOption configures NewRouter.
+type RouterOption func(*routerConfig)
+
+type routerConfig struct {
+	requireHousehold bool
+	authenticators   []auth.Authenticator
+	readiness        *health.Checker
+	requestTimeout   time.Duration
+}
+
+// RequireHousehold rejects recipe and ingest requests that do not name a
+// household instead of scoping them to tenant.DefaultHousehold.
+func RequireHousehold(required bool) RouterOption {
+	return func(c *routerConfig) {
+		c.requireHousehold = required
+	}
+}
+
+// WithAuthenticators requires recipe and ingest requests to authenticate with
+// one of authn and enforces each route's role. Without authenticators the API
+// is open and roles are not checked.
+func WithAuthenticators(authn ...auth.Authenticator) RouterOption {
+	return func(c *routerConfig) {
+		c.authenticators = append(c.authenticators, authn...)
+	}
+}
+
+// WithReadiness serves checker's report on /readyz. Without it /readyz
+// reports ready as soon as the process serves HTTP.
+func WithReadiness(checker *health.Checker) RouterOption {
+	return func(c *routerConfig) {
+		c.readiness = checker
+	}
+}
+
+// WithRequestTimeout gives each recipe and ingest request a deadline of
+// timeout, shared by every transaction and query it runs. Requests that run
+// out of time get 504. Zero leaves requests without a deadline.
+func WithRequestTimeout(timeout time.Duration) RouterOption {
+	return func(c *routerConfig) {
+		c.requestTimeout = timeout
+	}
+}
+
 // NewRouter wires up all routes.
-func NewRouter(svc *service.Service) http.Handler {
+func NewRouter(svc *service.Service, opts ...RouterOption) http.Handler {
+	var cfg routerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
+	r.Use(logging.RequestIDMiddleware)
 	r.Use(logging.Middleware)
+	r.Use(metrics.Middleware)
 	r.Use(middleware.Recoverer)
 
+	readiness := cfg.readiness
+	if readiness == nil {
+		readiness = health.NewChecker(0)
+	}
 	r.Get("/healthz", handleHealth)
+	r.Get("/livez", handleHealth)
+	r.Method(http.MethodGet, "/readyz", readiness)
+	r.Handle("/metrics", metrics.Handler())
 
-	r.Get("/recipes", handleListRecipes(svc))
-	r.Post("/recipes", handleCreateRecipe(svc))
-	r.Get("/recipes/{id}", handleGetRecipe(svc))
-	r.Put("/recipes/{id}", handleUpdateRecipe(svc))
-	r.Delete("/recipes/{id}", handleDeleteRecipe(svc))
+	allow := auth.Require
+	if len(cfg.authenticators) == 0 {
+		allow = func(auth.Role) func(http.Handler) http.Handler {
+			return func(next http.Handler) http.Handler { return next }
+		}
+	}
 
-	r.Post("/recipes/ingest", handleIngest(svc))
-	r.Get("/recipes/ingest/{job_id}", handleGetIngestJob(svc))
-	r.Post("/recipes/ingest/{job_id}/confirm", handleConfirmIngest(svc))
+	r.Group(func(r chi.Router) {
+		if cfg.requestTimeout > 0 {
+			r.Use(requestDeadline(cfg.requestTimeout))
+		}
+		if len(cfg.authenticators) > 0 {
+			r.Use(auth.Middleware(cfg.authenticators...))
+		}
+		r.Use(tenant.Middleware(cfg.requireHousehold))
+		recipeRoutes(r, svc, allow)
+	})
 
 	return r
 }
 
+// requestDeadline bounds each request's context by timeout.
+func requestDeadline(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// recipeRoutes registers the recipe and ingest routes, each behind allow for
+// the role it needs.
+func recipeRoutes(r chi.Router, svc *service.Service, allow func(auth.Role) func(http.Handler) http.Handler) {
+	view := r.With(allow(auth.RoleViewer))
+	edit := r.With(allow(auth.RoleEditor))
+	review := r.With(allow(auth.RoleReviewer))
+
+	view.Get("/recipes", handleListRecipes(svc))
+	edit.Post("/recipes", handleCreateRecipe(svc))
+	view.Get("/recipes/trash", handleListTrash(svc))
+	view.Get("/recipes/{id}", handleGetRecipe(svc))
+	edit.Put("/recipes/{id}", handleUpdateRecipe(svc))
+	edit.Patch("/recipes/{id}", handlePatchRecipe(svc))
+	edit.Delete("/recipes/{id}", handleDeleteRecipe(svc))
+	edit.Post("/recipes/{id}/restore", handleRestoreRecipe(svc))
+	edit.Post("/recipes/{id}/steps", handleInsertStep(svc))
+	edit.Patch("/recipes/{id}/steps/{n}", handlePatchStep(svc))
+	edit.Delete("/recipes/{id}/steps/{n}", handleDeleteStep(svc))
+	edit.Post("/recipes/{id}/ingredients", handleAddIngredient(svc))
+	edit.Patch("/recipes/{id}/ingredients/{ingredient_row_id}", handlePatchIngredient(svc))
+	edit.Delete("/recipes/{id}/ingredients/{ingredient_row_id}", handleDeleteIngredient(svc))
+	edit.Post("/recipes/{id}/merge", handleMergeRecipe(svc))
+	view.Get("/recipes/{id}/revisions", handleListRevisions(svc))
+	view.Get("/recipes/{id}/revisions/{n}", handleGetRevision(svc))
+	view.Get("/recipes/{id}/revisions/{n}/diff", handleDiffRevisions(svc))
+	edit.Post("/recipes/{id}/revisions/{n}/revert", handleRevertRevision(svc))
+	view.Get("/recipes/{id}/shares", handleListShares(svc))
+	edit.Put("/recipes/{id}/shares/{household_id}", handleShareRecipe(svc))
+	edit.Delete("/recipes/{id}/shares/{household_id}", handleUnshareRecipe(svc))
+
+	edit.Post("/recipes/ingest", handleIngest(svc))
+	edit.Post("/recipes/ingest/batch", handleIngestBatch(svc))
+	view.Get("/recipes/ingest/batch/{batch_id}", handleGetIngestBatch(svc))
+	review.Post("/recipes/ingest/batch/{batch_id}/confirm", handleConfirmIngestBatch(svc))
+	view.Get("/recipes/ingest/{job_id}", handleGetIngestJob(svc))
+	view.Get("/recipes/ingest/{job_id}/source", handleGetIngestSource(svc))
+	review.Post("/recipes/ingest/{job_id}/confirm", handleConfirmIngest(svc))
+}
+
 func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("ok")) //nolint:errcheck
 }
@@ -52,27 +184,33 @@ func handleListRecipes(svc *service.Service) http.HandlerFunc {
 		titleSearch := q.Get("title")
 		cookTimeStr := q.Get("cook_time_max")
 
+		household := tenant.FromContext(r.Context())
 		var recipes []db.Recipe
 		var err error
 
 		switch {
 		case tag != "":
-			recipes, err = svc.Queries().ListRecipesByTag(r.Context(), []string{tag})
+			recipes, err = svc.Queries().ListRecipesByTag(r.Context(), db.ListRecipesByTagParams{
+				Tags:        []string{tag},
+				HouseholdID: household,
+			})
 		case cookTimeStr != "":
 			maxCook, parseErr := strconv.ParseInt(cookTimeStr, 10, 32)
 			if parseErr != nil {
 				jsonError(w, "invalid cook_time_max", http.StatusBadRequest)
 				return
 			}
-			recipes, err = svc.Queries().ListRecipesByCookTime(
-				r.Context(),
-				sql.NullInt32{Int32: int32(maxCook), Valid: true},
-			)
+			recipes, err = svc.Queries().ListRecipesByCookTime(r.Context(), db.ListRecipesByCookTimeParams{
+				CookMinutes: sql.NullInt32{Int32: int32(maxCook), Valid: true},
+				HouseholdID: household,
+			})
 		case titleSearch != "":
-			recipes, err = svc.Queries().
-				ListRecipesByTitle(r.Context(), sql.NullString{String: titleSearch, Valid: true})
+			recipes, err = svc.Queries().ListRecipesByTitle(r.Context(), db.ListRecipesByTitleParams{
+				Title:       titleSearch,
+				HouseholdID: household,
+			})
 		default:
-			recipes, err = svc.Queries().ListRecipes(r.Context())
+			recipes, err = svc.Queries().ListRecipes(r.Context(), household)
 		}
 
 		if err != nil {
@@ -96,17 +234,37 @@ type recipeInput struct {
 	PrepMinutes int      `json:"prep_minutes"`
 	CookMinutes int      `json:"cook_minutes"`
 	Tags        []string `json:"tags"`
+	Visibility  string   `json:"visibility"`
 	Steps       []struct {
 		StepNumber  int    `json:"step_number"`
 		Instruction string `json:"instruction"`
 	} `json:"steps"`
-	Ingredients []struct {
-		IngredientID     string  `json:"ingredient_id"`
-		Quantity         float64 `json:"quantity"`
-		Unit             string  `json:"unit"`
-		IsOptional       bool    `json:"is_optional"`
-		PreparationNotes string  `json:"preparation_notes"`
-	} `json:"ingredients"`
+	Ingredients []ingredientInput `json:"ingredients"`
+}
+
+type ingredientInput struct {
+	IngredientID     string  `json:"ingredient_id"`
+	Quantity         float64 `json:"quantity"`
+	Unit             string  `json:"unit"`
+	IsOptional       bool    `json:"is_optional"`
+	PreparationNotes string  `json:"preparation_notes"`
+}
+
+type duplicateResponse struct {
+	Error      string                       `json:"error"`
+	Duplicates []service.DuplicateCandidate `json:"duplicates"`
+}
+
+// fingerprint returns the duplicate-check fingerprint of the input. Invalid
+// ingredient IDs are skipped here and rejected when the recipe is written.
+func (in recipeInput) fingerprint() service.RecipeFingerprint {
+	fp := service.RecipeFingerprint{Title: in.Title, SourceURL: in.SourceURL}
+	for _, ing := range in.Ingredients {
+		if id, err := uuid.Parse(ing.IngredientID); err == nil {
+			fp.IngredientIDs = append(fp.IngredientIDs, id)
+		}
+	}
+	return fp
 }
 
 //nolint:gocognit // Handler coordinates validation + transactional writes across related tables.
@@ -121,11 +279,30 @@ func handleCreateRecipe(svc *service.Service) http.HandlerFunc {
 			jsonError(w, "title is required", http.StatusBadRequest)
 			return
 		}
+		if err := service.ValidateVisibility(req.Visibility); err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		tags := req.Tags
 		if tags == nil {
 			tags = []string{}
 		}
 
+		if force, _ := strconv.ParseBool(r.URL.Query().Get("force")); !force {
+			duplicates, err := svc.FindDuplicates(r.Context(), req.fingerprint())
+			if err != nil {
+				jsonError(w, "failed to check for duplicates", http.StatusInternalServerError, err)
+				return
+			}
+			if len(duplicates) > 0 {
+				jsonWithStatus(w, http.StatusConflict, duplicateResponse{
+					Error:      "recipe looks like a duplicate; retry with ?force=true to create it anyway",
+					Duplicates: duplicates,
+				})
+				return
+			}
+		}
+
 		tx, err := svc.DB().BeginTx(r.Context(), nil)
 		if err != nil {
 			jsonError(w, "failed to start transaction", http.StatusInternalServerError, err)
@@ -133,9 +310,11 @@ func handleCreateRecipe(svc *service.Service) http.HandlerFunc {
 		}
 		defer tx.Rollback() //nolint:errcheck
 
-		qtx := db.New(tx)
+		qtx := svc.TxQueries(tx)
 
 		recipe, err := qtx.CreateRecipe(r.Context(), db.CreateRecipeParams{
+			HouseholdID: tenant.FromContext(r.Context()),
+			Visibility:  req.Visibility,
 			Title:       req.Title,
 			Description: nullString(req.Description),
 			SourceUrl:   nullString(req.SourceURL),
@@ -143,6 +322,7 @@ func handleCreateRecipe(svc *service.Service) http.HandlerFunc {
 			PrepMinutes: nullInt32(req.PrepMinutes),
 			CookMinutes: nullInt32(req.CookMinutes),
 			Tags:        tags,
+			CreatedBy:   nullString(auth.Subject(r.Context())),
 		})
 		if err != nil {
 			jsonError(w, "failed to create recipe", http.StatusInternalServerError, err)
@@ -184,11 +364,17 @@ func handleCreateRecipe(svc *service.Service) http.HandlerFunc {
 			}
 		}
 
+		if err := svc.EnqueueRecipeEvent(r.Context(), qtx, events.RecipeCreatedEventType, recipe.ID); err != nil {
+			jsonError(w, "failed to record recipe event", http.StatusInternalServerError, err)
+			return
+		}
+
 		if err := tx.Commit(); err != nil {
 			jsonError(w, "failed to commit", http.StatusInternalServerError, err)
 			return
 		}
 
+		w.Header().Set("ETag", recipeETag(recipe))
 		jsonWithStatus(w, http.StatusCreated, recipe)
 	}
 }
@@ -209,7 +395,10 @@ func handleGetRecipe(svc *service.Service) http.HandlerFunc {
 			jsonError(w, "invalid id", http.StatusBadRequest)
 			return
 		}
-		recipe, err := svc.Queries().GetRecipe(r.Context(), id)
+		recipe, err := svc.Queries().GetRecipe(r.Context(), db.GetRecipeParams{
+			ID:          id,
+			HouseholdID: tenant.FromContext(r.Context()),
+		})
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				jsonError(w, "recipe not found", http.StatusNotFound)
@@ -218,24 +407,45 @@ func handleGetRecipe(svc *service.Service) http.HandlerFunc {
 			jsonError(w, "failed to get recipe", http.StatusInternalServerError, err)
 			return
 		}
-		steps, err := svc.Queries().ListStepsByRecipe(r.Context(), id)
-		if err != nil {
-			jsonError(w, "failed to get steps", http.StatusInternalServerError, err)
+		etag := recipeETag(recipe)
+		w.Header().Set("ETag", etag)
+		if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag, true) {
+			w.WriteHeader(http.StatusNotModified)
 			return
 		}
-		ingredients, err := svc.Queries().ListIngredientsByRecipe(r.Context(), id)
+		detail, err := loadRecipeDetail(r, svc.Queries(), recipe)
 		if err != nil {
-			jsonError(w, "failed to get ingredients", http.StatusInternalServerError, err)
+			jsonError(w, "failed to get recipe", http.StatusInternalServerError, err)
 			return
 		}
-		if steps == nil {
-			steps = []db.RecipeStep{}
-		}
-		if ingredients == nil {
-			ingredients = []db.RecipeIngredient{}
-		}
-		jsonOK(w, recipeDetail{Recipe: recipe, Steps: steps, Ingredients: ingredients})
+		jsonOK(w, detail)
+	}
+}
+
+// loadRecipeDetail reads the recipe's steps and ingredients through q.
+func loadRecipeDetail(r *http.Request, q db.Querier, recipe db.Recipe) (recipeDetail, error) {
+	household := tenant.FromContext(r.Context())
+	steps, err := q.ListStepsByRecipe(r.Context(), db.ListStepsByRecipeParams{
+		RecipeID:    recipe.ID,
+		HouseholdID: household,
+	})
+	if err != nil {
+		return recipeDetail{}, fmt.Errorf("list steps: %w", err)
+	}
+	ingredients, err := q.ListIngredientsByRecipe(r.Context(), db.ListIngredientsByRecipeParams{
+		RecipeID:    recipe.ID,
+		HouseholdID: household,
+	})
+	if err != nil {
+		return recipeDetail{}, fmt.Errorf("list ingredients: %w", err)
 	}
+	if steps == nil {
+		steps = []db.RecipeStep{}
+	}
+	if ingredients == nil {
+		ingredients = []db.RecipeIngredient{}
+	}
+	return recipeDetail{Recipe: recipe, Steps: steps, Ingredients: ingredients}, nil
 }
 
 // --- update ---
@@ -249,8 +459,14 @@ func handleUpdateRecipe(svc *service.Service) http.HandlerFunc {
 			return
 		}
 
-		// Verify recipe exists first.
-		if _, err := svc.Queries().GetRecipe(r.Context(), id); err != nil {
+		// Verify recipe exists first. Households that can only read the
+		// recipe get the same 404 as the owner-scoped writes below.
+		household := tenant.FromContext(r.Context())
+		existing, err := svc.Queries().GetRecipe(r.Context(), db.GetRecipeParams{ID: id, HouseholdID: household})
+		if err == nil && existing.HouseholdID != household {
+			err = sql.ErrNoRows
+		}
+		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				jsonError(w, "recipe not found", http.StatusNotFound)
 				return
@@ -268,6 +484,10 @@ func handleUpdateRecipe(svc *service.Service) http.HandlerFunc {
 			jsonError(w, "title is required", http.StatusBadRequest)
 			return
 		}
+		if err := service.ValidateVisibility(req.Visibility); err != nil {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		tags := req.Tags
 		if tags == nil {
 			tags = []string{}
@@ -280,10 +500,25 @@ func handleUpdateRecipe(svc *service.Service) http.HandlerFunc {
 		}
 		defer tx.Rollback() //nolint:errcheck
 
-		qtx := db.New(tx)
+		qtx := svc.TxQueries(tx)
+
+		if err := checkIfMatch(r, qtx, id); err != nil {
+			if errors.Is(err, errPreconditionFailed) {
+				jsonError(w, err.Error(), http.StatusPreconditionFailed)
+				return
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to get recipe", http.StatusInternalServerError, err)
+			return
+		}
 
 		recipe, err := qtx.UpdateRecipe(r.Context(), db.UpdateRecipeParams{
 			ID:          id,
+			HouseholdID: household,
+			Visibility:  req.Visibility,
 			Title:       req.Title,
 			Description: nullString(req.Description),
 			SourceUrl:   nullString(req.SourceURL),
@@ -291,6 +526,7 @@ func handleUpdateRecipe(svc *service.Service) http.HandlerFunc {
 			PrepMinutes: nullInt32(req.PrepMinutes),
 			CookMinutes: nullInt32(req.CookMinutes),
 			Tags:        tags,
+			UpdatedBy:   nullString(auth.Subject(r.Context())),
 		})
 		if err != nil {
 			jsonError(w, "failed to update recipe", http.StatusInternalServerError, err)
@@ -340,15 +576,212 @@ func handleUpdateRecipe(svc *service.Service) http.HandlerFunc {
 			}
 		}
 
+		if err := svc.EnqueueRecipeEvent(r.Context(), qtx, events.RecipeUpdatedEventType, id); err != nil {
+			jsonError(w, "failed to record recipe event", http.StatusInternalServerError, err)
+			return
+		}
+
 		if err := tx.Commit(); err != nil {
 			jsonError(w, "failed to commit", http.StatusInternalServerError, err)
 			return
 		}
 
+		w.Header().Set("ETag", recipeETag(recipe))
 		jsonOK(w, recipe)
 	}
 }
 
+// --- patch ---
+
+const mergePatchContentType = "application/merge-patch+json"
+
+func handlePatchRecipe(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		patch, ok := decodePatch(w, r)
+		if !ok {
+			return
+		}
+		editRecipe(w, r, svc, http.StatusOK, func(q db.Querier, id uuid.UUID) (db.Recipe, error) {
+			return svc.PatchRecipe(r.Context(), q, id, patch)
+		})
+	}
+}
+
+type stepInput struct {
+	StepNumber  int    `json:"step_number"`
+	Instruction string `json:"instruction"`
+}
+
+// handleInsertStep inserts a step at step_number, or appends it when
+// step_number is omitted.
+func handleInsertStep(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req stepInput
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		editRecipe(w, r, svc, http.StatusCreated, func(q db.Querier, id uuid.UUID) (db.Recipe, error) {
+			return svc.InsertStep(r.Context(), q, id, req.StepNumber, req.Instruction)
+		})
+	}
+}
+
+func handlePatchStep(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := stepParam(w, r)
+		if !ok {
+			return
+		}
+		patch, ok := decodePatch(w, r)
+		if !ok {
+			return
+		}
+		editRecipe(w, r, svc, http.StatusOK, func(q db.Querier, id uuid.UUID) (db.Recipe, error) {
+			return svc.PatchStep(r.Context(), q, id, n, patch)
+		})
+	}
+}
+
+func handleDeleteStep(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, ok := stepParam(w, r)
+		if !ok {
+			return
+		}
+		editRecipe(w, r, svc, http.StatusOK, func(q db.Querier, id uuid.UUID) (db.Recipe, error) {
+			return svc.DeleteStep(r.Context(), q, id, n)
+		})
+	}
+}
+
+func handleAddIngredient(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ingredientInput
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		ingID, err := uuid.Parse(req.IngredientID)
+		if err != nil {
+			jsonError(w, "invalid ingredient_id: "+req.IngredientID, http.StatusBadRequest)
+			return
+		}
+		editRecipe(w, r, svc, http.StatusCreated, func(q db.Querier, id uuid.UUID) (db.Recipe, error) {
+			return svc.AddIngredient(r.Context(), q, db.CreateRecipeIngredientParams{
+				RecipeID:         id,
+				IngredientID:     ingID,
+				Quantity:         nullFloat64(req.Quantity),
+				Unit:             nullString(req.Unit),
+				IsOptional:       req.IsOptional,
+				PreparationNotes: nullString(req.PreparationNotes),
+			})
+		})
+	}
+}
+
+func handlePatchIngredient(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rowID, err := uuid.Parse(chi.URLParam(r, "ingredient_row_id"))
+		if err != nil {
+			jsonError(w, "invalid ingredient row id", http.StatusBadRequest)
+			return
+		}
+		patch, ok := decodePatch(w, r)
+		if !ok {
+			return
+		}
+		editRecipe(w, r, svc, http.StatusOK, func(q db.Querier, id uuid.UUID) (db.Recipe, error) {
+			return svc.PatchIngredient(r.Context(), q, id, rowID, patch)
+		})
+	}
+}
+
+func handleDeleteIngredient(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rowID, err := uuid.Parse(chi.URLParam(r, "ingredient_row_id"))
+		if err != nil {
+			jsonError(w, "invalid ingredient row id", http.StatusBadRequest)
+			return
+		}
+		editRecipe(w, r, svc, http.StatusOK, func(q db.Querier, id uuid.UUID) (db.Recipe, error) {
+			return svc.DeleteIngredient(r.Context(), q, id, rowID)
+		})
+	}
+}
+
+// editRecipe runs edit in a transaction guarded by the request's If-Match
+// header, then responds with the recipe's full detail and new ETag.
+func editRecipe(
+	w http.ResponseWriter,
+	r *http.Request,
+	svc *service.Service,
+	status int,
+	edit func(q db.Querier, id uuid.UUID) (db.Recipe, error),
+) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonError(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var detail recipeDetail
+	err = svc.WithTx(r.Context(), func(q db.Querier) error {
+		if err := checkIfMatch(r, q, id); err != nil {
+			return err
+		}
+		recipe, err := edit(q, id)
+		if err != nil {
+			return err
+		}
+		detail, err = loadRecipeDetail(r, q, recipe)
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			jsonError(w, "recipe not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrStepNotFound), errors.Is(err, service.ErrIngredientNotFound):
+			jsonError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidPatch):
+			jsonError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, errPreconditionFailed):
+			jsonError(w, err.Error(), http.StatusPreconditionFailed)
+		default:
+			jsonError(w, "failed to update recipe", http.StatusInternalServerError, err)
+		}
+		return
+	}
+	w.Header().Set("ETag", recipeETag(detail.Recipe))
+	jsonWithStatus(w, status, detail)
+}
+
+// decodePatch reads a JSON merge patch body. A plain application/json
+// content type is accepted as well.
+func decodePatch(w http.ResponseWriter, r *http.Request) (service.Patch, bool) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+			jsonError(w, "patch must be "+mergePatchContentType, http.StatusUnsupportedMediaType)
+			return nil, false
+		}
+	}
+	var patch service.Patch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	return patch, true
+}
+
+func stepParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	n, err := strconv.Atoi(chi.URLParam(r, "n"))
+	if err != nil || n < 1 {
+		jsonError(w, "invalid step number", http.StatusBadRequest)
+		return 0, false
+	}
+	return n, true
+}
+
 // --- delete ---
 
 func handleDeleteRecipe(svc *service.Service) http.HandlerFunc {
@@ -358,11 +791,21 @@ func handleDeleteRecipe(svc *service.Service) http.HandlerFunc {
 			jsonError(w, "invalid id", http.StatusBadRequest)
 			return
 		}
-		if err := svc.Queries().DeleteRecipe(r.Context(), id); err != nil {
+		err = svc.WithTx(r.Context(), func(q db.Querier) error {
+			if err := checkIfMatch(r, q, id); err != nil {
+				return err
+			}
+			return svc.TrashRecipe(r.Context(), q, id)
+		})
+		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				jsonError(w, "recipe not found", http.StatusNotFound)
 				return
 			}
+			if errors.Is(err, errPreconditionFailed) {
+				jsonError(w, err.Error(), http.StatusPreconditionFailed)
+				return
+			}
 			jsonError(w, "failed to delete recipe", http.StatusInternalServerError, err)
 			return
 		}
@@ -370,46 +813,567 @@ func handleDeleteRecipe(svc *service.Service) http.HandlerFunc {
 	}
 }
 
+// --- trash ---
+
+func handleListTrash(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipes, err := svc.Queries().ListTrashedRecipes(r.Context(), tenant.FromContext(r.Context()))
+		if err != nil {
+			jsonError(w, "failed to list trash", http.StatusInternalServerError, err)
+			return
+		}
+		if recipes == nil {
+			recipes = []db.Recipe{}
+		}
+		jsonOK(w, recipes)
+	}
+}
+
+// handleRestoreRecipe takes a recipe out of the trash. Consumers dropped it on
+// recipe.deleted, so it is announced again as recipe.created.
+func handleRestoreRecipe(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			jsonError(w, "invalid id", http.StatusBadRequest)
+			return
+		}
+		var recipe db.Recipe
+		err = svc.WithTx(r.Context(), func(q db.Querier) error {
+			recipe, err = q.RestoreRecipe(r.Context(), db.RestoreRecipeParams{
+				ID:          id,
+				HouseholdID: tenant.FromContext(r.Context()),
+				UpdatedBy:   nullString(auth.Subject(r.Context())),
+			})
+			if err != nil {
+				return err
+			}
+			return svc.EnqueueRecipeEvent(r.Context(), q, events.RecipeCreatedEventType, id)
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "recipe not found in trash", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to restore recipe", http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("ETag", recipeETag(recipe))
+		jsonOK(w, recipe)
+	}
+}
+
+// --- merge ---
+
+type mergeRequest struct {
+	SourceID string                `json:"source_id"`
+	Strategy service.MergeStrategy `json:"strategy"`
+}
+
+func handleMergeRecipe(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			jsonError(w, "invalid id", http.StatusBadRequest)
+			return
+		}
+		var req mergeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		sourceID, err := uuid.Parse(req.SourceID)
+		if err != nil {
+			jsonError(w, "invalid source_id", http.StatusBadRequest)
+			return
+		}
+
+		recipe, err := svc.MergeRecipes(r.Context(), id, sourceID, req.Strategy)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidMerge):
+				jsonError(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, sql.ErrNoRows):
+				jsonError(w, "recipe not found", http.StatusNotFound)
+			default:
+				jsonError(w, "failed to merge recipes", http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		jsonOK(w, recipe)
+	}
+}
+
+// --- revisions ---
+
+type revisionSummary struct {
+	Revision  int       `json:"revision"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func handleListRevisions(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			jsonError(w, "invalid id", http.StatusBadRequest)
+			return
+		}
+		if !recipeVisible(w, r, svc, id) {
+			return
+		}
+
+		revisions, err := svc.ListRevisions(r.Context(), id)
+		if err != nil {
+			jsonError(w, "failed to list revisions", http.StatusInternalServerError, err)
+			return
+		}
+		summaries := make([]revisionSummary, 0, len(revisions))
+		for _, rev := range revisions {
+			summaries = append(summaries, revisionSummary{
+				Revision:  rev.Revision,
+				Title:     rev.Recipe.Title,
+				CreatedAt: rev.CreatedAt,
+			})
+		}
+		jsonOK(w, summaries)
+	}
+}
+
+// recipeVisible reports whether the caller's household can read recipe id,
+// writing a 404 or 500 when it cannot. Revisions are keyed by recipe alone, so
+// their handlers check the recipe first.
+func recipeVisible(w http.ResponseWriter, r *http.Request, svc *service.Service, id uuid.UUID) bool {
+	household := tenant.FromContext(r.Context())
+	_, err := svc.Queries().GetRecipe(r.Context(), db.GetRecipeParams{ID: id, HouseholdID: household})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			jsonError(w, "recipe not found", http.StatusNotFound)
+			return false
+		}
+		jsonError(w, "failed to get recipe", http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+// revisionParams parses the {id} and {n} URL parameters, writing a 400 and
+// returning false when either is invalid or a 404 when the recipe is not
+// visible to the caller.
+func revisionParams(w http.ResponseWriter, r *http.Request, svc *service.Service) (uuid.UUID, int, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonError(w, "invalid id", http.StatusBadRequest)
+		return uuid.Nil, 0, false
+	}
+	n, err := strconv.Atoi(chi.URLParam(r, "n"))
+	if err != nil || n < 1 {
+		jsonError(w, "invalid revision number", http.StatusBadRequest)
+		return uuid.Nil, 0, false
+	}
+	if !recipeVisible(w, r, svc, id) {
+		return uuid.Nil, 0, false
+	}
+	return id, n, true
+}
+
+func revisionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrRevisionNotFound) {
+		jsonError(w, "revision not found", http.StatusNotFound)
+		return
+	}
+	jsonError(w, "failed to get revision", http.StatusInternalServerError, err)
+}
+
+func handleGetRevision(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, n, ok := revisionParams(w, r, svc)
+		if !ok {
+			return
+		}
+		rev, err := svc.GetRevision(r.Context(), id, n)
+		if err != nil {
+			revisionError(w, err)
+			return
+		}
+		jsonOK(w, rev)
+	}
+}
+
+// handleDiffRevisions compares revision {n} with ?from= (default n-1).
+func handleDiffRevisions(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, n, ok := revisionParams(w, r, svc)
+		if !ok {
+			return
+		}
+		from := n - 1
+		if v := r.URL.Query().Get("from"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 1 {
+				jsonError(w, "invalid from revision", http.StatusBadRequest)
+				return
+			}
+			from = parsed
+		}
+		if from < 1 {
+			jsonError(w, "revision 1 has no previous revision; pass ?from=", http.StatusBadRequest)
+			return
+		}
+
+		diff, err := svc.DiffRevisions(r.Context(), id, from, n)
+		if err != nil {
+			revisionError(w, err)
+			return
+		}
+		jsonOK(w, diff)
+	}
+}
+
+func handleRevertRevision(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, n, ok := revisionParams(w, r, svc)
+		if !ok {
+			return
+		}
+		recipe, err := svc.RevertRecipe(r.Context(), id, n)
+		if err != nil {
+			if errors.Is(err, service.ErrRevisionNotFound) {
+				jsonError(w, "revision not found", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to revert recipe", http.StatusInternalServerError, err)
+			return
+		}
+		jsonOK(w, recipe)
+	}
+}
+
+// --- shares ---
+
+func handleListShares(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			jsonError(w, "invalid id", http.StatusBadRequest)
+			return
+		}
+		shares, err := svc.ListShares(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to list shares", http.StatusInternalServerError, err)
+			return
+		}
+		jsonOK(w, shares)
+	}
+}
+
+// shareParams parses the {id} and {household_id} URL parameters, writing a
+// 400 and returning false when either is invalid.
+func shareParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonError(w, "invalid id", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	household, err := uuid.Parse(chi.URLParam(r, "household_id"))
+	if err != nil {
+		jsonError(w, "invalid household_id", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, household, true
+}
+
+func handleShareRecipe(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, household, ok := shareParams(w, r)
+		if !ok {
+			return
+		}
+		if household == tenant.FromContext(r.Context()) {
+			jsonError(w, "cannot share a recipe with its own household", http.StatusBadRequest)
+			return
+		}
+		if err := svc.ShareRecipe(r.Context(), id, household); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "recipe not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to share recipe", http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func handleUnshareRecipe(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, household, ok := shareParams(w, r)
+		if !ok {
+			return
+		}
+		if err := svc.UnshareRecipe(r.Context(), id, household); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "share not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to unshare recipe", http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
 // --- ingest ---
 
 type ingestRequest struct {
 	Text string `json:"text"`
+	URL  string `json:"url"`
 }
 
 func handleIngest(svc *service.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+			ingestFile(w, r, svc)
+			return
+		}
+
 		var req ingestRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			jsonError(w, "invalid request body", http.StatusBadRequest)
 			return
 		}
-		if req.Text == "" {
-			jsonError(w, "text is required", http.StatusBadRequest)
+		if (req.Text == "") == (req.URL == "") {
+			jsonError(w, "exactly one of text or url is required", http.StatusBadRequest)
+			return
+		}
+		if req.URL != "" {
+			ingestURL(w, r, svc, req.URL)
 			return
 		}
 
-		job, err := svc.Queries().CreateIngestionJob(r.Context(), db.CreateIngestionJobParams{
-			Type:     "text_blob",
-			RawInput: req.Text,
+		job, err := svc.IngestText(r.Context(), req.Text)
+		if err != nil {
+			jsonError(w, "failed to ingest text", http.StatusInternalServerError, err)
+			return
+		}
+
+		jsonWithStatus(w, http.StatusCreated, job)
+	}
+}
+
+func ingestURL(w http.ResponseWriter, r *http.Request, svc *service.Service, rawURL string) {
+	if err := checkIngestURL(svc, rawURL); err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job, err := svc.IngestURL(r.Context(), rawURL)
+	if err != nil {
+		if errors.Is(err, service.ErrScrapeFailed) {
+			jsonError(w, "failed to fetch url", http.StatusBadGateway, err)
+			return
+		}
+		if errors.Is(err, service.ErrJobClosed) {
+			jsonError(w, err.Error(), http.StatusConflict)
+			return
+		}
+		jsonError(w, "failed to ingest url", http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonWithStatus(w, http.StatusCreated, job)
+}
+
+type ingestBatchRequest struct {
+	Items []service.BatchItem `json:"items"`
+	// Text may hold several recipes separated by "---" lines; each becomes
+	// its own item after Items.
+	Text string `json:"text"`
+}
+
+type ingestBatchResponse struct {
+	BatchID uuid.UUID                 `json:"batch_id"`
+	Items   []service.BatchItemResult `json:"items"`
+}
+
+func handleIngestBatch(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ingestBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		items := req.Items
+		for _, text := range parse.SplitRecipes(req.Text) {
+			items = append(items, service.BatchItem{Text: text})
+		}
+		if len(items) == 0 {
+			jsonError(w, "items or text is required", http.StatusBadRequest)
+			return
+		}
+		if len(items) > service.MaxBatchItems {
+			jsonError(w, fmt.Sprintf("a batch holds at most %d items", service.MaxBatchItems), http.StatusBadRequest)
+			return
+		}
+		for i, item := range items {
+			if (item.Text == "") == (item.URL == "") {
+				jsonError(w, fmt.Sprintf("items[%d]: exactly one of text or url is required", i), http.StatusBadRequest)
+				return
+			}
+			if item.URL == "" {
+				continue
+			}
+			if err := checkIngestURL(svc, item.URL); err != nil {
+				jsonError(w, fmt.Sprintf("items[%d]: %v", i, err), http.StatusBadRequest)
+				return
+			}
+		}
+
+		batch, results, err := svc.IngestBatch(r.Context(), items)
+		if err != nil {
+			jsonError(w, "failed to create ingestion batch", http.StatusInternalServerError, err)
+			return
+		}
+
+		// URL items are still being fetched; their jobs report the outcome.
+		jsonWithStatus(w, http.StatusAccepted, ingestBatchResponse{BatchID: batch.ID, Items: results})
+	}
+}
+
+type ingestBatchStatusResponse struct {
+	ID        uuid.UUID         `json:"id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Status    string            `json:"status"`
+	Counts    map[string]int    `json:"counts"`
+	Jobs      []db.IngestionJob `json:"jobs"`
+}
+
+func handleGetIngestBatch(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "batch_id"))
+		if err != nil {
+			jsonError(w, "invalid batch_id", http.StatusBadRequest)
+			return
+		}
+
+		status, err := svc.GetBatchStatus(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "batch not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to get batch", http.StatusInternalServerError, err)
+			return
+		}
+
+		jobs := status.Jobs
+		if jobs == nil {
+			jobs = []db.IngestionJob{}
+		}
+		jsonOK(w, ingestBatchStatusResponse{
+			ID:        status.Batch.ID,
+			CreatedAt: status.Batch.CreatedAt,
+			Status:    status.Status,
+			Counts:    status.Counts,
+			Jobs:      jobs,
 		})
+	}
+}
+
+type confirmBatchResponse struct {
+	BatchID uuid.UUID                    `json:"batch_id"`
+	Results []service.BatchConfirmResult `json:"results"`
+}
+
+func handleConfirmIngestBatch(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "batch_id"))
 		if err != nil {
-			jsonError(w, "failed to create ingestion job", http.StatusInternalServerError, err)
+			jsonError(w, "invalid batch_id", http.StatusBadRequest)
 			return
 		}
 
-		if err := svc.PublishRecipeImportRequested(r.Context(), job); err != nil {
-			svc.Queries().UpdateIngestionJobStatus(r.Context(), db.UpdateIngestionJobStatusParams{ //nolint:errcheck
-				ID:     job.ID,
-				Status: "failed",
-			})
-			jsonError(w, "failed to enqueue recipe import", http.StatusInternalServerError, err)
+		results, err := svc.ConfirmBatch(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "batch not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to confirm batch", http.StatusInternalServerError, err)
 			return
 		}
 
-		jsonWithStatus(w, http.StatusCreated, job)
+		jsonOK(w, confirmBatchResponse{BatchID: id, Results: results})
 	}
 }
 
+// ingestFile handles a multipart upload with the recipe in the "file" field.
+func ingestFile(w http.ResponseWriter, r *http.Request, svc *service.Service) {
+	// Leave headroom over the file limit for the multipart framing.
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxUploadBytes+64<<10)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			jsonError(w, "file too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		jsonError(w, "multipart field \"file\" is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		jsonError(w, "failed to read upload", http.StatusBadRequest)
+		return
+	}
+
+	job, err := svc.IngestFile(r.Context(), service.UploadedFile{
+		Filename:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Data:        data,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFileTooLarge):
+			jsonError(w, "file too large", http.StatusRequestEntityTooLarge)
+		case errors.Is(err, document.ErrUnsupportedFormat):
+			jsonError(w, "unsupported file type: upload a PDF, Markdown or plain text file",
+				http.StatusUnsupportedMediaType)
+		case errors.Is(err, document.ErrNoText), errors.Is(err, document.ErrMalformed):
+			jsonError(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			jsonError(w, "failed to ingest file", http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	jsonWithStatus(w, http.StatusCreated, job)
+}
+
+// checkIngestURL rejects URLs the scraper must not fetch, so obviously
+// internal targets fail fast with 400 instead of creating a failed job. The
+// scraper checks the resolved addresses again when it connects.
+func checkIngestURL(svc *service.Service, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	return svc.CheckIngestURL(rawURL)
+}
+
 func handleGetIngestJob(svc *service.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, err := uuid.Parse(chi.URLParam(r, "job_id"))
@@ -417,7 +1381,10 @@ func handleGetIngestJob(svc *service.Service) http.HandlerFunc {
 			jsonError(w, "invalid job_id", http.StatusBadRequest)
 			return
 		}
-		job, err := svc.Queries().GetIngestionJob(r.Context(), id)
+		job, err := svc.Queries().GetIngestionJob(r.Context(), db.GetIngestionJobParams{
+			ID:          id,
+			HouseholdID: tenant.FromContext(r.Context()),
+		})
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				jsonError(w, "job not found", http.StatusNotFound)
@@ -430,14 +1397,18 @@ func handleGetIngestJob(svc *service.Service) http.HandlerFunc {
 	}
 }
 
-func handleConfirmIngest(svc *service.Service) http.HandlerFunc {
+// handleGetIngestSource downloads the original file a job was ingested from.
+func handleGetIngestSource(svc *service.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, err := uuid.Parse(chi.URLParam(r, "job_id"))
 		if err != nil {
 			jsonError(w, "invalid job_id", http.StatusBadRequest)
 			return
 		}
-		job, err := svc.Queries().GetIngestionJob(r.Context(), id)
+		job, err := svc.Queries().GetIngestionJob(r.Context(), db.GetIngestionJobParams{
+			ID:          id,
+			HouseholdID: tenant.FromContext(r.Context()),
+		})
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				jsonError(w, "job not found", http.StatusNotFound)
@@ -446,28 +1417,60 @@ func handleConfirmIngest(svc *service.Service) http.HandlerFunc {
 			jsonError(w, "failed to get job", http.StatusInternalServerError, err)
 			return
 		}
-		if job.Status != "staged" {
-			jsonError(w, "job is not in staged status", http.StatusConflict)
+		if !job.SourceFileID.Valid {
+			jsonError(w, "job has no source file", http.StatusNotFound)
 			return
 		}
 
-		recipe, err := svc.CommitStagedRecipe(r.Context(), job)
+		file, err := svc.Queries().GetIngestionFile(r.Context(), db.GetIngestionFileParams{
+			ID:          job.SourceFileID.UUID,
+			HouseholdID: job.HouseholdID,
+		})
 		if err != nil {
-			jsonError(w, "failed to commit recipe: "+err.Error(), http.StatusInternalServerError, err)
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "source file not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to get source file", http.StatusInternalServerError, err)
 			return
 		}
 
-		if _, err := svc.Queries().UpdateIngestionJobStatus(r.Context(), db.UpdateIngestionJobStatusParams{
-			ID:     job.ID,
-			Status: "confirmed",
-		}); err != nil {
-			slog.Default().Error(
-				"failed to mark job confirmed (recipe already committed)",
-				"job_id",
-				job.ID,
-				"error",
-				err,
-			)
+		w.Header().Set("Content-Type", file.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(file.SizeBytes, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": file.Filename,
+		}))
+		w.Write(file.Data) //nolint:errcheck
+	}
+}
+
+func handleConfirmIngest(svc *service.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "job_id"))
+		if err != nil {
+			jsonError(w, "invalid job_id", http.StatusBadRequest)
+			return
+		}
+		job, err := svc.Queries().GetIngestionJob(r.Context(), db.GetIngestionJobParams{
+			ID:          id,
+			HouseholdID: tenant.FromContext(r.Context()),
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				jsonError(w, "job not found", http.StatusNotFound)
+				return
+			}
+			jsonError(w, "failed to get job", http.StatusInternalServerError, err)
+			return
+		}
+		recipe, err := svc.ConfirmIngestionJob(r.Context(), job)
+		if err != nil {
+			if errors.Is(err, service.ErrJobNotStaged) {
+				jsonError(w, err.Error(), http.StatusConflict)
+				return
+			}
+			jsonError(w, "failed to commit recipe: "+err.Error(), http.StatusInternalServerError, err)
+			return
 		}
 
 		jsonWithStatus(w, http.StatusCreated, recipe)
@@ -476,6 +1479,47 @@ func handleConfirmIngest(svc *service.Service) http.HandlerFunc {
 
 // --- helpers ---
 
+var errPreconditionFailed = errors.New("recipe has changed; fetch it again and retry with the new ETag")
+
+// recipeETag is the strong entity tag for the recipe's current version.
+func recipeETag(recipe db.Recipe) string {
+	return `"v` + strconv.Itoa(int(recipe.Version)) + `"`
+}
+
+// etagMatches reports whether an If-Match or If-None-Match header value
+// lists etag. If-None-Match uses weak comparison; If-Match must not.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch enforces the request's If-Match header against the recipe's
+// current ETag, returning errPreconditionFailed on mismatch. The row is
+// locked so the check holds until q's transaction ends. Requests without
+// If-Match always pass.
+func checkIfMatch(r *http.Request, q db.Querier, id uuid.UUID) error {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return nil
+	}
+	current, err := q.LockRecipe(r.Context(), db.LockRecipeParams{ID: id, HouseholdID: tenant.FromContext(r.Context())})
+	if err != nil {
+		return err
+	}
+	if !etagMatches(ifMatch, recipeETag(current), false) {
+		return errPreconditionFailed
+	}
+	return nil
+}
+
 func jsonOK(w http.ResponseWriter, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(v) //nolint:errcheck
@@ -487,13 +1531,13 @@ func jsonWithStatus(w http.ResponseWriter, status int, v any) {
 	json.NewEncoder(w).Encode(v) //nolint:errcheck
 }
 
+// jsonError writes an error body through httperr.Write. A 500 caused by a
+// query running out of time is reported as 504.
 func jsonError(w http.ResponseWriter, msg string, status int, errs ...error) {
-	if status >= 500 && len(errs) > 0 {
-		slog.Default().Error(msg, "status", status, "error", errs[0])
+	if status == http.StatusInternalServerError && len(errs) > 0 && db.IsTimeout(errs[0]) {
+		status = http.StatusGatewayTimeout
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg}) //nolint:errcheck
+	httperr.Write(w, msg, status, errs...)
 }
 
 func nullString(s string) sql.NullString {
@@ -1,9 +1,15 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,14 +17,19 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
 	"github.com/mwhite7112/woodpantry-recipes/internal/db"
 	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/health"
 	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/scrape"
 	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
 )
 
 // stubExtractor is a no-op LLM extractor for handler tests.
@@ -51,10 +62,22 @@ func setupRouter(t *testing.T) (*mocks.MockQuerier, http.Handler) {
 	t.Helper()
 	mockQ := mocks.NewMockQuerier(t)
 	svc := service.New(mockQ, nil, &stubExtractor{}, &stubResolver{})
+	svc.SetTransactor(transactor(t, mockQ))
 	router := NewRouter(svc)
 	return mockQ, router
 }
 
+// transactor returns a Transactor that runs every transaction against q.
+func transactor(t *testing.T, q db.Querier) *service.MockTransactor {
+	t.Helper()
+
+	tx := service.NewMockTransactor(t)
+	tx.EXPECT().InTx(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(db.Querier) error) error { return fn(q) }).
+		Maybe()
+	return tx
+}
+
 func TestHealthz(t *testing.T) {
 	t.Parallel()
 	_, router := setupRouter(t)
@@ -67,6 +90,43 @@ func TestHealthz(t *testing.T) {
 	assert.Equal(t, "ok", rec.Body.String())
 }
 
+func TestProbes(t *testing.T) {
+	t.Parallel()
+
+	readiness := health.NewChecker(0)
+	readiness.Add("database", func(context.Context) error { return errors.New("connection refused") })
+	router := NewRouter(service.New(mocks.NewMockQuerier(t), nil, &stubExtractor{}, &stubResolver{}),
+		WithReadiness(readiness))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "liveness does not depend on the database")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var report health.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+
+	_, router = setupRouter(t)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "no checks configured")
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	_, router := setupRouter(t)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `recipes_http_requests_total{method="GET",route="/healthz",status="200"}`)
+}
+
 func TestListRecipes_Default(t *testing.T) {
 	t.Parallel()
 	mockQ, router := setupRouter(t)
@@ -81,7 +141,7 @@ func TestListRecipes_Default(t *testing.T) {
 			UpdatedAt: now,
 		},
 	}
-	mockQ.EXPECT().ListRecipes(mock.Anything).Return(recipes, nil)
+	mockQ.EXPECT().ListRecipes(mock.Anything, uuid.Nil).Return(recipes, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/recipes", nil)
 	rec := httptest.NewRecorder()
@@ -98,7 +158,8 @@ func TestListRecipes_ByTag(t *testing.T) {
 	t.Parallel()
 	mockQ, router := setupRouter(t)
 
-	mockQ.EXPECT().ListRecipesByTag(mock.Anything, []string{"vegan"}).Return([]db.Recipe{}, nil)
+	mockQ.EXPECT().ListRecipesByTag(mock.Anything, db.ListRecipesByTagParams{Tags: []string{"vegan"}}).
+		Return([]db.Recipe{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/recipes?tag=vegan", nil)
 	rec := httptest.NewRecorder()
@@ -112,7 +173,9 @@ func TestListRecipes_ByCookTime(t *testing.T) {
 	mockQ, router := setupRouter(t)
 
 	mockQ.EXPECT().
-		ListRecipesByCookTime(mock.Anything, sql.NullInt32{Int32: 30, Valid: true}).
+		ListRecipesByCookTime(mock.Anything, db.ListRecipesByCookTimeParams{
+			CookMinutes: sql.NullInt32{Int32: 30, Valid: true},
+		}).
 		Return([]db.Recipe{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/recipes?cook_time_max=30", nil)
@@ -138,7 +201,7 @@ func TestListRecipes_ByTitle(t *testing.T) {
 	mockQ, router := setupRouter(t)
 
 	mockQ.EXPECT().
-		ListRecipesByTitle(mock.Anything, sql.NullString{String: "pasta", Valid: true}).
+		ListRecipesByTitle(mock.Anything, db.ListRecipesByTitleParams{Title: "pasta"}).
 		Return([]db.Recipe{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/recipes?title=pasta", nil)
@@ -168,9 +231,10 @@ func TestGetRecipe_Success(t *testing.T) {
 		{ID: uuid.New(), RecipeID: id, IngredientID: uuid.New()},
 	}
 
-	mockQ.EXPECT().GetRecipe(mock.Anything, id).Return(recipe, nil)
-	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, id).Return(steps, nil)
-	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, id).Return(ingredients, nil)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id}).Return(recipe, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{RecipeID: id}).Return(steps, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{RecipeID: id}).
+		Return(ingredients, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/recipes/"+id.String(), nil)
 	rec := httptest.NewRecorder()
@@ -184,12 +248,30 @@ func TestGetRecipe_Success(t *testing.T) {
 	assert.Len(t, got["ingredients"], 1)
 }
 
+func TestGetRecipe_ETag(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id := uuid.New()
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id}).
+		Return(db.Recipe{ID: id, Title: "Soup", Version: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/"+id.String(), nil)
+	req.Header.Set("If-None-Match", `"v2", W/"v3"`)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, `"v3"`, rec.Header().Get("ETag"))
+	assert.Empty(t, rec.Body.String())
+}
+
 func TestGetRecipe_NotFound(t *testing.T) {
 	t.Parallel()
 	mockQ, router := setupRouter(t)
 
 	id := uuid.New()
-	mockQ.EXPECT().GetRecipe(mock.Anything, id).Return(db.Recipe{}, sql.ErrNoRows)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id}).Return(db.Recipe{}, sql.ErrNoRows)
 
 	req := httptest.NewRequest(http.MethodGet, "/recipes/"+id.String(), nil)
 	rec := httptest.NewRecorder()
@@ -209,12 +291,159 @@ func TestGetRecipe_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+	_, router := setupRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/not-a-uuid", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "req-123", rec.Header().Get("X-Request-ID"))
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, "req-123", body["request_id"])
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes/not-a-uuid", nil))
+	generated := rec.Header().Get("X-Request-ID")
+	require.NoError(t, uuid.Validate(generated), "a missing ID is generated")
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, generated, body["request_id"])
+}
+
+func TestQueryTimeout(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	mockQ.EXPECT().ListRecipes(mock.Anything, uuid.Nil).
+		Return(nil, fmt.Errorf("list recipes: %w", context.DeadlineExceeded)).Once()
+	mockQ.EXPECT().ListRecipesByTag(mock.Anything, mock.Anything).
+		Return(nil, &pq.Error{Code: "57014", Message: "canceling statement due to statement timeout"}).Once()
+
+	for _, target := range []string{"/recipes", "/recipes?tag=soup"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusGatewayTimeout, rec.Code, target)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+	mockQ := mocks.NewMockQuerier(t)
+	router := NewRouter(service.New(mockQ, nil, &stubExtractor{}, &stubResolver{}),
+		WithRequestTimeout(time.Millisecond))
+
+	mockQ.EXPECT().ListRecipes(mock.Anything, uuid.Nil).
+		RunAndReturn(func(ctx context.Context, _ uuid.UUID) ([]db.Recipe, error) {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok, "queries run under the request's deadline")
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).Once()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+}
+
+func TestCreateRecipe_RejectsDuplicate(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	existing := uuid.New()
+	mockQ.EXPECT().FindRecipesBySourceURL(mock.Anything, db.FindRecipesBySourceURLParams{
+		SourceUrl: "https://example.com/soup",
+	}).Return([]db.FindRecipesBySourceURLRow{{ID: existing, Title: "Soup"}}, nil)
+	mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, db.FindRecipesByTitleKeyParams{Title: "Tomato Soup"}).
+		Return(nil, nil)
+
+	body := `{"title": "Tomato Soup", "source_url": "https://example.com/soup"}`
+	req := httptest.NewRequest(http.MethodPost, "/recipes", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusConflict, rec.Code)
+	var got struct {
+		Duplicates []service.DuplicateCandidate `json:"duplicates"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got.Duplicates, 1)
+	assert.Equal(t, existing, got.Duplicates[0].RecipeID)
+	assert.Equal(t, []string{"source_url"}, got.Duplicates[0].Reasons)
+}
+
+func TestMergeRecipe_Errors(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	// Recipes are locked in ID order, so a missing target with the lowest
+	// possible ID is always the first lock to fail.
+	targetID := uuid.MustParse("00000000-0000-4000-8000-000000000000")
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: targetID}).Return(db.Recipe{}, sql.ErrNoRows)
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{`{"source_id": "nope"}`, http.StatusBadRequest},
+		{`{"source_id": "` + targetID.String() + `"}`, http.StatusBadRequest},
+		{`{"source_id": "` + uuid.NewString() + `", "strategy": {"title": "both"}}`, http.StatusBadRequest},
+		{`{"source_id": "` + uuid.NewString() + `"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/recipes/"+targetID.String()+"/merge", strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.body)
+	}
+}
+
+func TestGetRevision(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	recipeID := uuid.New()
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: recipeID}).Return(db.Recipe{ID: recipeID}, nil)
+	mockQ.EXPECT().GetRecipeRevision(mock.Anything, db.GetRecipeRevisionParams{RecipeID: recipeID, Revision: 1}).
+		Return(db.RecipeRevision{Revision: 1, Snapshot: json.RawMessage(`{"title":"Soup","tags":[]}`)}, nil)
+	mockQ.EXPECT().GetRecipeRevision(mock.Anything, db.GetRecipeRevisionParams{RecipeID: recipeID, Revision: 5}).
+		Return(db.RecipeRevision{}, sql.ErrNoRows)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/revisions/1", http.StatusOK},
+		{"/revisions/5", http.StatusNotFound},
+		{"/revisions/0", http.StatusBadRequest},
+		{"/revisions/1/diff", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/recipes/"+recipeID.String()+tt.path, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.path)
+	}
+}
+
 func TestDeleteRecipe_Success(t *testing.T) {
 	t.Parallel()
 	mockQ, router := setupRouter(t)
 
 	id := uuid.New()
-	mockQ.EXPECT().DeleteRecipe(mock.Anything, id).Return(nil)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id}).Return(db.Recipe{ID: id, Title: "Pasta"}, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{RecipeID: id}).Return(nil, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{RecipeID: id}).
+		Return(nil, nil)
+	mockQ.EXPECT().
+		InsertOutboxEvent(mock.Anything, mock.MatchedBy(func(arg db.InsertOutboxEventParams) bool {
+			return arg.EventType == events.RecipeDeletedEventType && arg.RoutingKey == "recipe.deleted"
+		})).
+		Return(db.EventOutbox{}, nil)
+	mockQ.EXPECT().SoftDeleteRecipe(mock.Anything, db.SoftDeleteRecipeParams{ID: id}).Return(db.Recipe{ID: id}, nil)
 
 	req := httptest.NewRequest(http.MethodDelete, "/recipes/"+id.String(), nil)
 	rec := httptest.NewRecorder()
@@ -223,12 +452,286 @@ func TestDeleteRecipe_Success(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, rec.Code)
 }
 
+func TestDeleteRecipe_IfMatchMismatch(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id := uuid.New()
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: id}).Return(db.Recipe{ID: id, Version: 4}, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/recipes/"+id.String(), nil)
+	req.Header.Set("If-Match", `"v3"`)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+}
+
+func TestListTrash(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	trashed := db.Recipe{
+		ID:        uuid.New(),
+		Title:     "Old Soup",
+		DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	mockQ.EXPECT().ListTrashedRecipes(mock.Anything, uuid.Nil).Return([]db.Recipe{trashed}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/trash", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got []db.Recipe
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got, 1)
+	assert.Equal(t, trashed.ID, got[0].ID)
+}
+
+func TestRestoreRecipe_Success(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id := uuid.New()
+	mockQ.EXPECT().RestoreRecipe(mock.Anything, db.RestoreRecipeParams{ID: id}).
+		Return(db.Recipe{ID: id, Title: "Soup", Version: 4}, nil)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id}).Return(db.Recipe{ID: id, Title: "Soup"}, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{RecipeID: id}).Return(nil, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{RecipeID: id}).
+		Return(nil, nil)
+	mockQ.EXPECT().InsertRecipeRevision(mock.Anything, mock.Anything).Return(db.RecipeRevision{}, nil)
+	mockQ.EXPECT().
+		InsertOutboxEvent(mock.Anything, mock.MatchedBy(func(arg db.InsertOutboxEventParams) bool {
+			return arg.EventType == events.RecipeCreatedEventType
+		})).
+		Return(db.EventOutbox{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/recipes/"+id.String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"v4"`, rec.Header().Get("ETag"))
+}
+
+func TestRestoreRecipe_NotInTrash(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id := uuid.New()
+	mockQ.EXPECT().RestoreRecipe(mock.Anything, db.RestoreRecipeParams{ID: id}).Return(db.Recipe{}, sql.ErrNoRows)
+
+	req := httptest.NewRequest(http.MethodPost, "/recipes/"+id.String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRequireHousehold(t *testing.T) {
+	t.Parallel()
+	mockQ := mocks.NewMockQuerier(t)
+	router := NewRouter(service.New(mockQ, nil, &stubExtractor{}, &stubResolver{}), RequireHousehold(true))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGetRecipe_ScopedToHousehold(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id, household := uuid.New(), uuid.New()
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id, HouseholdID: household}).
+		Return(db.Recipe{}, sql.ErrNoRows)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/"+id.String(), nil)
+	req.Header.Set(tenant.Header, household.String())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestUpdateRecipe_OtherHousehold(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id, household := uuid.New(), uuid.New()
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id, HouseholdID: household}).
+		Return(db.Recipe{ID: id, HouseholdID: uuid.New(), Visibility: service.VisibilityPublic}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/recipes/"+id.String(), strings.NewReader(`{"title": "Mine now"}`))
+	req.Header.Set(tenant.Header, household.String())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCreateRecipe_InvalidVisibility(t *testing.T) {
+	t.Parallel()
+	_, router := setupRouter(t)
+
+	body := `{"title": "Soup", "visibility": "everyone"}`
+	req := httptest.NewRequest(http.MethodPost, "/recipes", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestShareRecipe(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id, owner, guest := uuid.New(), uuid.New(), uuid.New()
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: id, HouseholdID: owner}).
+		Return(db.Recipe{ID: id, HouseholdID: owner}, nil)
+	mockQ.EXPECT().AddRecipeShare(mock.Anything, db.AddRecipeShareParams{RecipeID: id, HouseholdID: guest}).Return(nil)
+
+	tests := []struct {
+		household string
+		want      int
+	}{
+		{guest.String(), http.StatusNoContent},
+		{owner.String(), http.StatusBadRequest},
+		{"nope", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/recipes/"+id.String()+"/shares/"+tt.household, nil)
+		req.Header.Set(tenant.Header, owner.String())
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.household)
+	}
+}
+
+func TestUnshareRecipe_NotShared(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id, guest := uuid.New(), uuid.New()
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: id}).Return(db.Recipe{ID: id}, nil)
+	mockQ.EXPECT().RemoveRecipeShare(mock.Anything, db.RemoveRecipeShareParams{RecipeID: id, HouseholdID: guest}).
+		Return(0, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/recipes/"+id.String()+"/shares/"+guest.String(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestListShares(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id, guest := uuid.New(), uuid.New()
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: id}).Return(db.Recipe{ID: id}, nil)
+	mockQ.EXPECT().ListRecipeShares(mock.Anything, id).
+		Return([]db.RecipeShare{{RecipeID: id, HouseholdID: guest}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/"+id.String()+"/shares", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got []db.RecipeShare
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got, 1)
+	assert.Equal(t, guest, got[0].HouseholdID)
+}
+
+func TestPatchRecipe_Success(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id := uuid.New()
+	current := db.Recipe{ID: id, Title: "Soup", Tags: []string{"dinner"}, Version: 2}
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: id}).Return(current, nil)
+	mockQ.EXPECT().UpdateRecipe(mock.Anything, db.UpdateRecipeParams{
+		ID:    id,
+		Title: "Soup",
+		Tags:  []string{"dinner", "quick"},
+	}).Return(db.Recipe{ID: id, Title: "Soup", Tags: []string{"dinner", "quick"}, Version: 3}, nil)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id}).Return(current, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{RecipeID: id}).Return(nil, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{RecipeID: id}).
+		Return(nil, nil)
+	mockQ.EXPECT().InsertRecipeRevision(mock.Anything, mock.Anything).Return(db.RecipeRevision{}, nil)
+	mockQ.EXPECT().InsertOutboxEvent(mock.Anything, mock.Anything).Return(db.EventOutbox{}, nil)
+
+	body := strings.NewReader(`{"tags": ["dinner", "quick"]}`)
+	req := httptest.NewRequest(http.MethodPatch, "/recipes/"+id.String(), body)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"v2"`)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `"v3"`, rec.Header().Get("ETag"))
+	var detail map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	assert.Equal(t, []interface{}{"dinner", "quick"}, detail["Tags"])
+	assert.Equal(t, []interface{}{}, detail["steps"])
+}
+
+func TestPatchRecipe_UnsupportedMediaType(t *testing.T) {
+	t.Parallel()
+	_, router := setupRouter(t)
+
+	req := httptest.NewRequest(http.MethodPatch, "/recipes/"+uuid.New().String(), strings.NewReader(`[]`))
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestPatchRecipe_InvalidField(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id := uuid.New()
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: id}).Return(db.Recipe{ID: id, Title: "Soup"}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/recipes/"+id.String(), strings.NewReader(`{"title": null}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "title must be a non-empty string")
+}
+
+func TestDeleteStep_NotFound(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	id := uuid.New()
+	mockQ.EXPECT().TouchRecipe(mock.Anything, db.TouchRecipeParams{ID: id}).Return(db.Recipe{ID: id}, nil)
+	mockQ.EXPECT().DeleteStep(mock.Anything, db.DeleteStepParams{RecipeID: id, StepNumber: 4}).Return(0, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/recipes/"+id.String()+"/steps/4", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "step not found")
+}
+
 func TestDeleteRecipe_NotFound(t *testing.T) {
 	t.Parallel()
 	mockQ, router := setupRouter(t)
 
 	id := uuid.New()
-	mockQ.EXPECT().DeleteRecipe(mock.Anything, id).Return(sql.ErrNoRows)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id}).Return(db.Recipe{}, sql.ErrNoRows)
 
 	req := httptest.NewRequest(http.MethodDelete, "/recipes/"+id.String(), nil)
 	rec := httptest.NewRecorder()
@@ -250,7 +753,7 @@ func TestGetIngestJob_Success(t *testing.T) {
 		Status:    "staged",
 		CreatedAt: now,
 	}
-	mockQ.EXPECT().GetIngestionJob(mock.Anything, jobID).Return(job, nil)
+	mockQ.EXPECT().GetIngestionJob(mock.Anything, db.GetIngestionJobParams{ID: jobID}).Return(job, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/recipes/ingest/"+jobID.String(), nil)
 	rec := httptest.NewRecorder()
@@ -264,7 +767,8 @@ func TestGetIngestJob_NotFound(t *testing.T) {
 	mockQ, router := setupRouter(t)
 
 	jobID := uuid.New()
-	mockQ.EXPECT().GetIngestionJob(mock.Anything, jobID).Return(db.IngestionJob{}, sql.ErrNoRows)
+	mockQ.EXPECT().GetIngestionJob(mock.Anything, db.GetIngestionJobParams{ID: jobID}).
+		Return(db.IngestionJob{}, sql.ErrNoRows)
 
 	req := httptest.NewRequest(http.MethodGet, "/recipes/ingest/"+jobID.String(), nil)
 	rec := httptest.NewRecorder()
@@ -286,7 +790,7 @@ func TestPostIngest_MissingText(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	var errBody map[string]string
 	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errBody))
-	assert.Contains(t, errBody["error"], "text is required")
+	assert.Contains(t, errBody["error"], "exactly one of text or url is required")
 }
 
 func TestPostIngest_QueuesJob(t *testing.T) {
@@ -321,6 +825,82 @@ func TestPostIngest_QueuesJob(t *testing.T) {
 	assert.Equal(t, "some recipe text", publisher.event.RawInput)
 }
 
+func TestPostIngest_PrefillsJob(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	publisher := &stubImportPublisher{}
+	svc := service.New(mockQ, nil, &stubExtractor{}, &stubResolver{}, publisher)
+	svc.SetIngestPrefill(true)
+	router := NewRouter(svc)
+
+	job := db.IngestionJob{ID: uuid.New(), Type: "text_blob", RawInput: "some recipe text", Status: "pending"}
+	preview := json.RawMessage(`{"title":"stub"}`)
+	mockQ.EXPECT().CreateIngestionJob(mock.Anything, mock.Anything).Return(job, nil)
+	mockQ.EXPECT().PrefillIngestionJob(mock.Anything, mock.MatchedBy(func(p db.PrefillIngestionJobParams) bool {
+		return p.ID == job.ID
+	})).Return(db.IngestionJob{
+		ID:         job.ID,
+		Type:       job.Type,
+		RawInput:   job.RawInput,
+		Status:     "pending",
+		StagedData: &preview,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/recipes/ingest", strings.NewReader(`{"text":"some recipe text"}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.NotNil(t, publisher.event)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "pending", got["Status"])
+	assert.Equal(t, map[string]any{"title": "stub"}, got["StagedData"])
+}
+
+func TestPostIngest_URLValidation(t *testing.T) {
+	t.Parallel()
+	svc := service.New(mocks.NewMockQuerier(t), nil, &stubExtractor{}, &stubResolver{})
+	svc.SetScraper(scrape.New(nil))
+	router := NewRouter(svc)
+
+	for _, body := range []string{
+		`{"url":"ftp://example.com/recipe"}`,
+		`{"url":"/relative/path"}`,
+		`{"text":"recipe","url":"https://example.com"}`,
+		`{"url":"http://169.254.169.254/latest/meta-data/"}`,
+		`{"url":"http://localhost:8080/admin"}`,
+		`{"url":"http://[::ffff:10.0.0.1]/"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/recipes/ingest", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestPostIngest_URLFetchFailure(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	scraper := service.NewMockRecipeScraper(t)
+	svc := service.New(mockQ, nil, &stubExtractor{}, &stubResolver{})
+	svc.SetScraper(scraper)
+	router := NewRouter(svc)
+
+	job := db.IngestionJob{ID: uuid.New(), Type: service.JobTypeURL, RawInput: "https://example.com/r"}
+	mockQ.EXPECT().CreateIngestionJob(mock.Anything, mock.Anything).Return(job, nil)
+	scraper.EXPECT().ScrapeRecipe(mock.Anything, "https://example.com/r").Return(nil, errors.New("timeout"))
+	mockQ.EXPECT().UpdateIngestionJobStatus(mock.Anything, mock.Anything).Return(db.IngestionJob{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/recipes/ingest", strings.NewReader(`{"url":"https://example.com/r"}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
 func TestPostIngest_InvalidBody(t *testing.T) {
 	t.Parallel()
 	_, router := setupRouter(t)
@@ -333,13 +913,207 @@ func TestPostIngest_InvalidBody(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
+func TestPostIngestBatch_Validation(t *testing.T) {
+	t.Parallel()
+	svc := service.New(mocks.NewMockQuerier(t), nil, &stubExtractor{}, &stubResolver{})
+	svc.SetScraper(scrape.New(nil))
+	router := NewRouter(svc)
+
+	tooMany := `{"items":[` + strings.Repeat(`{"text":"x"},`, service.MaxBatchItems) + `{"text":"x"}]}`
+	for _, body := range []string{
+		`{}`,
+		`{"text":"---"}`,
+		`{"items":[{"text":"a","url":"https://example.com"}]}`,
+		`{"items":[{}]}`,
+		`{"items":[{"url":"example.com/recipe"}]}`,
+		`{"items":[{"url":"https://example.com/r"},{"url":"http://127.0.0.1:5432/"}]}`,
+		tooMany,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/recipes/ingest/batch", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestPostIngestBatch_SplitsText(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	publisher := &stubImportPublisher{}
+	svc := service.New(mockQ, nil, &stubExtractor{}, &stubResolver{}, publisher)
+	router := NewRouter(svc)
+
+	batch := db.IngestionBatch{ID: uuid.New()}
+	mockQ.EXPECT().CreateIngestionBatch(mock.Anything, uuid.Nil).Return(batch, nil)
+	var inputs []string
+	mockQ.EXPECT().CreateIngestionJob(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, p db.CreateIngestionJobParams) (db.IngestionJob, error) {
+			inputs = append(inputs, p.RawInput)
+			return db.IngestionJob{ID: uuid.New(), Type: p.Type, RawInput: p.RawInput, Status: "pending"}, nil
+		}).Times(3)
+
+	body := `{"items":[{"text":"Soup"}],"text":"Toast\n---\nTea"}`
+	req := httptest.NewRequest(http.MethodPost, "/recipes/ingest/batch", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, []string{"Soup", "Toast", "Tea"}, inputs)
+
+	var resp struct {
+		BatchID uuid.UUID `json:"batch_id"`
+		Items   []struct {
+			Position int            `json:"position"`
+			Job      map[string]any `json:"job"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, batch.ID, resp.BatchID)
+	require.Len(t, resp.Items, 3)
+	assert.Equal(t, 2, resp.Items[2].Position)
+}
+
+func multipartUpload(t *testing.T, filename string, data []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/recipes/ingest", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestPostIngest_FileUpload(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	publisher := &stubImportPublisher{}
+	svc := service.New(mockQ, nil, &stubExtractor{}, &stubResolver{}, publisher)
+	svc.SetTransactor(transactor(t, mockQ))
+	router := NewRouter(svc)
+
+	fileID := uuid.New()
+	jobID := uuid.New()
+	mockQ.EXPECT().CreateIngestionFile(mock.Anything, mock.MatchedBy(func(p db.CreateIngestionFileParams) bool {
+		return p.Filename == "toast.md" && p.ContentType == "text/markdown; charset=utf-8" && p.SizeBytes == 30
+	})).Return(db.IngestionFile{ID: fileID}, nil)
+	mockQ.EXPECT().CreateIngestionJob(mock.Anything, db.CreateIngestionJobParams{
+		Type:         "markdown",
+		RawInput:     "Toast\n\n- 2 slices bread",
+		SourceFileID: uuid.NullUUID{UUID: fileID, Valid: true},
+	}).Return(db.IngestionJob{
+		ID:           jobID,
+		Type:         "markdown",
+		RawInput:     "Toast\n\n- 2 slices bread",
+		Status:       "pending",
+		SourceFileID: uuid.NullUUID{UUID: fileID, Valid: true},
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, multipartUpload(t, "toast.md", []byte("# Toast\n\n- 2 slices **bread**\n")))
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NotNil(t, publisher.event)
+	assert.Equal(t, jobID, publisher.event.JobID)
+	assert.Equal(t, "Toast\n\n- 2 slices bread", publisher.event.RawInput)
+}
+
+func TestPostIngest_FileUploadRejected(t *testing.T) {
+	t.Parallel()
+	_, router := setupRouter(t)
+
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		want     int
+	}{
+		{name: "image", filename: "toast.png", data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+			want: http.StatusUnsupportedMediaType},
+		{name: "empty text", filename: "toast.txt", data: []byte("\n \n"), want: http.StatusUnprocessableEntity},
+		{name: "too large", filename: "toast.txt", data: bytes.Repeat([]byte("a"), service.MaxUploadBytes+1),
+			want: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, multipartUpload(t, tt.filename, tt.data))
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestGetIngestSource(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	jobID := uuid.New()
+	fileID := uuid.New()
+	mockQ.EXPECT().GetIngestionJob(mock.Anything, db.GetIngestionJobParams{ID: jobID}).Return(db.IngestionJob{
+		ID:           jobID,
+		Type:         "pdf_text",
+		SourceFileID: uuid.NullUUID{UUID: fileID, Valid: true},
+	}, nil)
+	mockQ.EXPECT().GetIngestionFile(mock.Anything, db.GetIngestionFileParams{ID: fileID}).Return(db.IngestionFile{
+		ID:          fileID,
+		Filename:    "lemon bars.pdf",
+		ContentType: "application/pdf",
+		SizeBytes:   8,
+		Data:        []byte("%PDF-1.4"),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/ingest/"+jobID.String()+"/source", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="lemon bars.pdf"`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "%PDF-1.4", rec.Body.String())
+}
+
+func TestGetIngestSource_NoFile(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	jobID := uuid.New()
+	mockQ.EXPECT().GetIngestionJob(mock.Anything, db.GetIngestionJobParams{ID: jobID}).
+		Return(db.IngestionJob{ID: jobID, Type: "text_blob"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/ingest/"+jobID.String()+"/source", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetIngestBatch_NotFound(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupRouter(t)
+
+	mockQ.EXPECT().GetIngestionBatch(mock.Anything, mock.Anything).Return(db.IngestionBatch{}, sql.ErrNoRows)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/ingest/batch/"+uuid.New().String(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
 func TestConfirmIngest_NotStaged(t *testing.T) {
 	t.Parallel()
 	mockQ, router := setupRouter(t)
 
 	jobID := uuid.New()
 	now := time.Now()
-	mockQ.EXPECT().GetIngestionJob(mock.Anything, jobID).Return(db.IngestionJob{
+	mockQ.EXPECT().GetIngestionJob(mock.Anything, db.GetIngestionJobParams{ID: jobID}).Return(db.IngestionJob{
 		ID:        jobID,
 		Type:      "text_blob",
 		RawInput:  "test",
@@ -353,3 +1127,137 @@ func TestConfirmIngest_NotStaged(t *testing.T) {
 
 	assert.Equal(t, http.StatusConflict, rec.Code)
 }
+
+// setupAuthRouter returns a router that authenticates with the API keys
+// "viewer-key", "editor-key" and "reviewer-key", all bound to household, a
+// "service-key" that may name any household and an "unscoped-key" with
+// neither.
+func setupAuthRouter(t *testing.T, household uuid.UUID) (*mocks.MockQuerier, http.Handler) {
+	t.Helper()
+	digest := func(key string) string {
+		sum := sha256.Sum256([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+	keys, err := auth.NewAPIKeys([]auth.APIKey{
+		{Subject: "vic", KeySHA256: digest("viewer-key"), Roles: []string{"viewer"}, HouseholdID: household.String()},
+		{Subject: "eddie", KeySHA256: digest("editor-key"), Roles: []string{"editor"}, HouseholdID: household.String()},
+		{Subject: "sam", KeySHA256: digest("service-key"), Roles: []string{"service", "editor"}},
+		{Subject: "nora", KeySHA256: digest("unscoped-key"), Roles: []string{"editor"}},
+		{
+			Subject:     "rita",
+			KeySHA256:   digest("reviewer-key"),
+			Roles:       []string{"reviewer"},
+			HouseholdID: household.String(),
+		},
+	})
+	require.NoError(t, err)
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, &stubExtractor{}, &stubResolver{})
+	svc.SetTransactor(transactor(t, mockQ))
+	return mockQ, NewRouter(svc, WithAuthenticators(keys))
+}
+
+func TestAuth_Roles(t *testing.T) {
+	t.Parallel()
+	_, router := setupAuthRouter(t, uuid.New())
+
+	tests := map[string]struct {
+		method, path, key string
+		want              int
+	}{
+		"no credentials":       {http.MethodGet, "/recipes", "", http.StatusUnauthorized},
+		"unknown key":          {http.MethodGet, "/recipes", "guess", http.StatusUnauthorized},
+		"viewer cannot create": {http.MethodPost, "/recipes", "viewer-key", http.StatusForbidden},
+		"viewer cannot confirm": {
+			http.MethodPost, "/recipes/ingest/" + uuid.NewString() + "/confirm", "viewer-key", http.StatusForbidden,
+		},
+		"editor cannot confirm": {
+			http.MethodPost, "/recipes/ingest/batch/" + uuid.NewString() + "/confirm", "editor-key",
+			http.StatusForbidden,
+		},
+		"reviewer cannot delete": {
+			http.MethodDelete, "/recipes/" + uuid.NewString(), "reviewer-key", http.StatusForbidden,
+		},
+		"key without a household": {http.MethodGet, "/recipes", "unscoped-key", http.StatusForbidden},
+		"healthz stays anonymous": {http.MethodGet, "/healthz", "", http.StatusOK},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(auth.APIKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestAuth_ReviewerConfirmsInOwnHousehold(t *testing.T) {
+	t.Parallel()
+	household := uuid.New()
+	mockQ, router := setupAuthRouter(t, household)
+
+	jobID := uuid.New()
+	mockQ.EXPECT().GetIngestionJob(mock.Anything, db.GetIngestionJobParams{ID: jobID, HouseholdID: household}).
+		Return(db.IngestionJob{ID: jobID, Status: "confirmed"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/recipes/ingest/"+jobID.String()+"/confirm", nil)
+	req.Header.Set(auth.APIKeyHeader, "reviewer-key")
+	req.Header.Set(tenant.Header, uuid.NewString())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code, "the key's household wins over the header")
+}
+
+func TestAuth_ServiceNamesHousehold(t *testing.T) {
+	t.Parallel()
+	mockQ, router := setupAuthRouter(t, uuid.New())
+
+	household := uuid.New()
+	jobID := uuid.New()
+	mockQ.EXPECT().GetIngestionJob(mock.Anything, db.GetIngestionJobParams{ID: jobID, HouseholdID: household}).
+		Return(db.IngestionJob{}, sql.ErrNoRows)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/ingest/"+jobID.String(), nil)
+	req.Header.Set(auth.APIKeyHeader, "service-key")
+	req.Header.Set(tenant.Header, household.String())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeleteRecipe_RecordsSubject(t *testing.T) {
+	t.Parallel()
+	household := uuid.New()
+	mockQ, router := setupAuthRouter(t, household)
+
+	id := uuid.New()
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: id, HouseholdID: household}).
+		Return(db.Recipe{ID: id}, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{RecipeID: id, HouseholdID: household}).
+		Return(nil, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{
+		RecipeID:    id,
+		HouseholdID: household,
+	}).
+		Return(nil, nil)
+	mockQ.EXPECT().InsertOutboxEvent(mock.Anything, mock.Anything).Return(db.EventOutbox{}, nil)
+	mockQ.EXPECT().SoftDeleteRecipe(mock.Anything, db.SoftDeleteRecipeParams{
+		ID:          id,
+		HouseholdID: household,
+		UpdatedBy:   sql.NullString{String: "eddie", Valid: true},
+	}).Return(db.Recipe{ID: id}, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/recipes/"+id.String(), nil)
+	req.Header.Set(auth.APIKeyHeader, "editor-key")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader carries a static API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKey describes one static key. Only the key's SHA-256 digest is stored,
+// so the keys file does not hold usable secrets.
+type APIKey struct {
+	Subject     string   `json:"subject"`
+	KeySHA256   string   `json:"key_sha256"`
+	Roles       []string `json:"roles"`
+	HouseholdID string   `json:"household_id"`
+}
+
+// APIKeys authenticates requests by the key in APIKeyHeader.
+type APIKeys struct {
+	byDigest map[[sha256.Size]byte]Principal
+}
+
+// NewAPIKeys validates keys and indexes them by digest.
+func NewAPIKeys(keys []APIKey) (*APIKeys, error) {
+	a := &APIKeys{byDigest: make(map[[sha256.Size]byte]Principal, len(keys))}
+	for i, k := range keys {
+		if k.Subject == "" {
+			return nil, fmt.Errorf("api key %d: subject is required", i)
+		}
+		raw, err := hex.DecodeString(k.KeySHA256)
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("api key %q: key_sha256 must be a hex SHA-256 digest", k.Subject)
+		}
+		p := Principal{Subject: k.Subject, Roles: parseRoles(k.Roles)}
+		if len(p.Roles) == 0 {
+			return nil, fmt.Errorf("api key %q: at least one known role is required", k.Subject)
+		}
+		if k.HouseholdID != "" {
+			household, err := uuid.Parse(k.HouseholdID)
+			if err != nil {
+				return nil, fmt.Errorf("api key %q: invalid household_id: %w", k.Subject, err)
+			}
+			p.Household = uuid.NullUUID{UUID: household, Valid: true}
+		}
+		digest := [sha256.Size]byte(raw)
+		if _, dup := a.byDigest[digest]; dup {
+			return nil, fmt.Errorf("api key %q: duplicate key", k.Subject)
+		}
+		a.byDigest[digest] = p
+	}
+	return a, nil
+}
+
+// LoadAPIKeys reads a JSON array of APIKey from path.
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read api keys: %w", err)
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parse api keys: %w", err)
+	}
+	return NewAPIKeys(keys)
+}
+
+// Authenticate implements Authenticator.
+func (a *APIKeys) Authenticate(r *http.Request) (Principal, error) {
+	key := strings.TrimSpace(r.Header.Get(APIKeyHeader))
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+	p, ok := a.byDigest[sha256.Sum256([]byte(key))]
+	if !ok {
+		return Principal{}, fmt.Errorf("%w: unknown api key", ErrInvalidCredentials)
+	}
+	return p, nil
+}
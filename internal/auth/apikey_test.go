@@ -0,0 +1,68 @@
+package auth_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
+)
+
+func digest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAPIKeys_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"subject": "ci", "key_sha256": "`+digest("ci-key")+`", "roles": ["reviewer"],
+		 "household_id": "3f1c6a8e-4a57-4d3b-9c1e-2f6b9a0d7e21"}
+	]`), 0o600))
+	keys, err := auth.LoadAPIKeys(path)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err = keys.Authenticate(req)
+	require.ErrorIs(t, err, auth.ErrNoCredentials)
+
+	req.Header.Set(auth.APIKeyHeader, "wrong")
+	_, err = keys.Authenticate(req)
+	require.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	req.Header.Set(auth.APIKeyHeader, "ci-key")
+	p, err := keys.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, "ci", p.Subject)
+	assert.Equal(t, []auth.Role{auth.RoleReviewer}, p.Roles)
+	assert.Equal(t, "3f1c6a8e-4a57-4d3b-9c1e-2f6b9a0d7e21", p.Household.UUID.String())
+}
+
+func TestNewAPIKeys_Invalid(t *testing.T) {
+	t.Parallel()
+
+	valid := auth.APIKey{Subject: "ci", KeySHA256: digest("k"), Roles: []string{"viewer"}}
+	tests := map[string][]auth.APIKey{
+		"no subject":    {{KeySHA256: digest("k"), Roles: []string{"viewer"}}},
+		"bad digest":    {{Subject: "ci", KeySHA256: "abc", Roles: []string{"viewer"}}},
+		"unknown roles": {{Subject: "ci", KeySHA256: digest("k"), Roles: []string{"owner"}}},
+		"bad household": {{Subject: "ci", KeySHA256: digest("k"), Roles: []string{"viewer"}, HouseholdID: "x"}},
+		"duplicate":     {valid, valid},
+	}
+	for name, keys := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := auth.NewAPIKeys(keys)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package auth authenticates API callers and enforces their roles. Callers
+// present either a signed JWT or a static API key; both resolve to a
+// Principal that handlers read from the request context.
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/httperr"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// Role grants access to a class of routes.
+type Role string
+
+// Roles, from least to most privileged. Editors and reviewers can also view,
+// and admins hold every role.
+const (
+	// RoleViewer reads recipes, revisions and ingest jobs.
+	RoleViewer Role = "viewer"
+	// RoleEditor creates and changes recipes and submits ingest jobs.
+	RoleEditor Role = "editor"
+	// RoleReviewer confirms staged ingest jobs.
+	RoleReviewer Role = "reviewer"
+	// RoleAdmin may do anything.
+	RoleAdmin Role = "admin"
+	// RoleService marks a trusted backend acting for many households. Its
+	// requests may name the household in the tenant.Header header.
+	RoleService Role = "service"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request does
+	// not carry the kind of credential it handles.
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is returned for credentials that are present but
+	// malformed, expired or not recognized.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject identifies the caller; it is recorded as created_by and
+	// updated_by on the rows it writes.
+	Subject string
+	Roles   []Role
+	// Household, when valid, scopes the caller's requests and takes
+	// precedence over the tenant.Header request header. Only callers with
+	// RoleService may go without one.
+	Household uuid.NullUUID
+}
+
+// Has reports whether p holds role.
+func (p Principal) Has(role Role) bool {
+	for _, held := range p.Roles {
+		switch {
+		case held == role, held == RoleAdmin:
+			return true
+		case role == RoleViewer && (held == RoleEditor || held == RoleReviewer):
+			return true
+		}
+	}
+	return false
+}
+
+// parseRoles keeps the known roles in names and drops the rest, so tokens
+// issued for several services can carry roles this one does not define.
+func parseRoles(names []string) []Role {
+	roles := make([]Role, 0, len(names))
+	for _, name := range names {
+		switch role := Role(name); role {
+		case RoleViewer, RoleEditor, RoleReviewer, RoleAdmin, RoleService:
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// Authenticator resolves the credentials on a request to a Principal. It
+// returns ErrNoCredentials when the request carries none of its kind.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+type contextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the caller authenticated by Middleware, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	return p, ok
+}
+
+// Subject returns the authenticated caller's subject, or "" when
+// authentication is disabled.
+func Subject(ctx context.Context) string {
+	p, _ := FromContext(ctx)
+	return p.Subject
+}
+
+// Middleware authenticates each request with the first authenticator that
+// finds credentials on it and rejects the request with 401 when none does or
+// the credentials are invalid. A household on the principal scopes the
+// request through the tenant package. A principal without one must hold
+// RoleService, which lets tenant.Middleware take the household from the
+// tenant.Header header; anyone else is rejected with 403, so end users can
+// never pick their household by setting the header themselves.
+func Middleware(authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, err := authenticate(r, authenticators)
+			if err != nil {
+				if !errors.Is(err, ErrNoCredentials) {
+					slog.Default().InfoContext(r.Context(), "authentication failed", "error", err)
+				}
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				httperr.Write(w, "authentication required", http.StatusUnauthorized)
+				return
+			}
+			ctx := WithPrincipal(r.Context(), p)
+			switch {
+			case p.Household.Valid:
+				ctx = tenant.WithHousehold(ctx, p.Household.UUID)
+			case !p.Has(RoleService):
+				httperr.Write(w, "credentials are not scoped to a household", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func authenticate(r *http.Request, authenticators []Authenticator) (Principal, error) {
+	for _, a := range authenticators {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return p, err
+	}
+	return Principal{}, ErrNoCredentials
+}
+
+// Require rejects requests whose principal does not hold role with 403.
+// Requests that were not authenticated are rejected with 401.
+func Require(role Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := FromContext(r.Context())
+			if !ok {
+				httperr.Write(w, "authentication required", http.StatusUnauthorized)
+				return
+			}
+			if !p.Has(role) {
+				httperr.Write(w, "this action requires the "+string(role)+" role", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
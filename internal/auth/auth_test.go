@@ -0,0 +1,152 @@
+package auth_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+type stubAuthenticator struct {
+	p   auth.Principal
+	err error
+}
+
+func (s stubAuthenticator) Authenticate(*http.Request) (auth.Principal, error) { return s.p, s.err }
+
+func TestPrincipalHas(t *testing.T) {
+	t.Parallel()
+
+	viewer := auth.Principal{Roles: []auth.Role{auth.RoleViewer}}
+	editor := auth.Principal{Roles: []auth.Role{auth.RoleEditor}}
+	reviewer := auth.Principal{Roles: []auth.Role{auth.RoleReviewer}}
+	admin := auth.Principal{Roles: []auth.Role{auth.RoleAdmin}}
+
+	assert.True(t, viewer.Has(auth.RoleViewer))
+	assert.False(t, viewer.Has(auth.RoleEditor))
+	assert.True(t, editor.Has(auth.RoleViewer))
+	assert.False(t, editor.Has(auth.RoleReviewer))
+	assert.True(t, reviewer.Has(auth.RoleViewer))
+	assert.False(t, reviewer.Has(auth.RoleEditor))
+	assert.True(t, admin.Has(auth.RoleReviewer))
+	assert.True(t, admin.Has(auth.RoleService))
+	assert.False(t, editor.Has(auth.RoleService))
+	assert.False(t, auth.Principal{}.Has(auth.RoleViewer))
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	household := uuid.New()
+	alice := auth.Principal{
+		Subject:   "alice",
+		Roles:     []auth.Role{auth.RoleViewer},
+		Household: uuid.NullUUID{UUID: household, Valid: true},
+	}
+	unscoped := func(role auth.Role) auth.Principal {
+		return auth.Principal{Subject: "alice", Roles: []auth.Role{role}}
+	}
+
+	tests := map[string]struct {
+		authenticators []auth.Authenticator
+		header         string
+		wantStatus     int
+	}{
+		"no authenticator finds credentials": {
+			[]auth.Authenticator{stubAuthenticator{err: auth.ErrNoCredentials}},
+			"",
+			http.StatusUnauthorized,
+		},
+		"invalid credentials": {
+			[]auth.Authenticator{stubAuthenticator{err: auth.ErrInvalidCredentials}, stubAuthenticator{p: alice}},
+			"",
+			http.StatusUnauthorized,
+		},
+		"falls through to the next authenticator": {
+			[]auth.Authenticator{stubAuthenticator{err: auth.ErrNoCredentials}, stubAuthenticator{p: alice}},
+			"",
+			http.StatusOK,
+		},
+		"household on the principal wins over the header": {
+			[]auth.Authenticator{stubAuthenticator{p: alice}},
+			uuid.NewString(),
+			http.StatusOK,
+		},
+		"service principal names the household in the header": {
+			[]auth.Authenticator{stubAuthenticator{p: unscoped(auth.RoleService)}},
+			household.String(),
+			http.StatusOK,
+		},
+		"admin principal names the household in the header": {
+			[]auth.Authenticator{stubAuthenticator{p: unscoped(auth.RoleAdmin)}},
+			household.String(),
+			http.StatusOK,
+		},
+		"principal without a household may not use the header": {
+			[]auth.Authenticator{stubAuthenticator{p: unscoped(auth.RoleEditor)}},
+			household.String(),
+			http.StatusForbidden,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotSubject string
+			var gotHousehold uuid.UUID
+			h := auth.Middleware(tt.authenticators...)(tenant.Middleware(false)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					gotSubject = auth.Subject(r.Context())
+					gotHousehold = tenant.FromContext(r.Context())
+				})))
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			switch tt.wantStatus {
+			case http.StatusOK:
+				assert.Equal(t, "alice", gotSubject)
+				assert.Equal(t, household, gotHousehold)
+			case http.StatusUnauthorized:
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	t.Parallel()
+
+	ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	serve := func(authn auth.Authenticator, role auth.Role) int {
+		rec := httptest.NewRecorder()
+		auth.Middleware(authn)(auth.Require(role)(ok)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+		return rec.Code
+	}
+	household := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	viewer := stubAuthenticator{p: auth.Principal{
+		Subject: "v", Roles: []auth.Role{auth.RoleViewer}, Household: household,
+	}}
+	reviewer := stubAuthenticator{p: auth.Principal{
+		Subject: "r", Roles: []auth.Role{auth.RoleReviewer}, Household: household,
+	}}
+
+	assert.Equal(t, http.StatusOK, serve(viewer, auth.RoleViewer))
+	assert.Equal(t, http.StatusForbidden, serve(viewer, auth.RoleReviewer))
+	assert.Equal(t, http.StatusOK, serve(reviewer, auth.RoleReviewer))
+	assert.Equal(t, http.StatusUnauthorized, serve(stubAuthenticator{err: errors.New("boom")}, auth.RoleViewer))
+
+	rec := httptest.NewRecorder()
+	auth.Require(auth.RoleViewer)(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "no principal without Middleware")
+}
@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// JWT claims read besides the registered ones.
+const (
+	RolesClaim     = "roles"
+	HouseholdClaim = "household_id"
+)
+
+const defaultLeeway = time.Minute
+
+// signingMethods are the algorithms tokens may be signed with. The key a
+// token selects must also match the algorithm's family, so a public key can
+// never be used as an HMAC secret.
+var signingMethods = []string{
+	"HS256", "HS384", "HS512",
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// KeySet maps key IDs to verification keys: []byte for HMAC,
+// *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey. A token without a
+// "kid" header is verified with the set's only key.
+type KeySet map[string]any
+
+// HMACKey returns a KeySet holding one shared secret.
+func HMACKey(secret []byte) KeySet {
+	return KeySet{"": secret}
+}
+
+// LoadPublicKey reads a PEM-encoded public key from path.
+func LoadPublicKey(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key file is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	return KeySet{"": key}, nil
+}
+
+// LoadJWKS reads a JSON Web Key Set from path. Encryption keys are skipped.
+func LoadJWKS(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JSON Web Key Set. Keys are decoded one at a time so
+// that an encryption key this service has no use for cannot fail the set.
+func ParseJWKS(data []byte) (KeySet, error) {
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+	keys := KeySet{}
+	for i, raw := range set.Keys {
+		var use struct {
+			Use string `json:"use"`
+		}
+		if err := json.Unmarshal(raw, &use); err != nil {
+			return nil, fmt.Errorf("jwks key %d: %w", i, err)
+		}
+		if use.Use == "enc" {
+			continue
+		}
+		var k jose.JSONWebKey
+		if err := k.UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("jwks key %d: %w", i, err)
+		}
+		if _, dup := keys[k.KeyID]; dup {
+			return nil, fmt.Errorf("jwks key %d: duplicate kid %q", i, k.KeyID)
+		}
+		// Only the public half of an asymmetric key is kept; symmetric keys
+		// have none and stay as they are.
+		key := k.Key
+		if pub := k.Public(); pub.Key != nil {
+			key = pub.Key
+		}
+		keys[k.KeyID] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no signing keys")
+	}
+	return keys, nil
+}
+
+// JWTOption configures a JWTVerifier.
+type JWTOption func(*JWTVerifier)
+
+// WithIssuer requires tokens to carry iss. Empty values are ignored.
+func WithIssuer(iss string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.issuer = iss
+	}
+}
+
+// WithAudience requires tokens to list aud. Empty values are ignored.
+func WithAudience(aud string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.audience = aud
+	}
+}
+
+// JWTVerifier authenticates requests by a signed JWT in the Authorization
+// header. The subject comes from "sub", roles from RolesClaim and the
+// optional household from HouseholdClaim. Tokens must expire.
+type JWTVerifier struct {
+	keys     KeySet
+	issuer   string
+	audience string
+	parser   *jwt.Parser
+}
+
+// NewJWTVerifier returns a verifier for tokens signed by keys.
+func NewJWTVerifier(keys KeySet, opts ...JWTOption) (*JWTVerifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one verification key is required")
+	}
+	v := &JWTVerifier{keys: keys}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(defaultLeeway),
+	}
+	if v.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(v.audience))
+	}
+	v.parser = jwt.NewParser(parserOpts...)
+	return v, nil
+}
+
+// Authenticate implements Authenticator.
+func (v *JWTVerifier) Authenticate(r *http.Request) (Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return Principal{}, ErrNoCredentials
+	}
+	p, err := v.Verify(token)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	return p, nil
+}
+
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Roles     []string `json:"roles"`
+	Household string   `json:"household_id"`
+}
+
+// Verify checks token's signature and claims and returns its principal.
+func (v *JWTVerifier) Verify(token string) (Principal, error) {
+	var claims jwtClaims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.keyFunc); err != nil {
+		return Principal{}, err
+	}
+	if claims.Subject == "" {
+		return Principal{}, errors.New("token has no subject")
+	}
+
+	p := Principal{Subject: claims.Subject, Roles: parseRoles(claims.Roles)}
+	if claims.Household != "" {
+		household, err := uuid.Parse(claims.Household)
+		if err != nil {
+			return Principal{}, fmt.Errorf("invalid %s claim: %w", HouseholdClaim, err)
+		}
+		p.Household = uuid.NullUUID{UUID: household, Valid: true}
+	}
+	return p, nil
+}
+
+// keyFunc selects the key named by the token's "kid" header.
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	// A static key has no ID of its own and verifies any token.
+	if key, ok := v.keys[""]; ok && len(v.keys) == 1 {
+		return key, nil
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
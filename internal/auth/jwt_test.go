@@ -0,0 +1,234 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
+)
+
+var b64 = base64.RawURLEncoding
+
+// signJWT builds a compact JWT over claims. key is the private key or HMAC
+// secret matching alg.
+func signJWT(t *testing.T, alg, kid string, key any, claims map[string]any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), jwt.MapClaims(claims))
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":   "alice",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iss":   "https://id.example.com",
+		"aud":   []string{"recipes", "pantry"},
+		"roles": []string{"editor", "pantry:owner"},
+	}
+}
+
+func TestJWTVerifier_Algorithms(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	secret := []byte("correct horse battery staple")
+
+	tests := []struct {
+		alg     string
+		signKey any
+		keys    auth.KeySet
+	}{
+		{"HS256", secret, auth.HMACKey(secret)},
+		{"RS256", rsaKey, auth.KeySet{"": &rsaKey.PublicKey}},
+		{"ES256", ecKey, auth.KeySet{"": &ecKey.PublicKey}},
+		{"EdDSA", edKey, auth.KeySet{"": edPub}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			t.Parallel()
+
+			v, err := auth.NewJWTVerifier(
+				tt.keys,
+				auth.WithIssuer("https://id.example.com"),
+				auth.WithAudience("recipes"),
+			)
+			require.NoError(t, err)
+
+			p, err := v.Verify(signJWT(t, tt.alg, "", tt.signKey, validClaims()))
+			require.NoError(t, err)
+			assert.Equal(t, "alice", p.Subject)
+			assert.Equal(t, []auth.Role{auth.RoleEditor}, p.Roles, "unknown roles are dropped")
+			assert.False(t, p.Household.Valid)
+		})
+	}
+}
+
+func TestJWTVerifier_Rejects(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("correct horse battery staple")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	v, err := auth.NewJWTVerifier(auth.HMACKey(secret), auth.WithAudience("recipes"))
+	require.NoError(t, err)
+	rsaVerifier, err := auth.NewJWTVerifier(auth.KeySet{"": &rsaKey.PublicKey})
+	require.NoError(t, err)
+
+	with := func(key string, value any) map[string]any {
+		c := validClaims()
+		if value == nil {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+		return c
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		verifier *auth.JWTVerifier
+		token    string
+	}{
+		"wrong secret":     {v, signJWT(t, "HS256", "", []byte("guess"), validClaims())},
+		"expired":          {v, signJWT(t, "HS256", "", secret, with("exp", time.Now().Add(-time.Hour).Unix()))},
+		"no expiry":        {v, signJWT(t, "HS256", "", secret, with("exp", nil))},
+		"not yet valid":    {v, signJWT(t, "HS256", "", secret, with("nbf", time.Now().Add(time.Hour).Unix()))},
+		"no subject":       {v, signJWT(t, "HS256", "", secret, with("sub", nil))},
+		"wrong audience":   {v, signJWT(t, "HS256", "", secret, with("aud", "billing"))},
+		"bad household":    {v, signJWT(t, "HS256", "", secret, with("household_id", "kitchen"))},
+		"malformed":        {v, "not-a-jwt"},
+		"alg none":         {v, unsignedJWT(t)},
+		"public key hmac":  {rsaVerifier, signJWT(t, "HS256", "", pubDER, validClaims())},
+		"tampered payload": {v, tamper(t, signJWT(t, "HS256", "", secret, validClaims()))},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tt.verifier.Verify(tt.token)
+			require.Error(t, err)
+		})
+	}
+}
+
+func unsignedJWT(t *testing.T) string {
+	t.Helper()
+	c, err := json.Marshal(validClaims())
+	require.NoError(t, err)
+	return b64.EncodeToString([]byte(`{"alg":"none"}`)) + "." + b64.EncodeToString(c) + "."
+}
+
+// tamper swaps the token's payload for one granting admin.
+func tamper(t *testing.T, token string) string {
+	t.Helper()
+	c := validClaims()
+	c["roles"] = []string{"admin"}
+	payload, err := json.Marshal(c)
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+	return parts[0] + "." + b64.EncodeToString(payload) + "." + parts[2]
+}
+
+func TestJWTVerifier_HouseholdClaim(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("s3cret")
+	v, err := auth.NewJWTVerifier(auth.HMACKey(secret))
+	require.NoError(t, err)
+
+	household := uuid.New()
+	c := validClaims()
+	c["household_id"] = household.String()
+	p, err := v.Verify(signJWT(t, "HS256", "", secret, c))
+	require.NoError(t, err)
+	assert.Equal(t, uuid.NullUUID{UUID: household, Valid: true}, p.Household)
+}
+
+func TestLoadJWKS(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecPub, err := ecKey.PublicKey.Bytes()
+	require.NoError(t, err)
+	jwks := map[string]any{"keys": []map[string]string{
+		{
+			"kty": "RSA", "kid": "rsa-1", "use": "sig",
+			"n": b64.EncodeToString(rsaKey.N.Bytes()),
+			"e": b64.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+		{
+			"kty": "EC", "kid": "ec-1", "crv": "P-256",
+			"x": b64.EncodeToString(ecPub[1:33]), "y": b64.EncodeToString(ecPub[33:]),
+		},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc"},
+	}}
+	data, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	keys, err := auth.LoadJWKS(path)
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	v, err := auth.NewJWTVerifier(keys)
+	require.NoError(t, err)
+	_, err = v.Verify(signJWT(t, "RS256", "rsa-1", rsaKey, validClaims()))
+	require.NoError(t, err)
+	_, err = v.Verify(signJWT(t, "ES256", "ec-1", ecKey, validClaims()))
+	require.NoError(t, err)
+	_, err = v.Verify(signJWT(t, "RS256", "ec-1", rsaKey, validClaims()))
+	require.Error(t, err, "key type must match the algorithm")
+	_, err = v.Verify(signJWT(t, "RS256", "", rsaKey, validClaims()))
+	require.Error(t, err, "kid is required with several keys")
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	t.Parallel()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	keys, err := auth.LoadPublicKey(path)
+	require.NoError(t, err)
+	v, err := auth.NewJWTVerifier(keys)
+	require.NoError(t, err)
+	_, err = v.Verify(signJWT(t, "ES256", "any-kid", ecKey, validClaims()))
+	require.NoError(t, err)
+}
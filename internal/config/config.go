@@ -0,0 +1,368 @@
+// Package config loads the service configuration from an optional YAML file
+// and the environment, validates it and prints it with secrets redacted.
+//
+// Every setting is a field of Config whose struct tags document it:
+//
+//   - yaml: key in the config file, under its section
+//   - env: environment variable, which overrides the file
+//   - default: value used when neither sets it
+//   - required, min, max, oneof: validation rules
+//   - secret: the value is redacted when printed
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the path of the optional
+// YAML config file.
+const FileEnv = "CONFIG_FILE"
+
+// Config is the service configuration.
+type Config struct {
+	HTTP       HTTP       `yaml:"http"`
+	Database   Database   `yaml:"database"`
+	Dictionary Dictionary `yaml:"dictionary"`
+	RabbitMQ   RabbitMQ   `yaml:"rabbitmq"`
+	Auth       Auth       `yaml:"auth"`
+	Features   Features   `yaml:"features"`
+	Ingest     Ingest     `yaml:"ingest"`
+	Trash      Trash      `yaml:"trash"`
+	Log        Log        `yaml:"log"`
+	Tracing    Tracing    `yaml:"tracing"`
+}
+
+// HTTP configures the API server.
+type HTTP struct {
+	// Port is the listen port.
+	Port int `yaml:"port" env:"PORT" default:"8080" min:"1" max:"65535"`
+	// ReadHeaderTimeout bounds reading request headers.
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" default:"10s" min:"1"`
+	// ReadTimeout bounds reading a whole request, including uploads.
+	ReadTimeout time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" default:"60s" min:"1"`
+	// WriteTimeout bounds handling a request and writing its response.
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" default:"60s" min:"1"`
+	// RequestTimeout is the deadline of each API request's context, and so
+	// of every transaction and query it runs. It must be below WriteTimeout,
+	// leaving time to answer a request that ran out of it. 0 disables it.
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"HTTP_REQUEST_TIMEOUT" default:"50s" min:"0"`
+	// IdleTimeout is how long idle keep-alive connections stay open.
+	IdleTimeout time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" default:"120s" min:"1"`
+	// ShutdownTimeout bounds how long SIGTERM waits for in-flight requests
+	// and background work.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" default:"25s" min:"1"`
+}
+
+// Database configures the PostgreSQL connection pool.
+type Database struct {
+	// URL is the recipe_db connection string.
+	URL string `yaml:"url" env:"DB_URL" required:"true" secret:"true"`
+	// MaxOpenConns caps the connections open at once.
+	MaxOpenConns int `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"25" min:"1"`
+	// MaxIdleConns caps the idle connections kept in the pool.
+	MaxIdleConns int `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"10" min:"0"`
+	// ConnMaxLifetime closes connections older than this, so the pool
+	// follows failovers and rebalanced poolers. 0 keeps them forever.
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"30m" min:"0"`
+	// ConnMaxIdleTime closes connections idle for longer than this. 0 keeps
+	// them.
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" default:"5m" min:"0"`
+	// QueryTimeout is the deadline of each query, an inner bound under the
+	// deadline of the request or job running it. 0 disables it.
+	QueryTimeout time.Duration `yaml:"query_timeout" env:"DB_QUERY_TIMEOUT" default:"10s" min:"0"`
+	// StatementTimeout is set as statement_timeout on every session, so the
+	// server also gives up on statements whose client has gone. 0 keeps the
+	// server's setting.
+	StatementTimeout time.Duration `yaml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT" default:"30s" min:"0"`
+	// MigrateOnStart applies pending migrations before serving. Turn it off
+	// when migrations run separately, with "recipes migrate up".
+	MigrateOnStart bool `yaml:"migrate_on_start" env:"MIGRATE_ON_START" default:"true"`
+}
+
+// Dictionary configures the Ingredient Dictionary client.
+type Dictionary struct {
+	// URL is the Dictionary's base URL.
+	URL string `yaml:"url" env:"DICTIONARY_URL" required:"true"`
+	// Timeout bounds each Dictionary request.
+	Timeout time.Duration `yaml:"timeout" env:"DICTIONARY_TIMEOUT" default:"10s" min:"1"`
+	// CheckReady adds Dictionary reachability to /readyz.
+	CheckReady bool `yaml:"check_ready" env:"READYZ_CHECK_DICTIONARY" default:"false"`
+}
+
+// RabbitMQ configures the event bus.
+type RabbitMQ struct {
+	// URL is the broker URL. Without it events stay in-process.
+	URL string `yaml:"url" env:"RABBITMQ_URL" secret:"true"`
+	// Exchange is the topic exchange events are published to.
+	Exchange string `yaml:"exchange" env:"RABBITMQ_EXCHANGE" default:"woodpantry.topic"`
+	// RecipeImportedQueue is the queue the recipe.imported subscriber
+	// consumes.
+	RecipeImportedQueue string `yaml:"recipe_imported_queue" env:"RABBITMQ_QUEUE" default:"recipes.recipe-imported"`
+	// Prefetch caps the unacked recipe.imported deliveries held at once.
+	Prefetch int `yaml:"prefetch" env:"RABBITMQ_PREFETCH" default:"16" min:"1"`
+	// Workers is the number of concurrent recipe.imported handlers.
+	Workers int `yaml:"workers" env:"RABBITMQ_WORKERS" default:"4" min:"1"`
+	// DeadLetterQueue is the queue "recipes dlq replay" moves messages back
+	// from. The service does not declare it; a broker policy dead-letters
+	// into it once a quorum queue's delivery-limit is reached.
+	DeadLetterQueue string `yaml:"dead_letter_queue" env:"RABBITMQ_DLQ" default:"recipes.recipe-imported.dlq"`
+}
+
+// Auth configures API authentication. With no keys configured the API is
+// served without authentication.
+type Auth struct {
+	// JWKSFile holds the keys that verify bearer tokens.
+	JWKSFile string `yaml:"jwks_file" env:"JWT_JWKS_FILE"`
+	// PublicKeyFile is a PEM public key that verifies bearer tokens.
+	PublicKeyFile string `yaml:"public_key_file" env:"JWT_PUBLIC_KEY_FILE"`
+	// HMACSecret verifies HS256/384/512 bearer tokens.
+	HMACSecret string `yaml:"hmac_secret" env:"JWT_HMAC_SECRET" secret:"true"`
+	// Issuer is the required iss claim, if set.
+	Issuer string `yaml:"issuer" env:"JWT_ISSUER"`
+	// Audience is the required aud claim, if set.
+	Audience string `yaml:"audience" env:"JWT_AUDIENCE"`
+	// APIKeysFile is a JSON file of static API keys.
+	APIKeysFile string `yaml:"api_keys_file" env:"API_KEYS_FILE"`
+}
+
+// Features toggles optional behaviour.
+type Features struct {
+	// IngestPrefill pre-fills pending ingest jobs with the local
+	// extractor's result.
+	IngestPrefill bool `yaml:"ingest_prefill" env:"INGEST_PREFILL" default:"false"`
+	// RequireHousehold rejects requests without an X-Household-ID header
+	// instead of using the default household.
+	RequireHousehold bool `yaml:"require_household" env:"REQUIRE_HOUSEHOLD" default:"false"`
+}
+
+// Ingest configures fetching the URLs of ingest batches.
+type Ingest struct {
+	// ScrapeWorkers is the number of a batch's URLs fetched at once.
+	ScrapeWorkers int `yaml:"scrape_workers" env:"INGEST_SCRAPE_WORKERS" default:"4" min:"1"`
+	// BatchTimeout bounds fetching all of a batch's URLs. URLs not fetched
+	// by then are marked failed.
+	BatchTimeout time.Duration `yaml:"batch_timeout" env:"INGEST_BATCH_TIMEOUT" default:"10m" min:"1"`
+}
+
+// Trash configures soft-deleted recipes.
+type Trash struct {
+	// Retention is how long deleted recipes stay in the trash.
+	Retention time.Duration `yaml:"retention" env:"TRASH_RETENTION" default:"720h" min:"1"`
+}
+
+// Log configures logging.
+type Log struct {
+	// Level is the minimum level logged.
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"info" oneof:"debug info warn error"`
+}
+
+// Tracing configures OpenTelemetry. The exporter itself reads the standard
+// OTEL_EXPORTER_OTLP_* and OTEL_SERVICE_NAME variables.
+type Tracing struct {
+	// Exporter selects where spans are sent.
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" default:"none" oneof:"none otlp stdout console"`
+}
+
+// Load reads the file named by FileEnv, if set, then the environment, and
+// validates the result. Every problem found is reported in the returned
+// error, alongside the configuration as far as it could be loaded.
+func Load() (*Config, error) {
+	return load(os.LookupEnv)
+}
+
+func load(lookup func(string) (string, bool)) (*Config, error) {
+	cfg := &Config{}
+	settings := cfg.settings()
+	var errs []error
+	for _, s := range settings {
+		if s.def != "" {
+			if err := s.set(s.def); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid default: %w", s.key, err))
+			}
+		}
+	}
+
+	if path, _ := lookup(FileEnv); path != "" {
+		if err := cfg.readFile(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, s := range settings {
+		if v, _ := lookup(s.env); v != "" {
+			if err := s.set(v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+
+	for _, s := range settings {
+		errs = append(errs, s.validate()...)
+	}
+	errs = append(errs, cfg.validate()...)
+	return cfg, errors.Join(errs...)
+}
+
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// validate checks rules that span several settings.
+func (c *Config) validate() []error {
+	var errs []error
+	jwtSources := 0
+	for _, v := range []string{c.Auth.JWKSFile, c.Auth.PublicKeyFile, c.Auth.HMACSecret} {
+		if v != "" {
+			jwtSources++
+		}
+	}
+	if jwtSources > 1 {
+		errs = append(errs, errors.New("set only one of JWT_JWKS_FILE, JWT_PUBLIC_KEY_FILE and JWT_HMAC_SECRET"))
+	}
+	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)",
+			c.Database.MaxIdleConns, c.Database.MaxOpenConns))
+	}
+	if c.HTTP.RequestTimeout >= c.HTTP.WriteTimeout {
+		errs = append(errs, fmt.Errorf("HTTP_REQUEST_TIMEOUT (%s) must be below HTTP_WRITE_TIMEOUT (%s)",
+			c.HTTP.RequestTimeout, c.HTTP.WriteTimeout))
+	}
+	return errs
+}
+
+// setting is one leaf field of Config with its tags.
+type setting struct {
+	section, key string
+	env, def     string
+	required     bool
+	secret       bool
+	min, max     string
+	oneof        []string
+	value        reflect.Value
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// settings lists every setting of c in declaration order. The values point
+// into c.
+func (c *Config) settings() []setting {
+	var out []setting
+	root := reflect.ValueOf(c).Elem()
+	for i := range root.NumField() {
+		section := root.Type().Field(i)
+		group := root.Field(i)
+		for j := range group.NumField() {
+			f := group.Type().Field(j)
+			out = append(out, setting{
+				section:  section.Tag.Get("yaml"),
+				key:      section.Tag.Get("yaml") + "." + f.Tag.Get("yaml"),
+				env:      f.Tag.Get("env"),
+				def:      f.Tag.Get("default"),
+				required: f.Tag.Get("required") == "true",
+				secret:   f.Tag.Get("secret") == "true",
+				min:      f.Tag.Get("min"),
+				max:      f.Tag.Get("max"),
+				oneof:    strings.Fields(f.Tag.Get("oneof")),
+				value:    group.Field(j),
+			})
+		}
+	}
+	return out
+}
+
+func (s setting) set(v string) error {
+	switch {
+	case s.value.Type() == durationType:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 30s or 720h: %w", err)
+		}
+		s.value.SetInt(int64(d))
+	case s.value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("must be an integer: %w", err)
+		}
+		s.value.SetInt(int64(n))
+	case s.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("must be a boolean: %w", err)
+		}
+		s.value.SetBool(b)
+	case s.value.Kind() == reflect.String:
+		s.value.SetString(v)
+	default:
+		return fmt.Errorf("unsupported setting type %s", s.value.Type())
+	}
+	return nil
+}
+
+func (s setting) validate() []error {
+	var errs []error
+	name := s.env + " (" + s.key + ")"
+	if s.required && s.value.IsZero() {
+		errs = append(errs, fmt.Errorf("%s is required", name))
+	}
+	if s.value.Kind() == reflect.Int || s.value.Kind() == reflect.Int64 {
+		n := s.value.Int()
+		if limit, err := strconv.ParseInt(s.min, 10, 64); err == nil && n < limit {
+			errs = append(errs, fmt.Errorf("%s must be at least %s, got %s", name, s.format(limit), s.format(n)))
+		}
+		if limit, err := strconv.ParseInt(s.max, 10, 64); err == nil && n > limit {
+			errs = append(errs, fmt.Errorf("%s must be at most %s, got %s", name, s.format(limit), s.format(n)))
+		}
+	}
+	if len(s.oneof) > 0 {
+		v := strings.ToLower(s.value.String())
+		found := false
+		for _, allowed := range s.oneof {
+			found = found || v == allowed
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("%s must be one of %s, got %q",
+				name, strings.Join(s.oneof, ", "), s.value.String()))
+		}
+	}
+	return errs
+}
+
+// format renders n, a value or limit of s, the way it would be configured.
+func (s setting) format(n int64) string {
+	if s.value.Type() == durationType {
+		return formatDuration(time.Duration(n))
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// formatDuration drops the zero minutes and seconds time.Duration.String
+// adds, so 720h prints as "720h" rather than "720h0m0s".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
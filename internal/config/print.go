@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "<redacted>"
+
+// Write prints c as a YAML config file with secrets redacted. Each setting
+// is annotated with its environment variable.
+func (c *Config) Write(w io.Writer) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	var section *yaml.Node
+	for _, s := range c.settings() {
+		if section == nil || doc.Content[len(doc.Content)-2].Value != s.section {
+			section = &yaml.Node{Kind: yaml.MappingNode}
+			doc.Content = append(doc.Content, scalar(s.section), section)
+		}
+		key := scalar(s.key[len(s.section)+1:])
+		value := scalar(s.display())
+		if s.value.Kind() == reflect.String {
+			value.Style = yaml.DoubleQuotedStyle
+		}
+		value.LineComment = s.env
+		section.Content = append(section.Content, key, value)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	return enc.Close()
+}
+
+func scalar(v string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: v}
+}
+
+// display renders the value of s as it would be configured. Secrets that
+// are URLs keep everything but their password, which is shown as "xxxxx"
+// as url.URL.Redacted does; other secrets are hidden.
+func (s setting) display() string {
+	switch {
+	case s.value.Type() == durationType:
+		return s.format(s.value.Int())
+	case s.value.Kind() == reflect.Int:
+		return strconv.FormatInt(s.value.Int(), 10)
+	case s.value.Kind() == reflect.Bool:
+		return strconv.FormatBool(s.value.Bool())
+	}
+	v := s.value.String()
+	if !s.secret || v == "" {
+		return v
+	}
+	u, err := url.Parse(v)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return redacted
+	}
+	if q := u.Query(); q.Has("password") {
+		q.Set("password", "xxxxx")
+		u.RawQuery = q.Encode()
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,87 @@
+//go:build ignore
+
+// gen_hooks writes hooks_gen.go, which implements every Querier method on
+// hookedQuerier. It runs through go generate after sqlc.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "querier.go", nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var iface *ast.InterfaceType
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Name == "Querier" {
+			iface, _ = spec.Type.(*ast.InterfaceType)
+		}
+		return iface == nil
+	})
+	if iface == nil {
+		log.Fatal("Querier not found")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_hooks.go. DO NOT EDIT.\n\npackage db\n\n")
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			buf.WriteString(node(fset, gen) + "\n")
+		}
+	}
+
+	for _, method := range iface.Methods.List {
+		name := method.Names[0].Name
+		fn := method.Type.(*ast.FuncType)
+
+		var params, args []string
+		for _, p := range fn.Params.List {
+			for _, n := range p.Names {
+				params = append(params, n.Name+" "+node(fset, p.Type))
+				args = append(args, n.Name)
+			}
+		}
+		var results []string
+		for _, r := range fn.Results.List {
+			results = append(results, node(fset, r.Type))
+		}
+		call := fmt.Sprintf("q.next.%s(%s)", name, strings.Join(args, ", "))
+
+		fmt.Fprintf(&buf, "\nfunc (q *hookedQuerier) %s(%s) (%s) {\n\tctx, done := q.hook(ctx, %q)\n",
+			name, strings.Join(params, ", "), strings.Join(results, ", "), name)
+		if len(results) == 1 {
+			fmt.Fprintf(&buf, "\terr := %s\n\tdone(err)\n\treturn err\n}\n", call)
+		} else {
+			fmt.Fprintf(&buf, "\tr, err := %s\n\tdone(err)\n\treturn r, err\n}\n", call)
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("format: %v\n%s", err, buf.Bytes())
+	}
+	if err := os.WriteFile("hooks_gen.go", src, 0o644); err != nil { //nolint:gosec // generated source.
+		log.Fatal(err)
+	}
+}
+
+func node(fset *token.FileSet, n ast.Node) string {
+	var b bytes.Buffer
+	if err := printer.Fprint(&b, fset, n); err != nil {
+		log.Fatal(err)
+	}
+	return b.String()
+}
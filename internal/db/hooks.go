@@ -0,0 +1,21 @@
+package db
+
+import "context"
+
+//go:generate go run gen_hooks.go
+
+// QueryHook observes a query. It is called with the sqlc query name before
+// the query runs and returns the context to run it with and a function that
+// receives the query's error once it has finished.
+type QueryHook func(ctx context.Context, query string) (context.Context, func(err error))
+
+// WithQueryHook wraps q so that hook observes every call.
+func WithQueryHook(q Querier, hook QueryHook) Querier {
+	return &hookedQuerier{next: q, hook: hook}
+}
+
+// hookedQuerier is implemented in hooks_gen.go, one method per Querier method.
+type hookedQuerier struct {
+	next Querier
+	hook QueryHook
+}
@@ -7,24 +7,75 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 
 	"github.com/google/uuid"
 )
 
+const countIngestionJobsByStatus = `-- name: CountIngestionJobsByStatus :many
+SELECT status, count(*) AS jobs FROM ingestion_jobs GROUP BY status ORDER BY status
+`
+
+type CountIngestionJobsByStatusRow struct {
+	Status string
+	Jobs   int64
+}
+
+// Used by the metrics collector, which reports across all households.
+func (q *Queries) CountIngestionJobsByStatus(ctx context.Context) ([]CountIngestionJobsByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, countIngestionJobsByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountIngestionJobsByStatusRow
+	for rows.Next() {
+		var i CountIngestionJobsByStatusRow
+		if err := rows.Scan(
+			&i.Status,
+			&i.Jobs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createIngestionJob = `-- name: CreateIngestionJob :one
-INSERT INTO ingestion_jobs (type, raw_input)
-VALUES ($1, $2)
-RETURNING id, type, raw_input, status, staged_data, created_at
+INSERT INTO ingestion_jobs (type, raw_input, batch_id, batch_position, source_file_id, household_id, created_by, updated_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
 `
 
 type CreateIngestionJobParams struct {
-	Type     string
-	RawInput string
+	Type          string
+	RawInput      string
+	BatchID       uuid.NullUUID
+	BatchPosition sql.NullInt32
+	SourceFileID  uuid.NullUUID
+	HouseholdID   uuid.UUID
+	CreatedBy     sql.NullString
 }
 
 func (q *Queries) CreateIngestionJob(ctx context.Context, arg CreateIngestionJobParams) (IngestionJob, error) {
-	row := q.db.QueryRowContext(ctx, createIngestionJob, arg.Type, arg.RawInput)
+	row := q.db.QueryRowContext(ctx, createIngestionJob,
+		arg.Type,
+		arg.RawInput,
+		arg.BatchID,
+		arg.BatchPosition,
+		arg.SourceFileID,
+		arg.HouseholdID,
+		arg.CreatedBy,
+	)
 	var i IngestionJob
 	err := row.Scan(
 		&i.ID,
@@ -33,17 +84,31 @@ func (q *Queries) CreateIngestionJob(ctx context.Context, arg CreateIngestionJob
 		&i.Status,
 		&i.StagedData,
 		&i.CreatedAt,
+		&i.BatchID,
+		&i.BatchPosition,
+		&i.SourceFileID,
+		&i.Duplicates,
+		&i.RecipeID,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.UpdatedBy,
 	)
 	return i, err
 }
 
 const getIngestionJob = `-- name: GetIngestionJob :one
-SELECT id, type, raw_input, status, staged_data, created_at
-FROM ingestion_jobs WHERE id = $1
+SELECT id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+FROM ingestion_jobs WHERE id = $1 AND household_id = $2
 `
 
-func (q *Queries) GetIngestionJob(ctx context.Context, id uuid.UUID) (IngestionJob, error) {
-	row := q.db.QueryRowContext(ctx, getIngestionJob, id)
+type GetIngestionJobParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) GetIngestionJob(ctx context.Context, arg GetIngestionJobParams) (IngestionJob, error) {
+	row := q.db.QueryRowContext(ctx, getIngestionJob, arg.ID, arg.HouseholdID)
 	var i IngestionJob
 	err := row.Scan(
 		&i.ID,
@@ -52,24 +117,339 @@ func (q *Queries) GetIngestionJob(ctx context.Context, id uuid.UUID) (IngestionJ
 		&i.Status,
 		&i.StagedData,
 		&i.CreatedAt,
+		&i.BatchID,
+		&i.BatchPosition,
+		&i.SourceFileID,
+		&i.Duplicates,
+		&i.RecipeID,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.UpdatedBy,
 	)
 	return i, err
 }
 
+const getIngestionJobHousehold = `-- name: GetIngestionJobHousehold :one
+SELECT household_id FROM ingestion_jobs WHERE id = $1
+`
+
+// Used by the recipe.imported handler, which only knows the job ID.
+func (q *Queries) GetIngestionJobHousehold(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getIngestionJobHousehold, id)
+	var householdID uuid.UUID
+	err := row.Scan(&householdID)
+	return householdID, err
+}
+
+const listIngestionJobs = `-- name: ListIngestionJobs :many
+SELECT id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+FROM ingestion_jobs
+WHERE household_id = $1::uuid
+  AND ($2::text = '' OR status = $2::text)
+ORDER BY created_at DESC, id
+LIMIT $3::int
+`
+
+type ListIngestionJobsParams struct {
+	HouseholdID uuid.UUID
+	Status      string
+	MaxJobs     int32
+}
+
+// An empty status lists jobs in every status.
+func (q *Queries) ListIngestionJobs(ctx context.Context, arg ListIngestionJobsParams) ([]IngestionJob, error) {
+	rows, err := q.db.QueryContext(ctx, listIngestionJobs, arg.HouseholdID, arg.Status, arg.MaxJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IngestionJob
+	for rows.Next() {
+		var i IngestionJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.RawInput,
+			&i.Status,
+			&i.StagedData,
+			&i.CreatedAt,
+			&i.BatchID,
+			&i.BatchPosition,
+			&i.SourceFileID,
+			&i.Duplicates,
+			&i.RecipeID,
+			&i.HouseholdID,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIngestionJobsByBatch = `-- name: ListIngestionJobsByBatch :many
+SELECT id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+FROM ingestion_jobs
+WHERE batch_id = $1 AND household_id = $2
+ORDER BY batch_position
+`
+
+type ListIngestionJobsByBatchParams struct {
+	BatchID     uuid.NullUUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) ListIngestionJobsByBatch(ctx context.Context, arg ListIngestionJobsByBatchParams) ([]IngestionJob, error) {
+	rows, err := q.db.QueryContext(ctx, listIngestionJobsByBatch, arg.BatchID, arg.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IngestionJob
+	for rows.Next() {
+		var i IngestionJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.RawInput,
+			&i.Status,
+			&i.StagedData,
+			&i.CreatedAt,
+			&i.BatchID,
+			&i.BatchPosition,
+			&i.SourceFileID,
+			&i.Duplicates,
+			&i.RecipeID,
+			&i.HouseholdID,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markIngestionJobConfirmed = `-- name: MarkIngestionJobConfirmed :one
+UPDATE ingestion_jobs
+SET status = 'confirmed', recipe_id = $2, updated_by = $3
+WHERE id = $1 AND household_id = $4 AND status = 'staged'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+`
+
+type MarkIngestionJobConfirmedParams struct {
+	ID          uuid.UUID
+	RecipeID    uuid.NullUUID
+	UpdatedBy   sql.NullString
+	HouseholdID uuid.UUID
+}
+
+// Only a staged job can be confirmed; no row means it was settled meanwhile.
+func (q *Queries) MarkIngestionJobConfirmed(ctx context.Context, arg MarkIngestionJobConfirmedParams) (IngestionJob, error) {
+	row := q.db.QueryRowContext(ctx, markIngestionJobConfirmed,
+		arg.ID,
+		arg.RecipeID,
+		arg.UpdatedBy,
+		arg.HouseholdID,
+	)
+	var i IngestionJob
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.RawInput,
+		&i.Status,
+		&i.StagedData,
+		&i.CreatedAt,
+		&i.BatchID,
+		&i.BatchPosition,
+		&i.SourceFileID,
+		&i.Duplicates,
+		&i.RecipeID,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const prefillIngestionJob = `-- name: PrefillIngestionJob :one
+UPDATE ingestion_jobs
+SET staged_data = $2
+WHERE id = $1 AND household_id = $3 AND status = 'pending'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+`
+
+type PrefillIngestionJobParams struct {
+	ID          uuid.UUID
+	StagedData  *json.RawMessage
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) PrefillIngestionJob(ctx context.Context, arg PrefillIngestionJobParams) (IngestionJob, error) {
+	row := q.db.QueryRowContext(ctx, prefillIngestionJob, arg.ID, arg.StagedData, arg.HouseholdID)
+	var i IngestionJob
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.RawInput,
+		&i.Status,
+		&i.StagedData,
+		&i.CreatedAt,
+		&i.BatchID,
+		&i.BatchPosition,
+		&i.SourceFileID,
+		&i.Duplicates,
+		&i.RecipeID,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const rejectIngestionJob = `-- name: RejectIngestionJob :one
+UPDATE ingestion_jobs
+SET status = 'rejected', updated_by = $2
+WHERE id = $1 AND household_id = $3 AND status IN ('pending', 'staged', 'failed')
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+`
+
+type RejectIngestionJobParams struct {
+	ID          uuid.UUID
+	UpdatedBy   sql.NullString
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) RejectIngestionJob(ctx context.Context, arg RejectIngestionJobParams) (IngestionJob, error) {
+	row := q.db.QueryRowContext(ctx, rejectIngestionJob, arg.ID, arg.UpdatedBy, arg.HouseholdID)
+	var i IngestionJob
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.RawInput,
+		&i.Status,
+		&i.StagedData,
+		&i.CreatedAt,
+		&i.BatchID,
+		&i.BatchPosition,
+		&i.SourceFileID,
+		&i.Duplicates,
+		&i.RecipeID,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const repointIngestionJobs = `-- name: RepointIngestionJobs :execrows
+UPDATE ingestion_jobs
+SET recipe_id = $1::uuid
+WHERE recipe_id = $2::uuid AND household_id = $3::uuid
+`
+
+type RepointIngestionJobsParams struct {
+	TargetID    uuid.UUID
+	SourceID    uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) RepointIngestionJobs(ctx context.Context, arg RepointIngestionJobsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, repointIngestionJobs, arg.TargetID, arg.SourceID, arg.HouseholdID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateIngestionJobStaged = `-- name: UpdateIngestionJobStaged :one
 UPDATE ingestion_jobs
-SET status = 'staged', staged_data = $2
-WHERE id = $1
-RETURNING id, type, raw_input, status, staged_data, created_at
+SET status = 'staged', staged_data = $2, duplicates = $3
+WHERE id = $1 AND household_id = $4 AND status = 'pending'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
 `
 
 type UpdateIngestionJobStagedParams struct {
-	ID         uuid.UUID
-	StagedData *json.RawMessage
+	ID          uuid.UUID
+	StagedData  *json.RawMessage
+	Duplicates  *json.RawMessage
+	HouseholdID uuid.UUID
 }
 
+// Stages a pending job. Use UpdateIngestionJobStagedData to edit a staged one.
 func (q *Queries) UpdateIngestionJobStaged(ctx context.Context, arg UpdateIngestionJobStagedParams) (IngestionJob, error) {
-	row := q.db.QueryRowContext(ctx, updateIngestionJobStaged, arg.ID, arg.StagedData)
+	row := q.db.QueryRowContext(ctx, updateIngestionJobStaged,
+		arg.ID,
+		arg.StagedData,
+		arg.Duplicates,
+		arg.HouseholdID,
+	)
+	var i IngestionJob
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.RawInput,
+		&i.Status,
+		&i.StagedData,
+		&i.CreatedAt,
+		&i.BatchID,
+		&i.BatchPosition,
+		&i.SourceFileID,
+		&i.Duplicates,
+		&i.RecipeID,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const updateIngestionJobStagedData = `-- name: UpdateIngestionJobStagedData :one
+UPDATE ingestion_jobs
+SET staged_data = $2, duplicates = $3, updated_by = $4
+WHERE id = $1 AND household_id = $5 AND status = 'staged'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+`
+
+type UpdateIngestionJobStagedDataParams struct {
+	ID          uuid.UUID
+	StagedData  *json.RawMessage
+	Duplicates  *json.RawMessage
+	UpdatedBy   sql.NullString
+	HouseholdID uuid.UUID
+}
+
+// Replaces a staged job's recipe and duplicate candidates.
+func (q *Queries) UpdateIngestionJobStagedData(ctx context.Context, arg UpdateIngestionJobStagedDataParams) (IngestionJob, error) {
+	row := q.db.QueryRowContext(ctx, updateIngestionJobStagedData,
+		arg.ID,
+		arg.StagedData,
+		arg.Duplicates,
+		arg.UpdatedBy,
+		arg.HouseholdID,
+	)
 	var i IngestionJob
 	err := row.Scan(
 		&i.ID,
@@ -78,6 +458,14 @@ func (q *Queries) UpdateIngestionJobStaged(ctx context.Context, arg UpdateIngest
 		&i.Status,
 		&i.StagedData,
 		&i.CreatedAt,
+		&i.BatchID,
+		&i.BatchPosition,
+		&i.SourceFileID,
+		&i.Duplicates,
+		&i.RecipeID,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.UpdatedBy,
 	)
 	return i, err
 }
@@ -85,17 +473,21 @@ func (q *Queries) UpdateIngestionJobStaged(ctx context.Context, arg UpdateIngest
 const updateIngestionJobStatus = `-- name: UpdateIngestionJobStatus :one
 UPDATE ingestion_jobs
 SET status = $2
-WHERE id = $1
-RETURNING id, type, raw_input, status, staged_data, created_at
+WHERE id = $1 AND household_id = $3 AND status = 'pending'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
 `
 
 type UpdateIngestionJobStatusParams struct {
-	ID     uuid.UUID
-	Status string
+	ID          uuid.UUID
+	Status      string
+	HouseholdID uuid.UUID
 }
 
+// Only pending jobs change status here; staged, confirmed, rejected and
+// failed jobs are settled.
 func (q *Queries) UpdateIngestionJobStatus(ctx context.Context, arg UpdateIngestionJobStatusParams) (IngestionJob, error) {
-	row := q.db.QueryRowContext(ctx, updateIngestionJobStatus, arg.ID, arg.Status)
+	row := q.db.QueryRowContext(ctx, updateIngestionJobStatus, arg.ID, arg.Status, arg.HouseholdID)
 	var i IngestionJob
 	err := row.Scan(
 		&i.ID,
@@ -104,6 +496,14 @@ func (q *Queries) UpdateIngestionJobStatus(ctx context.Context, arg UpdateIngest
 		&i.Status,
 		&i.StagedData,
 		&i.CreatedAt,
+		&i.BatchID,
+		&i.BatchPosition,
+		&i.SourceFileID,
+		&i.Duplicates,
+		&i.RecipeID,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.UpdatedBy,
 	)
 	return i, err
 }
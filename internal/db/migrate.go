@@ -0,0 +1,37 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// NewMigrator returns a migrator for MigrationsFS over a connection of its
+// own to dsn, so the session settings of the service's pool, such as its
+// statement_timeout, do not apply to migrations. Closing the migrator closes
+// the connection.
+func NewMigrator(dsn string) (*migrate.Migrate, error) {
+	sqlDB, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	srcDriver, err := iofs.New(MigrationsFS, "migrations")
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("create migration source: %w", err)
+	}
+	dbDriver, err := postgres.WithInstance(sqlDB, &postgres.Config{})
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("create migration driver: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", srcDriver, "postgres", dbDriver)
+	if err != nil {
+		_ = dbDriver.Close()
+		return nil, fmt.Errorf("create migrator: %w", err)
+	}
+	return m, nil
+}
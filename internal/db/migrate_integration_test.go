@@ -0,0 +1,108 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/testutil"
+)
+
+// TestMigrations_DownAndUp reverts every migration one at a time, checking
+// each down migration undoes its up migration well enough for it to apply
+// again, and that reverting them all leaves nothing behind.
+func TestMigrations_DownAndUp(t *testing.T) {
+	dsn := testutil.SetupEmptyDB(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.Close() })
+	sqlDB, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	latest, err := LatestMigration()
+	require.NoError(t, err)
+	require.NoError(t, m.Up())
+	require.NoError(t, CheckSchema(ctx, sqlDB))
+
+	for {
+		version, _, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			break
+		}
+		require.NoError(t, err)
+
+		require.NoError(t, m.Steps(-1), "down from %d", version)
+		require.NoError(t, m.Steps(1), "up to %d again", version)
+		require.NoError(t, m.Steps(-1), "down from %d again", version)
+	}
+
+	var leftover []string
+	rows, err := sqlDB.QueryContext(ctx, `
+		SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relkind IN ('r', 'v', 'm', 'S', 'p')
+		  AND c.relname <> 'schema_migrations'
+		UNION ALL
+		SELECT t.typname FROM pg_type t JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = 'public' AND t.typtype IN ('e', 'd')
+		UNION ALL
+		SELECT p.proname FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = 'public'`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		leftover = append(leftover, name)
+	}
+	require.NoError(t, rows.Err())
+	assert.Empty(t, leftover, "objects left after reverting every migration")
+
+	require.NoError(t, m.Up())
+	version, dirty, err := SchemaVersion(ctx, sqlDB)
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	assert.Equal(t, latest, version)
+}
+
+func TestCheckSchema(t *testing.T) {
+	dsn := testutil.SetupEmptyDB(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.Close() })
+	sqlDB, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	latest, err := LatestMigration()
+	require.NoError(t, err)
+
+	require.Error(t, CheckSchema(ctx, sqlDB), "never migrated")
+
+	require.NoError(t, m.Migrate(latest-1))
+	err = CheckSchema(ctx, sqlDB)
+	require.Error(t, err, "behind")
+	assert.Contains(t, err.Error(), "want")
+
+	require.NoError(t, m.Up())
+	require.NoError(t, CheckSchema(ctx, sqlDB))
+
+	require.NoError(t, m.Force(int(latest)+1))
+	require.NoError(t, CheckSchema(ctx, sqlDB), "a newer release's schema is accepted")
+
+	_, err = sqlDB.ExecContext(ctx, "UPDATE schema_migrations SET dirty = true")
+	require.NoError(t, err)
+	err = CheckSchema(ctx, sqlDB)
+	require.Error(t, err, "dirty")
+	assert.Contains(t, err.Error(), "dirty")
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS event_outbox (
+  id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+  event_type   TEXT        NOT NULL,
+  routing_key  TEXT        NOT NULL,
+  payload      JSONB       NOT NULL,
+  attempts     INT         NOT NULL DEFAULT 0,
+  last_error   TEXT,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS event_outbox_pending_idx
+  ON event_outbox (created_at)
+  WHERE published_at IS NULL;
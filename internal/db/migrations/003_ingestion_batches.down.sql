@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS ingestion_jobs_batch_idx;
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS batch_position;
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS batch_id;
+DROP TABLE IF EXISTS ingestion_batches;
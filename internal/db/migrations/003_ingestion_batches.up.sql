@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS ingestion_batches (
+  id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE ingestion_jobs ADD COLUMN batch_id UUID REFERENCES ingestion_batches(id) ON DELETE CASCADE;
+ALTER TABLE ingestion_jobs ADD COLUMN batch_position INT;
+
+CREATE INDEX IF NOT EXISTS ingestion_jobs_batch_idx
+  ON ingestion_jobs (batch_id, batch_position)
+  WHERE batch_id IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS source_file_id;
+DROP TABLE IF EXISTS ingestion_files;
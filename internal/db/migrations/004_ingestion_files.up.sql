@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS ingestion_files (
+  id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+  filename     TEXT        NOT NULL,
+  content_type TEXT        NOT NULL,
+  size_bytes   BIGINT      NOT NULL,
+  sha256       TEXT        NOT NULL,
+  data         BYTEA       NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE ingestion_jobs ADD COLUMN source_file_id UUID REFERENCES ingestion_files(id) ON DELETE SET NULL;
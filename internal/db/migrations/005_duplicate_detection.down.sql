@@ -0,0 +1,5 @@
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS duplicates;
+
+DROP INDEX IF EXISTS recipe_ingredients_ingredient_id_idx;
+DROP INDEX IF EXISTS recipes_source_url_idx;
+DROP INDEX IF EXISTS recipes_title_key_idx;
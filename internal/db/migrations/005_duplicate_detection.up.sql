@@ -0,0 +1,10 @@
+-- Normalized title: lowercased, punctuation and whitespace runs collapsed.
+-- FindRecipesByTitleKey must use the same expression to hit this index.
+CREATE INDEX IF NOT EXISTS recipes_title_key_idx
+  ON recipes ((btrim(regexp_replace(lower(title), '[^[:alnum:]]+', ' ', 'g'))));
+
+CREATE INDEX IF NOT EXISTS recipes_source_url_idx ON recipes (source_url) WHERE source_url IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS recipe_ingredients_ingredient_id_idx ON recipe_ingredients (ingredient_id);
+
+ALTER TABLE ingestion_jobs ADD COLUMN duplicates JSONB;
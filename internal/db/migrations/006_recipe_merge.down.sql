@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS recipe_audit_log;
+
+DROP INDEX IF EXISTS ingestion_jobs_recipe_id_idx;
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS recipe_id;
@@ -0,0 +1,14 @@
+ALTER TABLE ingestion_jobs ADD COLUMN recipe_id UUID REFERENCES recipes(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS ingestion_jobs_recipe_id_idx ON ingestion_jobs (recipe_id) WHERE recipe_id IS NOT NULL;
+
+-- Audit rows outlive the recipes they describe, so recipe_id has no FK.
+CREATE TABLE IF NOT EXISTS recipe_audit_log (
+  id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+  recipe_id  UUID        NOT NULL,
+  action     TEXT        NOT NULL,
+  details    JSONB       NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS recipe_audit_log_recipe_id_idx ON recipe_audit_log (recipe_id, created_at);
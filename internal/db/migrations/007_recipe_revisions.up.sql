@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS recipe_revisions (
+  id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+  recipe_id  UUID        NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+  revision   INT         NOT NULL,
+  snapshot   JSONB       NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (recipe_id, revision)
+);
+
+-- Seed revision 1 for existing recipes in the same shape the service writes
+-- (events.RecipePayload), so their first update does not lose the original.
+INSERT INTO recipe_revisions (recipe_id, revision, snapshot, created_at)
+SELECT r.id, 1, jsonb_build_object(
+    'id', r.id,
+    'title', r.title,
+    'description', COALESCE(r.description, ''),
+    'source_url', COALESCE(r.source_url, ''),
+    'servings', COALESCE(r.servings, 0),
+    'prep_minutes', COALESCE(r.prep_minutes, 0),
+    'cook_minutes', COALESCE(r.cook_minutes, 0),
+    'tags', to_jsonb(r.tags),
+    'steps', COALESCE((
+      SELECT jsonb_agg(jsonb_build_object('step_number', s.step_number, 'instruction', s.instruction)
+                       ORDER BY s.step_number)
+      FROM recipe_steps s WHERE s.recipe_id = r.id), '[]'::jsonb),
+    'ingredients', COALESCE((
+      SELECT jsonb_agg(jsonb_build_object(
+               'id', i.id,
+               'ingredient_id', i.ingredient_id,
+               'quantity', COALESCE(i.quantity, 0),
+               'unit', COALESCE(i.unit, ''),
+               'is_optional', i.is_optional,
+               'preparation_notes', COALESCE(i.preparation_notes, '')))
+      FROM recipe_ingredients i WHERE i.recipe_id = r.id), '[]'::jsonb),
+    'created_at', r.created_at,
+    'updated_at', r.updated_at
+  ), r.updated_at
+FROM recipes r;
@@ -0,0 +1,2 @@
+-- Incremented by every write to the recipe row; exposed as the recipe's ETag.
+ALTER TABLE recipes ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+ALTER TABLE recipe_steps DROP CONSTRAINT recipe_steps_recipe_id_step_number_key;
+ALTER TABLE recipe_steps ADD CONSTRAINT recipe_steps_recipe_id_step_number_key UNIQUE (recipe_id, step_number);
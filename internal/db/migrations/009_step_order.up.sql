@@ -0,0 +1,6 @@
+-- Step inserts, moves and deletes renumber neighbouring steps in a single
+-- UPDATE; a deferrable constraint is checked at the end of the statement
+-- rather than row by row.
+ALTER TABLE recipe_steps DROP CONSTRAINT recipe_steps_recipe_id_step_number_key;
+ALTER TABLE recipe_steps ADD CONSTRAINT recipe_steps_recipe_id_step_number_key
+  UNIQUE (recipe_id, step_number) DEFERRABLE INITIALLY IMMEDIATE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS recipes_deleted_at_idx;
+
+ALTER TABLE recipes DROP COLUMN IF EXISTS deleted_at;
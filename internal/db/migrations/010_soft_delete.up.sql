@@ -0,0 +1,3 @@
+ALTER TABLE recipes ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS recipes_deleted_at_idx ON recipes (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS recipe_shares;
+
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS household_id;
+ALTER TABLE ingestion_batches DROP COLUMN IF EXISTS household_id;
+
+DROP INDEX IF EXISTS recipes_household_id_idx;
+ALTER TABLE recipes DROP COLUMN IF EXISTS visibility;
+ALTER TABLE recipes DROP COLUMN IF EXISTS household_id;
@@ -0,0 +1,24 @@
+-- Rows that predate households belong to the default household (the nil
+-- UUID). The defaults are dropped afterwards so every new row must name its
+-- household explicitly.
+ALTER TABLE recipes ADD COLUMN household_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE recipes ALTER COLUMN household_id DROP DEFAULT;
+ALTER TABLE recipes ADD COLUMN visibility TEXT NOT NULL DEFAULT 'private'
+  CHECK (visibility IN ('private', 'shared', 'public'));
+
+CREATE INDEX IF NOT EXISTS recipes_household_id_idx ON recipes (household_id, created_at DESC);
+
+ALTER TABLE ingestion_batches ADD COLUMN household_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE ingestion_batches ALTER COLUMN household_id DROP DEFAULT;
+
+ALTER TABLE ingestion_jobs ADD COLUMN household_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE ingestion_jobs ALTER COLUMN household_id DROP DEFAULT;
+
+CREATE TABLE IF NOT EXISTS recipe_shares (
+  recipe_id    UUID        NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+  household_id UUID        NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (recipe_id, household_id)
+);
+
+CREATE INDEX IF NOT EXISTS recipe_shares_household_id_idx ON recipe_shares (household_id);
@@ -0,0 +1,5 @@
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS created_by;
+
+ALTER TABLE recipes DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE recipes DROP COLUMN IF EXISTS created_by;
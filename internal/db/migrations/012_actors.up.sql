@@ -0,0 +1,7 @@
+-- Subjects of the authenticated callers that created and last changed each
+-- row. NULL for rows written before authentication, or with it disabled.
+ALTER TABLE recipes ADD COLUMN created_by TEXT;
+ALTER TABLE recipes ADD COLUMN updated_by TEXT;
+
+ALTER TABLE ingestion_jobs ADD COLUMN created_by TEXT;
+ALTER TABLE ingestion_jobs ADD COLUMN updated_by TEXT;
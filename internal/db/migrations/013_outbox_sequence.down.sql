@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS event_outbox_pending_idx;
+ALTER TABLE event_outbox DROP COLUMN IF EXISTS seq;
+
+CREATE INDEX IF NOT EXISTS event_outbox_pending_idx
+  ON event_outbox (created_at)
+  WHERE published_at IS NULL;
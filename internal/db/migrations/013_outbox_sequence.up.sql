@@ -0,0 +1,9 @@
+-- Relay order. seq is taken when the row is inserted, after the write it
+-- records has locked its recipe row, so one recipe's events are numbered in
+-- commit order. created_at is the transaction's start time and is not.
+ALTER TABLE event_outbox ADD COLUMN seq BIGSERIAL;
+
+DROP INDEX IF EXISTS event_outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS event_outbox_pending_idx
+  ON event_outbox (seq)
+  WHERE published_at IS NULL;
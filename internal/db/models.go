@@ -12,13 +12,49 @@ import (
 	"github.com/google/uuid"
 )
 
+type EventOutbox struct {
+	ID          uuid.UUID
+	EventType   string
+	RoutingKey  string
+	Payload     json.RawMessage
+	Attempts    int32
+	LastError   sql.NullString
+	CreatedAt   time.Time
+	PublishedAt sql.NullTime
+	Seq         sql.NullInt64
+}
+
+type IngestionBatch struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	HouseholdID uuid.UUID
+}
+
+type IngestionFile struct {
+	ID          uuid.UUID
+	Filename    string
+	ContentType string
+	SizeBytes   int64
+	Sha256      string
+	Data        []byte
+	CreatedAt   time.Time
+}
+
 type IngestionJob struct {
-	ID         uuid.UUID
-	Type       string
-	RawInput   string
-	Status     string
-	StagedData *json.RawMessage
-	CreatedAt  time.Time
+	ID            uuid.UUID
+	Type          string
+	RawInput      string
+	Status        string
+	StagedData    *json.RawMessage
+	CreatedAt     time.Time
+	BatchID       uuid.NullUUID
+	BatchPosition sql.NullInt32
+	SourceFileID  uuid.NullUUID
+	Duplicates    *json.RawMessage
+	RecipeID      uuid.NullUUID
+	HouseholdID   uuid.UUID
+	CreatedBy     sql.NullString
+	UpdatedBy     sql.NullString
 }
 
 type Recipe struct {
@@ -32,6 +68,20 @@ type Recipe struct {
 	Tags        []string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	Version     int32
+	DeletedAt   sql.NullTime
+	HouseholdID uuid.UUID
+	Visibility  string
+	CreatedBy   sql.NullString
+	UpdatedBy   sql.NullString
+}
+
+type RecipeAuditLog struct {
+	ID        uuid.UUID
+	RecipeID  uuid.UUID
+	Action    string
+	Details   json.RawMessage
+	CreatedAt time.Time
 }
 
 type RecipeIngredient struct {
@@ -44,6 +94,20 @@ type RecipeIngredient struct {
 	PreparationNotes sql.NullString
 }
 
+type RecipeRevision struct {
+	ID        uuid.UUID
+	RecipeID  uuid.UUID
+	Revision  int32
+	Snapshot  json.RawMessage
+	CreatedAt time.Time
+}
+
+type RecipeShare struct {
+	RecipeID    uuid.UUID
+	HouseholdID uuid.UUID
+	CreatedAt   time.Time
+}
+
 type RecipeStep struct {
 	ID          uuid.UUID
 	RecipeID    uuid.UUID
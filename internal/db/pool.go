@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// queryCanceled is the SQLSTATE of a statement cancelled by
+// statement_timeout.
+const queryCanceled = "57014"
+
+// PoolConfig configures the connection pool opened by Open. Zero values
+// leave the database/sql defaults.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// StatementTimeout is set as statement_timeout on every session, so the
+	// server cancels statements that outlive it.
+	StatementTimeout time.Duration
+}
+
+// Open opens a PostgreSQL connection pool for dsn configured by cfg. Like
+// sql.Open, it does not connect.
+func Open(dsn string, cfg PoolConfig) (*sql.DB, error) {
+	if cfg.StatementTimeout > 0 {
+		var err error
+		dsn, err = withStatementTimeout(dsn, cfg.StatementTimeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sqlDB, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return sqlDB, nil
+}
+
+// withStatementTimeout adds statement_timeout to dsn, in either the URL or
+// the keyword/value form lib/pq accepts. lib/pq sends it as a run-time
+// parameter when each connection starts.
+func withStatementTimeout(dsn string, timeout time.Duration) (string, error) {
+	ms := strconv.FormatInt(max(timeout.Milliseconds(), 1), 10)
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return strings.TrimSpace(dsn) + " statement_timeout=" + ms, nil
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", errors.New("parse database URL: invalid URL")
+	}
+	q := u.Query()
+	q.Set("statement_timeout", ms)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// WithQueryTimeout wraps q so that every call runs with a deadline of at
+// most timeout, releasing its connection once the deadline passes even if
+// the caller's context has none. A timeout of zero returns q unchanged.
+func WithQueryTimeout(q Querier, timeout time.Duration) Querier {
+	if timeout <= 0 {
+		return q
+	}
+	return WithQueryHook(q, func(ctx context.Context, _ string) (context.Context, func(error)) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		return ctx, func(error) { cancel() }
+	})
+}
+
+// IsTimeout reports whether err comes from a query that ran out of time,
+// either at its context's deadline or at the session's statement_timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == queryCanceled
+}
+
+// PoolStats is a snapshot of the connection pool, as reported by readiness.
+type PoolStats struct {
+	MaxOpen        int     `json:"max_open"`
+	Open           int     `json:"open"`
+	InUse          int     `json:"in_use"`
+	Idle           int     `json:"idle"`
+	WaitCount      int64   `json:"wait_count"`
+	WaitDurationMS float64 `json:"wait_duration_ms"`
+}
+
+// Stats returns a snapshot of sqlDB's connection pool.
+func Stats(sqlDB *sql.DB) PoolStats {
+	s := sqlDB.Stats()
+	return PoolStats{
+		MaxOpen:        s.MaxOpenConnections,
+		Open:           s.OpenConnections,
+		InUse:          s.InUse,
+		Idle:           s.Idle,
+		WaitCount:      s.WaitCount,
+		WaitDurationMS: float64(s.WaitDuration.Microseconds()) / 1000,
+	}
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStatementTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dsn  string
+		want string
+	}{
+		"url": {
+			dsn:  "postgres://recipes:secret@db:5432/recipe_db?sslmode=disable",
+			want: "postgres://recipes:secret@db:5432/recipe_db?sslmode=disable&statement_timeout=30000",
+		},
+		"url replaces existing": {
+			dsn:  "postgresql://db/recipe_db?statement_timeout=0",
+			want: "postgresql://db/recipe_db?statement_timeout=30000",
+		},
+		"keyword/value": {
+			dsn:  "host=db dbname=recipe_db sslmode=disable ",
+			want: "host=db dbname=recipe_db sslmode=disable statement_timeout=30000",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := withStatementTimeout(tt.dsn, 30*time.Second)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsTimeout(fmt.Errorf("get recipe: %w", context.DeadlineExceeded)))
+	assert.True(t, IsTimeout(fmt.Errorf("list recipes: %w", &pq.Error{Code: queryCanceled})))
+	assert.False(t, IsTimeout(context.Canceled))
+	assert.False(t, IsTimeout(&pq.Error{Code: "23505"}))
+	assert.False(t, IsTimeout(errors.New("connection refused")))
+}
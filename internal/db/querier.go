@@ -6,30 +6,94 @@ package db
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddRecipeShare(ctx context.Context, arg AddRecipeShareParams) error
+	// Used by the metrics collector, which reports across all households.
+	CountIngestionJobsByStatus(ctx context.Context) ([]CountIngestionJobsByStatusRow, error)
+	CreateIngestionBatch(ctx context.Context, householdID uuid.UUID) (IngestionBatch, error)
+	CreateIngestionFile(ctx context.Context, arg CreateIngestionFileParams) (IngestionFile, error)
 	CreateIngestionJob(ctx context.Context, arg CreateIngestionJobParams) (IngestionJob, error)
 	CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Recipe, error)
 	CreateRecipeIngredient(ctx context.Context, arg CreateRecipeIngredientParams) (RecipeIngredient, error)
 	CreateStep(ctx context.Context, arg CreateStepParams) (RecipeStep, error)
 	DeleteIngredientsByRecipe(ctx context.Context, recipeID uuid.UUID) error
-	DeleteRecipe(ctx context.Context, id uuid.UUID) error
+	DeleteRecipeIngredient(ctx context.Context, arg DeleteRecipeIngredientParams) (int64, error)
+	DeleteStep(ctx context.Context, arg DeleteStepParams) (int64, error)
 	DeleteStepsByRecipe(ctx context.Context, recipeID uuid.UUID) error
-	GetIngestionJob(ctx context.Context, id uuid.UUID) (IngestionJob, error)
-	GetRecipe(ctx context.Context, id uuid.UUID) (Recipe, error)
-	ListIngredientsByRecipe(ctx context.Context, recipeID uuid.UUID) ([]RecipeIngredient, error)
-	ListRecipes(ctx context.Context) ([]Recipe, error)
-	ListRecipesByCookTime(ctx context.Context, cookMinutes sql.NullInt32) ([]Recipe, error)
-	ListRecipesByTag(ctx context.Context, tags []string) ([]Recipe, error)
-	ListRecipesByTitle(ctx context.Context, dollar_1 sql.NullString) ([]Recipe, error)
-	ListStepsByRecipe(ctx context.Context, recipeID uuid.UUID) ([]RecipeStep, error)
+	FindRecipesBySourceURL(ctx context.Context, arg FindRecipesBySourceURLParams) ([]FindRecipesBySourceURLRow, error)
+	FindRecipesByTitleKey(ctx context.Context, arg FindRecipesByTitleKeyParams) ([]FindRecipesByTitleKeyRow, error)
+	FindRecipesSharingIngredients(ctx context.Context, arg FindRecipesSharingIngredientsParams) ([]FindRecipesSharingIngredientsRow, error)
+	GetIngestionBatch(ctx context.Context, arg GetIngestionBatchParams) (IngestionBatch, error)
+	// Only finds files uploaded for one of the household's jobs.
+	GetIngestionFile(ctx context.Context, arg GetIngestionFileParams) (IngestionFile, error)
+	GetIngestionJob(ctx context.Context, arg GetIngestionJobParams) (IngestionJob, error)
+	// Used by the recipe.imported handler, which only knows the job ID.
+	GetIngestionJobHousehold(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	GetMaxStepNumber(ctx context.Context, recipeID uuid.UUID) (int32, error)
+	GetRecipe(ctx context.Context, arg GetRecipeParams) (Recipe, error)
+	GetRecipeIngredient(ctx context.Context, arg GetRecipeIngredientParams) (RecipeIngredient, error)
+	GetRecipeRevision(ctx context.Context, arg GetRecipeRevisionParams) (RecipeRevision, error)
+	GetStep(ctx context.Context, arg GetStepParams) (RecipeStep, error)
+	InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) (EventOutbox, error)
+	InsertRecipeAuditLog(ctx context.Context, arg InsertRecipeAuditLogParams) (RecipeAuditLog, error)
+	// Inserts nothing unless the household owns the recipe.
+	InsertRecipeRevision(ctx context.Context, arg InsertRecipeRevisionParams) (RecipeRevision, error)
+	// An empty status lists jobs in every status.
+	ListIngestionJobs(ctx context.Context, arg ListIngestionJobsParams) ([]IngestionJob, error)
+	ListIngestionJobsByBatch(ctx context.Context, arg ListIngestionJobsByBatchParams) ([]IngestionJob, error)
+	// Returns no ingredients unless the recipe is visible to the household.
+	ListIngredientsByRecipe(ctx context.Context, arg ListIngredientsByRecipeParams) ([]RecipeIngredient, error)
+	// Rows that failed max_attempts times are parked: they stay unpublished but
+	// no longer hold up the rows behind them.
+	ListPendingOutboxEvents(ctx context.Context, arg ListPendingOutboxEventsParams) ([]EventOutbox, error)
+	ListRecipeAuditLog(ctx context.Context, recipeID uuid.UUID) ([]RecipeAuditLog, error)
+	// Returns no revisions unless the recipe is visible to the household.
+	ListRecipeRevisions(ctx context.Context, arg ListRecipeRevisionsParams) ([]RecipeRevision, error)
+	ListRecipeShares(ctx context.Context, recipeID uuid.UUID) ([]RecipeShare, error)
+	ListRecipes(ctx context.Context, householdID uuid.UUID) ([]Recipe, error)
+	ListRecipesByCookTime(ctx context.Context, arg ListRecipesByCookTimeParams) ([]Recipe, error)
+	ListRecipesByTag(ctx context.Context, arg ListRecipesByTagParams) ([]Recipe, error)
+	ListRecipesByTitle(ctx context.Context, arg ListRecipesByTitleParams) ([]Recipe, error)
+	// Returns no steps unless the recipe is visible to the household.
+	ListStepsByRecipe(ctx context.Context, arg ListStepsByRecipeParams) ([]RecipeStep, error)
+	ListTrashedRecipes(ctx context.Context, householdID uuid.UUID) ([]Recipe, error)
+	LockRecipe(ctx context.Context, arg LockRecipeParams) (Recipe, error)
+	// Only a staged job can be confirmed; no row means it was settled meanwhile.
+	MarkIngestionJobConfirmed(ctx context.Context, arg MarkIngestionJobConfirmedParams) (IngestionJob, error)
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
+	MarkOutboxEventPublished(ctx context.Context, id uuid.UUID) error
+	MoveStep(ctx context.Context, arg MoveStepParams) error
+	PrefillIngestionJob(ctx context.Context, arg PrefillIngestionJobParams) (IngestionJob, error)
+	// Runs for every household: retention is a property of the deployment.
+	PurgeDeletedRecipes(ctx context.Context, cutoff time.Time) (int64, error)
+	RejectIngestionJob(ctx context.Context, arg RejectIngestionJobParams) (IngestionJob, error)
+	RemoveRecipeShare(ctx context.Context, arg RemoveRecipeShareParams) (int64, error)
+	RepointIngestionJobs(ctx context.Context, arg RepointIngestionJobsParams) (int64, error)
+	// Moves the source recipe's shares onto the target, e.g. when merging.
+	// Households the target is already shared with are skipped.
+	RepointRecipeShares(ctx context.Context, arg RepointRecipeSharesParams) (int64, error)
+	RestoreRecipe(ctx context.Context, arg RestoreRecipeParams) (Recipe, error)
+	ShiftStepsFrom(ctx context.Context, arg ShiftStepsFromParams) error
+	SoftDeleteRecipe(ctx context.Context, arg SoftDeleteRecipeParams) (Recipe, error)
+	TouchRecipe(ctx context.Context, arg TouchRecipeParams) (Recipe, error)
+	// Taken by the relay for the rest of its transaction, so that only one
+	// replica publishes at a time.
+	TryLockOutboxRelay(ctx context.Context) (bool, error)
+	// Stages a pending job. Use UpdateIngestionJobStagedData to edit a staged one.
 	UpdateIngestionJobStaged(ctx context.Context, arg UpdateIngestionJobStagedParams) (IngestionJob, error)
+	// Replaces a staged job's recipe and duplicate candidates.
+	UpdateIngestionJobStagedData(ctx context.Context, arg UpdateIngestionJobStagedDataParams) (IngestionJob, error)
+	// Only pending jobs change status here; staged, confirmed, rejected and
+	// failed jobs are settled.
 	UpdateIngestionJobStatus(ctx context.Context, arg UpdateIngestionJobStatusParams) (IngestionJob, error)
 	UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Recipe, error)
+	UpdateRecipeIngredient(ctx context.Context, arg UpdateRecipeIngredientParams) (RecipeIngredient, error)
+	UpdateStepInstruction(ctx context.Context, arg UpdateStepInstructionParams) (RecipeStep, error)
 }
 
 var _ Querier = (*Queries)(nil)
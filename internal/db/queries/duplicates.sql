@@ -0,0 +1,31 @@
+-- Duplicates are only looked for among the caller's own recipes.
+
+-- name: FindRecipesByTitleKey :many
+SELECT id, title
+FROM recipes
+WHERE btrim(regexp_replace(lower(title), '[^[:alnum:]]+', ' ', 'g'))
+    = btrim(regexp_replace(lower(sqlc.arg(title)::text), '[^[:alnum:]]+', ' ', 'g'))
+  AND household_id = sqlc.arg(household_id)::uuid AND deleted_at IS NULL
+LIMIT 10;
+
+-- name: FindRecipesBySourceURL :many
+SELECT id, title
+FROM recipes
+WHERE source_url = sqlc.arg(source_url)::text AND household_id = sqlc.arg(household_id)::uuid AND deleted_at IS NULL
+LIMIT 10;
+
+-- name: FindRecipesSharingIngredients :many
+WITH matched AS (
+    SELECT recipe_id, count(DISTINCT ingredient_id) AS shared
+    FROM recipe_ingredients
+    WHERE ingredient_id = ANY(sqlc.arg(ingredient_ids)::uuid[])
+    GROUP BY recipe_id
+)
+SELECT r.id, r.title, m.shared::int AS shared, count(DISTINCT ri.ingredient_id)::int AS total
+FROM matched m
+JOIN recipes r ON r.id = m.recipe_id
+JOIN recipe_ingredients ri ON ri.recipe_id = m.recipe_id
+WHERE r.household_id = sqlc.arg(household_id)::uuid AND r.deleted_at IS NULL
+GROUP BY r.id, r.title, m.shared
+ORDER BY m.shared DESC
+LIMIT 20;
@@ -0,0 +1,28 @@
+-- name: InsertOutboxEvent :one
+INSERT INTO event_outbox (event_type, routing_key, payload)
+VALUES ($1, $2, $3)
+RETURNING id, event_type, routing_key, payload, attempts, last_error, created_at, published_at, seq;
+
+-- name: TryLockOutboxRelay :one
+-- Taken by the relay for the rest of its transaction, so that only one
+-- replica publishes at a time.
+SELECT pg_try_advisory_xact_lock(hashtext('event_outbox_relay'))::boolean AS locked;
+
+-- name: ListPendingOutboxEvents :many
+-- Rows that failed max_attempts times are parked: they stay unpublished but
+-- no longer hold up the rows behind them.
+SELECT id, event_type, routing_key, payload, attempts, last_error, created_at, published_at, seq
+FROM event_outbox
+WHERE published_at IS NULL AND attempts < sqlc.arg(max_attempts)
+ORDER BY seq
+LIMIT sqlc.arg(batch_size);
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE event_outbox
+SET published_at = now()
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE event_outbox
+SET attempts = attempts + 1, last_error = $2
+WHERE id = $1;
@@ -0,0 +1,8 @@
+-- name: CreateIngestionBatch :one
+INSERT INTO ingestion_batches (household_id)
+VALUES ($1)
+RETURNING id, created_at, household_id;
+
+-- name: GetIngestionBatch :one
+SELECT id, created_at, household_id
+FROM ingestion_batches WHERE id = $1 AND household_id = $2;
@@ -0,0 +1,14 @@
+-- name: CreateIngestionFile :one
+INSERT INTO ingestion_files (filename, content_type, size_bytes, sha256, data)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, filename, content_type, size_bytes, sha256, data, created_at;
+
+-- name: GetIngestionFile :one
+-- Only finds files uploaded for one of the household's jobs.
+SELECT id, filename, content_type, size_bytes, sha256, data, created_at
+FROM ingestion_files
+WHERE id = sqlc.arg(id)::uuid
+  AND EXISTS (
+    SELECT 1 FROM ingestion_jobs j
+    WHERE j.source_file_id = ingestion_files.id AND j.household_id = sqlc.arg(household_id)::uuid
+  );
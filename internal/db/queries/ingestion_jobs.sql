@@ -1,20 +1,87 @@
 -- name: CreateIngestionJob :one
-INSERT INTO ingestion_jobs (type, raw_input)
-VALUES ($1, $2)
-RETURNING id, type, raw_input, status, staged_data, created_at;
+INSERT INTO ingestion_jobs (type, raw_input, batch_id, batch_position, source_file_id, household_id, created_by, updated_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by;
 
 -- name: GetIngestionJob :one
-SELECT id, type, raw_input, status, staged_data, created_at
-FROM ingestion_jobs WHERE id = $1;
+SELECT id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+FROM ingestion_jobs WHERE id = $1 AND household_id = $2;
+
+-- name: GetIngestionJobHousehold :one
+-- Used by the recipe.imported handler, which only knows the job ID.
+SELECT household_id FROM ingestion_jobs WHERE id = $1;
 
 -- name: UpdateIngestionJobStatus :one
+-- Only pending jobs change status here; staged, confirmed, rejected and
+-- failed jobs are settled.
 UPDATE ingestion_jobs
 SET status = $2
-WHERE id = $1
-RETURNING id, type, raw_input, status, staged_data, created_at;
+WHERE id = $1 AND household_id = $3 AND status = 'pending'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by;
 
 -- name: UpdateIngestionJobStaged :one
+-- Stages a pending job. Use UpdateIngestionJobStagedData to edit a staged one.
+UPDATE ingestion_jobs
+SET status = 'staged', staged_data = $2, duplicates = $3
+WHERE id = $1 AND household_id = $4 AND status = 'pending'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by;
+
+-- name: PrefillIngestionJob :one
+UPDATE ingestion_jobs
+SET staged_data = $2
+WHERE id = $1 AND household_id = $3 AND status = 'pending'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by;
+
+-- name: ListIngestionJobsByBatch :many
+SELECT id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+FROM ingestion_jobs
+WHERE batch_id = $1 AND household_id = $2
+ORDER BY batch_position;
+
+-- name: ListIngestionJobs :many
+-- An empty status lists jobs in every status.
+SELECT id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by
+FROM ingestion_jobs
+WHERE household_id = sqlc.arg(household_id)::uuid
+  AND (sqlc.arg(status)::text = '' OR status = sqlc.arg(status)::text)
+ORDER BY created_at DESC, id
+LIMIT sqlc.arg(max_jobs)::int;
+
+-- name: UpdateIngestionJobStagedData :one
+-- Replaces a staged job's recipe and duplicate candidates.
 UPDATE ingestion_jobs
-SET status = 'staged', staged_data = $2
-WHERE id = $1
-RETURNING id, type, raw_input, status, staged_data, created_at;
+SET staged_data = $2, duplicates = $3, updated_by = $4
+WHERE id = $1 AND household_id = $5 AND status = 'staged'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by;
+
+-- name: RejectIngestionJob :one
+UPDATE ingestion_jobs
+SET status = 'rejected', updated_by = $2
+WHERE id = $1 AND household_id = $3 AND status IN ('pending', 'staged', 'failed')
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by;
+
+-- name: MarkIngestionJobConfirmed :one
+-- Only a staged job can be confirmed; no row means it was settled meanwhile.
+UPDATE ingestion_jobs
+SET status = 'confirmed', recipe_id = $2, updated_by = $3
+WHERE id = $1 AND household_id = $4 AND status = 'staged'
+RETURNING id, type, raw_input, status, staged_data, created_at, batch_id, batch_position, source_file_id, duplicates, recipe_id, household_id,
+    created_by, updated_by;
+
+-- name: RepointIngestionJobs :execrows
+UPDATE ingestion_jobs
+SET recipe_id = sqlc.arg(target_id)::uuid
+WHERE recipe_id = sqlc.arg(source_id)::uuid AND household_id = sqlc.arg(household_id)::uuid;
+
+-- name: CountIngestionJobsByStatus :many
+-- Used by the metrics collector, which reports across all households.
+SELECT status, count(*) AS jobs FROM ingestion_jobs GROUP BY status ORDER BY status;
@@ -0,0 +1,10 @@
+-- name: InsertRecipeAuditLog :one
+INSERT INTO recipe_audit_log (recipe_id, action, details)
+VALUES ($1, $2, $3)
+RETURNING id, recipe_id, action, details, created_at;
+
+-- name: ListRecipeAuditLog :many
+SELECT id, recipe_id, action, details, created_at
+FROM recipe_audit_log
+WHERE recipe_id = $1
+ORDER BY created_at;
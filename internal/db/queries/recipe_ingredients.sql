@@ -1,7 +1,17 @@
 -- name: ListIngredientsByRecipe :many
+-- Returns no ingredients unless the recipe is visible to the household.
 SELECT id, recipe_id, ingredient_id, quantity, unit, is_optional, preparation_notes
 FROM recipe_ingredients
-WHERE recipe_id = $1;
+WHERE recipe_id = sqlc.arg(recipe_id)::uuid
+  AND EXISTS (
+    SELECT 1 FROM recipes r
+    WHERE r.id = recipe_ingredients.recipe_id
+      AND (r.household_id = sqlc.arg(household_id)::uuid
+      OR r.visibility = 'public'
+      OR (r.visibility = 'shared' AND r.id IN (
+        SELECT sh.recipe_id FROM recipe_shares sh WHERE sh.household_id = sqlc.arg(household_id)::uuid
+      )))
+  );
 
 -- name: CreateRecipeIngredient :one
 INSERT INTO recipe_ingredients (recipe_id, ingredient_id, quantity, unit, is_optional, preparation_notes)
@@ -10,3 +20,17 @@ RETURNING id, recipe_id, ingredient_id, quantity, unit, is_optional, preparation
 
 -- name: DeleteIngredientsByRecipe :exec
 DELETE FROM recipe_ingredients WHERE recipe_id = $1;
+
+-- name: GetRecipeIngredient :one
+SELECT id, recipe_id, ingredient_id, quantity, unit, is_optional, preparation_notes
+FROM recipe_ingredients
+WHERE recipe_id = $1 AND id = $2;
+
+-- name: UpdateRecipeIngredient :one
+UPDATE recipe_ingredients
+SET ingredient_id = $3, quantity = $4, unit = $5, is_optional = $6, preparation_notes = $7
+WHERE recipe_id = $1 AND id = $2
+RETURNING id, recipe_id, ingredient_id, quantity, unit, is_optional, preparation_notes;
+
+-- name: DeleteRecipeIngredient :execrows
+DELETE FROM recipe_ingredients WHERE recipe_id = $1 AND id = $2;
@@ -0,0 +1,41 @@
+-- name: InsertRecipeRevision :one
+-- Inserts nothing unless the household owns the recipe.
+INSERT INTO recipe_revisions (recipe_id, revision, snapshot)
+SELECT sqlc.arg(recipe_id)::uuid, COALESCE(MAX(revision), 0) + 1, sqlc.arg(snapshot)::jsonb
+FROM recipe_revisions
+WHERE recipe_id = sqlc.arg(recipe_id)::uuid
+HAVING EXISTS (
+  SELECT 1 FROM recipes r
+  WHERE r.id = sqlc.arg(recipe_id)::uuid AND r.household_id = sqlc.arg(household_id)::uuid
+)
+RETURNING id, recipe_id, revision, snapshot, created_at;
+
+-- name: ListRecipeRevisions :many
+-- Returns no revisions unless the recipe is visible to the household.
+SELECT id, recipe_id, revision, snapshot, created_at
+FROM recipe_revisions
+WHERE recipe_id = sqlc.arg(recipe_id)::uuid
+  AND EXISTS (
+    SELECT 1 FROM recipes r
+    WHERE r.id = recipe_revisions.recipe_id
+      AND (r.household_id = sqlc.arg(household_id)::uuid
+      OR r.visibility = 'public'
+      OR (r.visibility = 'shared' AND r.id IN (
+        SELECT sh.recipe_id FROM recipe_shares sh WHERE sh.household_id = sqlc.arg(household_id)::uuid
+      )))
+  )
+ORDER BY revision DESC;
+
+-- name: GetRecipeRevision :one
+SELECT id, recipe_id, revision, snapshot, created_at
+FROM recipe_revisions
+WHERE recipe_id = sqlc.arg(recipe_id)::uuid AND revision = sqlc.arg(revision)::int
+  AND EXISTS (
+    SELECT 1 FROM recipes r
+    WHERE r.id = recipe_revisions.recipe_id
+      AND (r.household_id = sqlc.arg(household_id)::uuid
+      OR r.visibility = 'public'
+      OR (r.visibility = 'shared' AND r.id IN (
+        SELECT sh.recipe_id FROM recipe_shares sh WHERE sh.household_id = sqlc.arg(household_id)::uuid
+      )))
+  );
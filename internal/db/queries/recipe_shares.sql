@@ -0,0 +1,29 @@
+-- name: AddRecipeShare :exec
+INSERT INTO recipe_shares (recipe_id, household_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveRecipeShare :execrows
+DELETE FROM recipe_shares WHERE recipe_id = $1 AND household_id = $2;
+
+-- name: ListRecipeShares :many
+SELECT recipe_id, household_id, created_at
+FROM recipe_shares
+WHERE recipe_id = $1
+ORDER BY created_at;
+
+-- name: RepointRecipeShares :execrows
+-- Moves the source recipe's shares onto the target, e.g. when merging.
+-- Households the target is already shared with are skipped.
+WITH moved AS (
+    DELETE FROM recipe_shares
+    WHERE recipe_id = sqlc.arg(source_id)
+      AND EXISTS (
+          SELECT 1 FROM recipes r
+          WHERE r.id = recipe_shares.recipe_id AND r.household_id = sqlc.arg(household_id)
+      )
+    RETURNING household_id
+)
+INSERT INTO recipe_shares (recipe_id, household_id)
+SELECT sqlc.arg(target_id)::uuid, household_id FROM moved
+ON CONFLICT DO NOTHING;
@@ -1,7 +1,17 @@
 -- name: ListStepsByRecipe :many
+-- Returns no steps unless the recipe is visible to the household.
 SELECT id, recipe_id, step_number, instruction
 FROM recipe_steps
-WHERE recipe_id = $1
+WHERE recipe_id = sqlc.arg(recipe_id)::uuid
+  AND EXISTS (
+    SELECT 1 FROM recipes r
+    WHERE r.id = recipe_steps.recipe_id
+      AND (r.household_id = sqlc.arg(household_id)::uuid
+      OR r.visibility = 'public'
+      OR (r.visibility = 'shared' AND r.id IN (
+        SELECT sh.recipe_id FROM recipe_shares sh WHERE sh.household_id = sqlc.arg(household_id)::uuid
+      )))
+  )
 ORDER BY step_number;
 
 -- name: CreateStep :one
@@ -11,3 +21,38 @@ RETURNING id, recipe_id, step_number, instruction;
 
 -- name: DeleteStepsByRecipe :exec
 DELETE FROM recipe_steps WHERE recipe_id = $1;
+
+-- name: GetStep :one
+SELECT id, recipe_id, step_number, instruction
+FROM recipe_steps
+WHERE recipe_id = $1 AND step_number = $2;
+
+-- name: GetMaxStepNumber :one
+SELECT COALESCE(MAX(step_number), 0)::int AS max_step_number
+FROM recipe_steps
+WHERE recipe_id = $1;
+
+-- name: UpdateStepInstruction :one
+UPDATE recipe_steps
+SET instruction = $3
+WHERE recipe_id = $1 AND step_number = $2
+RETURNING id, recipe_id, step_number, instruction;
+
+-- name: ShiftStepsFrom :exec
+UPDATE recipe_steps
+SET step_number = step_number + sqlc.arg(delta)::int
+WHERE recipe_id = sqlc.arg(recipe_id)::uuid AND step_number >= sqlc.arg(from_step)::int;
+
+-- name: MoveStep :exec
+UPDATE recipe_steps
+SET step_number = CASE
+    WHEN step_number = sqlc.arg(from_step)::int THEN sqlc.arg(to_step)::int
+    WHEN sqlc.arg(from_step)::int < sqlc.arg(to_step)::int THEN step_number - 1
+    ELSE step_number + 1
+END
+WHERE recipe_id = sqlc.arg(recipe_id)::uuid
+  AND step_number BETWEEN LEAST(sqlc.arg(from_step)::int, sqlc.arg(to_step)::int)
+                      AND GREATEST(sqlc.arg(from_step)::int, sqlc.arg(to_step)::int);
+
+-- name: DeleteStep :execrows
+DELETE FROM recipe_steps WHERE recipe_id = $1 AND step_number = $2;
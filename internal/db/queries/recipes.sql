@@ -1,41 +1,125 @@
+-- Reads see the caller's own recipes plus recipes other households made
+-- public or shared with it. Writes only ever touch the caller's own recipes.
+
 -- name: ListRecipes :many
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 FROM recipes
+WHERE deleted_at IS NULL
+  AND (household_id = sqlc.arg(household_id)::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = sqlc.arg(household_id)::uuid
+    )))
 ORDER BY created_at DESC;
 
 -- name: GetRecipe :one
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
-FROM recipes WHERE id = $1;
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+FROM recipes
+WHERE id = sqlc.arg(id)::uuid AND deleted_at IS NULL
+  AND (household_id = sqlc.arg(household_id)::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = sqlc.arg(household_id)::uuid
+    )));
+
+-- name: LockRecipe :one
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+FROM recipes
+WHERE id = sqlc.arg(id)::uuid AND household_id = sqlc.arg(household_id)::uuid AND deleted_at IS NULL
+FOR UPDATE;
 
 -- name: CreateRecipe :one
-INSERT INTO recipes (title, description, source_url, servings, prep_minutes, cook_minutes, tags)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at;
+INSERT INTO recipes (
+  title, description, source_url, servings, prep_minutes, cook_minutes, tags, household_id, visibility,
+  created_by, updated_by
+)
+VALUES (
+  sqlc.arg(title)::text, sqlc.narg(description)::text, sqlc.narg(source_url)::text, sqlc.narg(servings)::int,
+  sqlc.narg(prep_minutes)::int, sqlc.narg(cook_minutes)::int, sqlc.arg(tags)::text[], sqlc.arg(household_id)::uuid,
+  COALESCE(NULLIF(sqlc.arg(visibility)::text, ''), 'private'), sqlc.narg(created_by)::text, sqlc.narg(created_by)::text
+)
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by;
 
 -- name: UpdateRecipe :one
 UPDATE recipes
-SET title = $2, description = $3, source_url = $4, servings = $5,
-    prep_minutes = $6, cook_minutes = $7, tags = $8, updated_at = now()
-WHERE id = $1
-RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at;
+SET title = sqlc.arg(title)::text, description = sqlc.narg(description)::text, source_url = sqlc.narg(source_url)::text,
+    servings = sqlc.narg(servings)::int, prep_minutes = sqlc.narg(prep_minutes)::int,
+    cook_minutes = sqlc.narg(cook_minutes)::int, tags = sqlc.arg(tags)::text[],
+    visibility = COALESCE(NULLIF(sqlc.arg(visibility)::text, ''), visibility),
+    updated_at = now(), updated_by = sqlc.narg(updated_by)::text, version = version + 1
+WHERE id = sqlc.arg(id)::uuid AND household_id = sqlc.arg(household_id)::uuid AND deleted_at IS NULL
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by;
+
+-- name: SoftDeleteRecipe :one
+UPDATE recipes
+SET deleted_at = now(), updated_at = now(), updated_by = $3, version = version + 1
+WHERE id = $1 AND household_id = $2 AND deleted_at IS NULL
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by;
 
--- name: DeleteRecipe :exec
-DELETE FROM recipes WHERE id = $1;
+-- name: RestoreRecipe :one
+UPDATE recipes
+SET deleted_at = NULL, updated_at = now(), updated_by = $3, version = version + 1
+WHERE id = $1 AND household_id = $2 AND deleted_at IS NOT NULL
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by;
+
+-- name: ListTrashedRecipes :many
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+FROM recipes
+WHERE household_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeDeletedRecipes :execrows
+-- Runs for every household: retention is a property of the deployment.
+DELETE FROM recipes
+WHERE deleted_at < sqlc.arg(cutoff)::timestamptz;
 
 -- name: ListRecipesByTag :many
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 FROM recipes
-WHERE $1 = ANY(tags)
+WHERE sqlc.arg(tags) = ANY(tags) AND deleted_at IS NULL
+  AND (household_id = sqlc.arg(household_id)::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = sqlc.arg(household_id)::uuid
+    )))
 ORDER BY created_at DESC;
 
 -- name: ListRecipesByCookTime :many
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 FROM recipes
-WHERE cook_minutes <= $1
+WHERE cook_minutes <= sqlc.arg(cook_minutes) AND deleted_at IS NULL
+  AND (household_id = sqlc.arg(household_id)::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = sqlc.arg(household_id)::uuid
+    )))
 ORDER BY created_at DESC;
 
 -- name: ListRecipesByTitle :many
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 FROM recipes
-WHERE title ILIKE '%' || $1 || '%'
+WHERE title ILIKE '%' || sqlc.arg(title)::text || '%' AND deleted_at IS NULL
+  AND (household_id = sqlc.arg(household_id)::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = sqlc.arg(household_id)::uuid
+    )))
 ORDER BY created_at DESC;
+
+-- name: TouchRecipe :one
+UPDATE recipes
+SET updated_at = now(), updated_by = $3, version = version + 1
+WHERE id = $1 AND household_id = $2 AND deleted_at IS NULL
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by;
@@ -58,14 +58,72 @@ func (q *Queries) DeleteIngredientsByRecipe(ctx context.Context, recipeID uuid.U
 	return err
 }
 
+const deleteRecipeIngredient = `-- name: DeleteRecipeIngredient :execrows
+DELETE FROM recipe_ingredients WHERE recipe_id = $1 AND id = $2
+`
+
+type DeleteRecipeIngredientParams struct {
+	RecipeID uuid.UUID
+	ID       uuid.UUID
+}
+
+func (q *Queries) DeleteRecipeIngredient(ctx context.Context, arg DeleteRecipeIngredientParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRecipeIngredient, arg.RecipeID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getRecipeIngredient = `-- name: GetRecipeIngredient :one
+SELECT id, recipe_id, ingredient_id, quantity, unit, is_optional, preparation_notes
+FROM recipe_ingredients
+WHERE recipe_id = $1 AND id = $2
+`
+
+type GetRecipeIngredientParams struct {
+	RecipeID uuid.UUID
+	ID       uuid.UUID
+}
+
+func (q *Queries) GetRecipeIngredient(ctx context.Context, arg GetRecipeIngredientParams) (RecipeIngredient, error) {
+	row := q.db.QueryRowContext(ctx, getRecipeIngredient, arg.RecipeID, arg.ID)
+	var i RecipeIngredient
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.IngredientID,
+		&i.Quantity,
+		&i.Unit,
+		&i.IsOptional,
+		&i.PreparationNotes,
+	)
+	return i, err
+}
+
 const listIngredientsByRecipe = `-- name: ListIngredientsByRecipe :many
 SELECT id, recipe_id, ingredient_id, quantity, unit, is_optional, preparation_notes
 FROM recipe_ingredients
-WHERE recipe_id = $1
+WHERE recipe_id = $1::uuid
+  AND EXISTS (
+    SELECT 1 FROM recipes r
+    WHERE r.id = recipe_ingredients.recipe_id
+      AND (r.household_id = $2::uuid
+      OR r.visibility = 'public'
+      OR (r.visibility = 'shared' AND r.id IN (
+        SELECT sh.recipe_id FROM recipe_shares sh WHERE sh.household_id = $2::uuid
+      )))
+  )
 `
 
-func (q *Queries) ListIngredientsByRecipe(ctx context.Context, recipeID uuid.UUID) ([]RecipeIngredient, error) {
-	rows, err := q.db.QueryContext(ctx, listIngredientsByRecipe, recipeID)
+type ListIngredientsByRecipeParams struct {
+	RecipeID    uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+// Returns no ingredients unless the recipe is visible to the household.
+func (q *Queries) ListIngredientsByRecipe(ctx context.Context, arg ListIngredientsByRecipeParams) ([]RecipeIngredient, error) {
+	rows, err := q.db.QueryContext(ctx, listIngredientsByRecipe, arg.RecipeID, arg.HouseholdID)
 	if err != nil {
 		return nil, err
 	}
@@ -94,3 +152,43 @@ func (q *Queries) ListIngredientsByRecipe(ctx context.Context, recipeID uuid.UUI
 	}
 	return items, nil
 }
+
+const updateRecipeIngredient = `-- name: UpdateRecipeIngredient :one
+UPDATE recipe_ingredients
+SET ingredient_id = $3, quantity = $4, unit = $5, is_optional = $6, preparation_notes = $7
+WHERE recipe_id = $1 AND id = $2
+RETURNING id, recipe_id, ingredient_id, quantity, unit, is_optional, preparation_notes
+`
+
+type UpdateRecipeIngredientParams struct {
+	RecipeID         uuid.UUID
+	ID               uuid.UUID
+	IngredientID     uuid.UUID
+	Quantity         sql.NullFloat64
+	Unit             sql.NullString
+	IsOptional       bool
+	PreparationNotes sql.NullString
+}
+
+func (q *Queries) UpdateRecipeIngredient(ctx context.Context, arg UpdateRecipeIngredientParams) (RecipeIngredient, error) {
+	row := q.db.QueryRowContext(ctx, updateRecipeIngredient,
+		arg.RecipeID,
+		arg.ID,
+		arg.IngredientID,
+		arg.Quantity,
+		arg.Unit,
+		arg.IsOptional,
+		arg.PreparationNotes,
+	)
+	var i RecipeIngredient
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.IngredientID,
+		&i.Quantity,
+		&i.Unit,
+		&i.IsOptional,
+		&i.PreparationNotes,
+	)
+	return i, err
+}
@@ -35,6 +35,23 @@ func (q *Queries) CreateStep(ctx context.Context, arg CreateStepParams) (RecipeS
 	return i, err
 }
 
+const deleteStep = `-- name: DeleteStep :execrows
+DELETE FROM recipe_steps WHERE recipe_id = $1 AND step_number = $2
+`
+
+type DeleteStepParams struct {
+	RecipeID   uuid.UUID
+	StepNumber int32
+}
+
+func (q *Queries) DeleteStep(ctx context.Context, arg DeleteStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteStep, arg.RecipeID, arg.StepNumber)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteStepsByRecipe = `-- name: DeleteStepsByRecipe :exec
 DELETE FROM recipe_steps WHERE recipe_id = $1
 `
@@ -44,15 +61,66 @@ func (q *Queries) DeleteStepsByRecipe(ctx context.Context, recipeID uuid.UUID) e
 	return err
 }
 
+const getMaxStepNumber = `-- name: GetMaxStepNumber :one
+SELECT COALESCE(MAX(step_number), 0)::int AS max_step_number
+FROM recipe_steps
+WHERE recipe_id = $1
+`
+
+func (q *Queries) GetMaxStepNumber(ctx context.Context, recipeID uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getMaxStepNumber, recipeID)
+	var maxStepNumber int32
+	err := row.Scan(&maxStepNumber)
+	return maxStepNumber, err
+}
+
+const getStep = `-- name: GetStep :one
+SELECT id, recipe_id, step_number, instruction
+FROM recipe_steps
+WHERE recipe_id = $1 AND step_number = $2
+`
+
+type GetStepParams struct {
+	RecipeID   uuid.UUID
+	StepNumber int32
+}
+
+func (q *Queries) GetStep(ctx context.Context, arg GetStepParams) (RecipeStep, error) {
+	row := q.db.QueryRowContext(ctx, getStep, arg.RecipeID, arg.StepNumber)
+	var i RecipeStep
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.StepNumber,
+		&i.Instruction,
+	)
+	return i, err
+}
+
 const listStepsByRecipe = `-- name: ListStepsByRecipe :many
 SELECT id, recipe_id, step_number, instruction
 FROM recipe_steps
-WHERE recipe_id = $1
+WHERE recipe_id = $1::uuid
+  AND EXISTS (
+    SELECT 1 FROM recipes r
+    WHERE r.id = recipe_steps.recipe_id
+      AND (r.household_id = $2::uuid
+      OR r.visibility = 'public'
+      OR (r.visibility = 'shared' AND r.id IN (
+        SELECT sh.recipe_id FROM recipe_shares sh WHERE sh.household_id = $2::uuid
+      )))
+  )
 ORDER BY step_number
 `
 
-func (q *Queries) ListStepsByRecipe(ctx context.Context, recipeID uuid.UUID) ([]RecipeStep, error) {
-	rows, err := q.db.QueryContext(ctx, listStepsByRecipe, recipeID)
+type ListStepsByRecipeParams struct {
+	RecipeID    uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+// Returns no steps unless the recipe is visible to the household.
+func (q *Queries) ListStepsByRecipe(ctx context.Context, arg ListStepsByRecipeParams) ([]RecipeStep, error) {
+	rows, err := q.db.QueryContext(ctx, listStepsByRecipe, arg.RecipeID, arg.HouseholdID)
 	if err != nil {
 		return nil, err
 	}
@@ -78,3 +146,68 @@ func (q *Queries) ListStepsByRecipe(ctx context.Context, recipeID uuid.UUID) ([]
 	}
 	return items, nil
 }
+
+const moveStep = `-- name: MoveStep :exec
+UPDATE recipe_steps
+SET step_number = CASE
+    WHEN step_number = $1::int THEN $2::int
+    WHEN $1::int < $2::int THEN step_number - 1
+    ELSE step_number + 1
+END
+WHERE recipe_id = $3::uuid
+  AND step_number BETWEEN LEAST($1::int, $2::int)
+                      AND GREATEST($1::int, $2::int)
+`
+
+type MoveStepParams struct {
+	FromStep int32
+	ToStep   int32
+	RecipeID uuid.UUID
+}
+
+func (q *Queries) MoveStep(ctx context.Context, arg MoveStepParams) error {
+	_, err := q.db.ExecContext(ctx, moveStep, arg.FromStep, arg.ToStep, arg.RecipeID)
+	return err
+}
+
+const shiftStepsFrom = `-- name: ShiftStepsFrom :exec
+UPDATE recipe_steps
+SET step_number = step_number + $1::int
+WHERE recipe_id = $2::uuid AND step_number >= $3::int
+`
+
+type ShiftStepsFromParams struct {
+	Delta    int32
+	RecipeID uuid.UUID
+	FromStep int32
+}
+
+func (q *Queries) ShiftStepsFrom(ctx context.Context, arg ShiftStepsFromParams) error {
+	_, err := q.db.ExecContext(ctx, shiftStepsFrom, arg.Delta, arg.RecipeID, arg.FromStep)
+	return err
+}
+
+const updateStepInstruction = `-- name: UpdateStepInstruction :one
+UPDATE recipe_steps
+SET instruction = $3
+WHERE recipe_id = $1 AND step_number = $2
+RETURNING id, recipe_id, step_number, instruction
+`
+
+type UpdateStepInstructionParams struct {
+	RecipeID    uuid.UUID
+	StepNumber  int32
+	Instruction string
+}
+
+func (q *Queries) UpdateStepInstruction(ctx context.Context, arg UpdateStepInstructionParams) (RecipeStep, error) {
+	row := q.db.QueryRowContext(ctx, updateStepInstruction, arg.RecipeID, arg.StepNumber, arg.Instruction)
+	var i RecipeStep
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.StepNumber,
+		&i.Instruction,
+	)
+	return i, err
+}
@@ -8,15 +8,24 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 const createRecipe = `-- name: CreateRecipe :one
-INSERT INTO recipes (title, description, source_url, servings, prep_minutes, cook_minutes, tags)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+INSERT INTO recipes (
+  title, description, source_url, servings, prep_minutes, cook_minutes, tags, household_id, visibility,
+  created_by, updated_by
+)
+VALUES (
+  $1::text, $2::text, $3::text, $4::int,
+  $5::int, $6::int, $7::text[], $8::uuid,
+  COALESCE(NULLIF($9::text, ''), 'private'), $10::text, $10::text
+)
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 `
 
 type CreateRecipeParams struct {
@@ -27,6 +36,9 @@ type CreateRecipeParams struct {
 	PrepMinutes sql.NullInt32
 	CookMinutes sql.NullInt32
 	Tags        []string
+	HouseholdID uuid.UUID
+	Visibility  string
+	CreatedBy   sql.NullString
 }
 
 func (q *Queries) CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Recipe, error) {
@@ -38,6 +50,9 @@ func (q *Queries) CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Rec
 		arg.PrepMinutes,
 		arg.CookMinutes,
 		pq.Array(arg.Tags),
+		arg.HouseholdID,
+		arg.Visibility,
+		arg.CreatedBy,
 	)
 	var i Recipe
 	err := row.Scan(
@@ -51,26 +66,35 @@ func (q *Queries) CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Rec
 		pq.Array(&i.Tags),
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.HouseholdID,
+		&i.Visibility,
+		&i.CreatedBy,
+		&i.UpdatedBy,
 	)
 	return i, err
 }
 
-const deleteRecipe = `-- name: DeleteRecipe :exec
-DELETE FROM recipes WHERE id = $1
+const getRecipe = `-- name: GetRecipe :one
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+FROM recipes
+WHERE id = $1::uuid AND deleted_at IS NULL
+  AND (household_id = $2::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = $2::uuid
+    )))
 `
 
-func (q *Queries) DeleteRecipe(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteRecipe, id)
-	return err
+type GetRecipeParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
 }
 
-const getRecipe = `-- name: GetRecipe :one
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
-FROM recipes WHERE id = $1
-`
-
-func (q *Queries) GetRecipe(ctx context.Context, id uuid.UUID) (Recipe, error) {
-	row := q.db.QueryRowContext(ctx, getRecipe, id)
+func (q *Queries) GetRecipe(ctx context.Context, arg GetRecipeParams) (Recipe, error) {
+	row := q.db.QueryRowContext(ctx, getRecipe, arg.ID, arg.HouseholdID)
 	var i Recipe
 	err := row.Scan(
 		&i.ID,
@@ -83,18 +107,31 @@ func (q *Queries) GetRecipe(ctx context.Context, id uuid.UUID) (Recipe, error) {
 		pq.Array(&i.Tags),
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.HouseholdID,
+		&i.Visibility,
+		&i.CreatedBy,
+		&i.UpdatedBy,
 	)
 	return i, err
 }
 
 const listRecipes = `-- name: ListRecipes :many
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 FROM recipes
+WHERE deleted_at IS NULL
+  AND (household_id = $1::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = $1::uuid
+    )))
 ORDER BY created_at DESC
 `
 
-func (q *Queries) ListRecipes(ctx context.Context) ([]Recipe, error) {
-	rows, err := q.db.QueryContext(ctx, listRecipes)
+func (q *Queries) ListRecipes(ctx context.Context, householdID uuid.UUID) ([]Recipe, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipes, householdID)
 	if err != nil {
 		return nil, err
 	}
@@ -113,6 +150,12 @@ func (q *Queries) ListRecipes(ctx context.Context) ([]Recipe, error) {
 			pq.Array(&i.Tags),
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+			&i.HouseholdID,
+			&i.Visibility,
+			&i.CreatedBy,
+			&i.UpdatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -128,14 +171,25 @@ func (q *Queries) ListRecipes(ctx context.Context) ([]Recipe, error) {
 }
 
 const listRecipesByCookTime = `-- name: ListRecipesByCookTime :many
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 FROM recipes
-WHERE cook_minutes <= $1
+WHERE cook_minutes <= $1 AND deleted_at IS NULL
+  AND (household_id = $2::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = $2::uuid
+    )))
 ORDER BY created_at DESC
 `
 
-func (q *Queries) ListRecipesByCookTime(ctx context.Context, cookMinutes sql.NullInt32) ([]Recipe, error) {
-	rows, err := q.db.QueryContext(ctx, listRecipesByCookTime, cookMinutes)
+type ListRecipesByCookTimeParams struct {
+	CookMinutes sql.NullInt32
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) ListRecipesByCookTime(ctx context.Context, arg ListRecipesByCookTimeParams) ([]Recipe, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipesByCookTime, arg.CookMinutes, arg.HouseholdID)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +208,12 @@ func (q *Queries) ListRecipesByCookTime(ctx context.Context, cookMinutes sql.Nul
 			pq.Array(&i.Tags),
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+			&i.HouseholdID,
+			&i.Visibility,
+			&i.CreatedBy,
+			&i.UpdatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -169,14 +229,25 @@ func (q *Queries) ListRecipesByCookTime(ctx context.Context, cookMinutes sql.Nul
 }
 
 const listRecipesByTag = `-- name: ListRecipesByTag :many
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 FROM recipes
-WHERE $1 = ANY(tags)
+WHERE $1 = ANY(tags) AND deleted_at IS NULL
+  AND (household_id = $2::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = $2::uuid
+    )))
 ORDER BY created_at DESC
 `
 
-func (q *Queries) ListRecipesByTag(ctx context.Context, tags []string) ([]Recipe, error) {
-	rows, err := q.db.QueryContext(ctx, listRecipesByTag, pq.Array(tags))
+type ListRecipesByTagParams struct {
+	Tags        []string
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) ListRecipesByTag(ctx context.Context, arg ListRecipesByTagParams) ([]Recipe, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipesByTag, pq.Array(arg.Tags), arg.HouseholdID)
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +266,12 @@ func (q *Queries) ListRecipesByTag(ctx context.Context, tags []string) ([]Recipe
 			pq.Array(&i.Tags),
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+			&i.HouseholdID,
+			&i.Visibility,
+			&i.CreatedBy,
+			&i.UpdatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -210,14 +287,25 @@ func (q *Queries) ListRecipesByTag(ctx context.Context, tags []string) ([]Recipe
 }
 
 const listRecipesByTitle = `-- name: ListRecipesByTitle :many
-SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 FROM recipes
-WHERE title ILIKE '%' || $1 || '%'
+WHERE title ILIKE '%' || $1::text || '%' AND deleted_at IS NULL
+  AND (household_id = $2::uuid
+    OR visibility = 'public'
+    OR (visibility = 'shared' AND id IN (
+      SELECT s.recipe_id FROM recipe_shares s WHERE s.household_id = $2::uuid
+    )))
 ORDER BY created_at DESC
 `
 
-func (q *Queries) ListRecipesByTitle(ctx context.Context, dollar_1 sql.NullString) ([]Recipe, error) {
-	rows, err := q.db.QueryContext(ctx, listRecipesByTitle, dollar_1)
+type ListRecipesByTitleParams struct {
+	Title       string
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) ListRecipesByTitle(ctx context.Context, arg ListRecipesByTitleParams) ([]Recipe, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipesByTitle, arg.Title, arg.HouseholdID)
 	if err != nil {
 		return nil, err
 	}
@@ -236,6 +324,12 @@ func (q *Queries) ListRecipesByTitle(ctx context.Context, dollar_1 sql.NullStrin
 			pq.Array(&i.Tags),
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+			&i.HouseholdID,
+			&i.Visibility,
+			&i.CreatedBy,
+			&i.UpdatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -250,16 +344,232 @@ func (q *Queries) ListRecipesByTitle(ctx context.Context, dollar_1 sql.NullStrin
 	return items, nil
 }
 
+const listTrashedRecipes = `-- name: ListTrashedRecipes :many
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+FROM recipes
+WHERE household_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListTrashedRecipes(ctx context.Context, householdID uuid.UUID) ([]Recipe, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedRecipes, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Recipe
+	for rows.Next() {
+		var i Recipe
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.SourceUrl,
+			&i.Servings,
+			&i.PrepMinutes,
+			&i.CookMinutes,
+			pq.Array(&i.Tags),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+			&i.HouseholdID,
+			&i.Visibility,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockRecipe = `-- name: LockRecipe :one
+SELECT id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+FROM recipes
+WHERE id = $1::uuid AND household_id = $2::uuid AND deleted_at IS NULL
+FOR UPDATE
+`
+
+type LockRecipeParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+}
+
+func (q *Queries) LockRecipe(ctx context.Context, arg LockRecipeParams) (Recipe, error) {
+	row := q.db.QueryRowContext(ctx, lockRecipe, arg.ID, arg.HouseholdID)
+	var i Recipe
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SourceUrl,
+		&i.Servings,
+		&i.PrepMinutes,
+		&i.CookMinutes,
+		pq.Array(&i.Tags),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.HouseholdID,
+		&i.Visibility,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const purgeDeletedRecipes = `-- name: PurgeDeletedRecipes :execrows
+DELETE FROM recipes
+WHERE deleted_at < $1::timestamptz
+`
+
+// Runs for every household: retention is a property of the deployment.
+func (q *Queries) PurgeDeletedRecipes(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedRecipes, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreRecipe = `-- name: RestoreRecipe :one
+UPDATE recipes
+SET deleted_at = NULL, updated_at = now(), updated_by = $3, version = version + 1
+WHERE id = $1 AND household_id = $2 AND deleted_at IS NOT NULL
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+`
+
+type RestoreRecipeParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	UpdatedBy   sql.NullString
+}
+
+func (q *Queries) RestoreRecipe(ctx context.Context, arg RestoreRecipeParams) (Recipe, error) {
+	row := q.db.QueryRowContext(ctx, restoreRecipe, arg.ID, arg.HouseholdID, arg.UpdatedBy)
+	var i Recipe
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SourceUrl,
+		&i.Servings,
+		&i.PrepMinutes,
+		&i.CookMinutes,
+		pq.Array(&i.Tags),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.HouseholdID,
+		&i.Visibility,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const softDeleteRecipe = `-- name: SoftDeleteRecipe :one
+UPDATE recipes
+SET deleted_at = now(), updated_at = now(), updated_by = $3, version = version + 1
+WHERE id = $1 AND household_id = $2 AND deleted_at IS NULL
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+`
+
+type SoftDeleteRecipeParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	UpdatedBy   sql.NullString
+}
+
+func (q *Queries) SoftDeleteRecipe(ctx context.Context, arg SoftDeleteRecipeParams) (Recipe, error) {
+	row := q.db.QueryRowContext(ctx, softDeleteRecipe, arg.ID, arg.HouseholdID, arg.UpdatedBy)
+	var i Recipe
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SourceUrl,
+		&i.Servings,
+		&i.PrepMinutes,
+		&i.CookMinutes,
+		pq.Array(&i.Tags),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.HouseholdID,
+		&i.Visibility,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const touchRecipe = `-- name: TouchRecipe :one
+UPDATE recipes
+SET updated_at = now(), updated_by = $3, version = version + 1
+WHERE id = $1 AND household_id = $2 AND deleted_at IS NULL
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
+`
+
+type TouchRecipeParams struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	UpdatedBy   sql.NullString
+}
+
+func (q *Queries) TouchRecipe(ctx context.Context, arg TouchRecipeParams) (Recipe, error) {
+	row := q.db.QueryRowContext(ctx, touchRecipe, arg.ID, arg.HouseholdID, arg.UpdatedBy)
+	var i Recipe
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.SourceUrl,
+		&i.Servings,
+		&i.PrepMinutes,
+		&i.CookMinutes,
+		pq.Array(&i.Tags),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.HouseholdID,
+		&i.Visibility,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
 const updateRecipe = `-- name: UpdateRecipe :one
 UPDATE recipes
-SET title = $2, description = $3, source_url = $4, servings = $5,
-    prep_minutes = $6, cook_minutes = $7, tags = $8, updated_at = now()
-WHERE id = $1
-RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at
+SET title = $1::text, description = $2::text, source_url = $3::text,
+    servings = $4::int, prep_minutes = $5::int,
+    cook_minutes = $6::int, tags = $7::text[],
+    visibility = COALESCE(NULLIF($8::text, ''), visibility),
+    updated_at = now(), updated_by = $9::text, version = version + 1
+WHERE id = $10::uuid AND household_id = $11::uuid AND deleted_at IS NULL
+RETURNING id, title, description, source_url, servings, prep_minutes, cook_minutes, tags, created_at, updated_at, version, deleted_at,
+    household_id, visibility, created_by, updated_by
 `
 
 type UpdateRecipeParams struct {
-	ID          uuid.UUID
 	Title       string
 	Description sql.NullString
 	SourceUrl   sql.NullString
@@ -267,11 +577,14 @@ type UpdateRecipeParams struct {
 	PrepMinutes sql.NullInt32
 	CookMinutes sql.NullInt32
 	Tags        []string
+	Visibility  string
+	UpdatedBy   sql.NullString
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
 }
 
 func (q *Queries) UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Recipe, error) {
 	row := q.db.QueryRowContext(ctx, updateRecipe,
-		arg.ID,
 		arg.Title,
 		arg.Description,
 		arg.SourceUrl,
@@ -279,6 +592,10 @@ func (q *Queries) UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Rec
 		arg.PrepMinutes,
 		arg.CookMinutes,
 		pq.Array(arg.Tags),
+		arg.Visibility,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.HouseholdID,
 	)
 	var i Recipe
 	err := row.Scan(
@@ -292,6 +609,12 @@ func (q *Queries) UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Rec
 		pq.Array(&i.Tags),
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
+		&i.HouseholdID,
+		&i.Visibility,
+		&i.CreatedBy,
+		&i.UpdatedBy,
 	)
 	return i, err
 }
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// LatestMigration returns the version of the newest migration in
+// MigrationsFS.
+func LatestMigration() (uint, error) {
+	names, err := fs.Glob(MigrationsFS, "migrations/*.up.sql")
+	if err != nil {
+		return 0, fmt.Errorf("list migrations: %w", err)
+	}
+	var latest uint64
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(strings.TrimPrefix(name, "migrations/"), "_")
+		v, err := strconv.ParseUint(prefix, 10, 0)
+		if err != nil {
+			return 0, fmt.Errorf("parse migration version of %s: %w", name, err)
+		}
+		latest = max(latest, v)
+	}
+	return uint(latest), nil
+}
+
+// SchemaVersion returns the migration version recorded in schema_migrations
+// and whether a migration to it failed part-way. A database that has never
+// been migrated is at version 0.
+func SchemaVersion(ctx context.Context, db DBTX) (version uint, dirty bool, err error) {
+	var v int64
+	err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&v, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
+	}
+	return uint(v), dirty, nil //nolint:gosec // migration versions are never negative.
+}
+
+// CheckSchema returns an error if the database is dirty or behind
+// LatestMigration. A schema ahead of it is accepted: migrations stay
+// compatible with the previous release, which keeps serving while a rollout
+// that migrated first replaces it.
+func CheckSchema(ctx context.Context, db DBTX) error {
+	want, err := LatestMigration()
+	if err != nil {
+		return err
+	}
+	got, dirty, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	switch {
+	case dirty:
+		return fmt.Errorf("schema version %d is dirty", got)
+	case got < want:
+		return fmt.Errorf("schema version is %d, want %d", got, want)
+	}
+	return nil
+}
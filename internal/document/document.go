@@ -0,0 +1,91 @@
+// Package document extracts plain text from uploaded recipe files so they can
+// be ingested like pasted text.
+package document
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format is a supported upload format.
+type Format string
+
+const (
+	FormatPDF      Format = "pdf"
+	FormatMarkdown Format = "markdown"
+	FormatText     Format = "text"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for files that are not PDF, Markdown
+	// or UTF-8 text.
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+
+	// ErrNoText is returned when a file has no extractable text, such as a
+	// scanned PDF without a text layer.
+	ErrNoText = errors.New("file contains no extractable text")
+
+	// ErrMalformed is returned for files too deeply nested or too large once
+	// decompressed to extract safely.
+	ErrMalformed = errors.New("file is malformed or too complex to read")
+)
+
+// ContentType returns the MIME type stored for files of format f.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatPDF:
+		return "application/pdf"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatText:
+		return "text/plain; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// Sniff determines a file's format from its content, using the filename and
+// declared content type only to tell Markdown from plain text. The declared
+// type is never trusted for binary formats.
+func Sniff(filename, declaredType string, data []byte) (Format, error) {
+	if bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF-")) {
+		return FormatPDF, nil
+	}
+
+	if !strings.HasPrefix(http.DetectContentType(data), "text/plain") || !utf8.Valid(data) {
+		return "", ErrUnsupportedFormat
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == ".md" || ext == ".markdown" || strings.HasPrefix(strings.ToLower(declaredType), "text/markdown") {
+		return FormatMarkdown, nil
+	}
+	return FormatText, nil
+}
+
+// Text extracts the readable text of data in format f.
+func Text(f Format, data []byte) (string, error) {
+	var text string
+	switch f {
+	case FormatPDF:
+		var err error
+		if text, err = PDFText(data); err != nil {
+			return "", err
+		}
+	case FormatMarkdown:
+		text = MarkdownText(string(data))
+	case FormatText:
+		text = strings.ReplaceAll(strings.TrimPrefix(string(data), "\uFEFF"), "\r\n", "\n")
+	default:
+		return "", ErrUnsupportedFormat
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
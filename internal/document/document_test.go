@@ -0,0 +1,182 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+func TestSniff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		declared string
+		data     []byte
+		want     Format
+		wantErr  error
+	}{
+		{name: "pdf magic", filename: "recipe.bin", data: []byte("%PDF-1.7\n..."), want: FormatPDF},
+		{name: "pdf despite declared text", filename: "a.txt", declared: "text/plain", data: []byte("%PDF-1.4"),
+			want: FormatPDF},
+		{name: "markdown extension", filename: "Pasta.MD", data: []byte("# Pasta"), want: FormatMarkdown},
+		{name: "markdown declared", filename: "pasta", declared: "text/markdown", data: []byte("# Pasta"),
+			want: FormatMarkdown},
+		{name: "plain text", filename: "pasta.txt", data: []byte("Pasta\n1 lb spaghetti"), want: FormatText},
+		{name: "png", filename: "pasta.md", data: []byte("\x89PNG\r\n\x1a\n\x00\x00"), wantErr: ErrUnsupportedFormat},
+		{name: "html", filename: "pasta.txt", data: []byte("<html><body>x</body></html>"),
+			wantErr: ErrUnsupportedFormat},
+		{name: "invalid utf8", filename: "pasta.txt", data: []byte("caf\xe9 au lait"), wantErr: ErrUnsupportedFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Sniff(tt.filename, tt.declared, tt.data)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestText_Markdown(t *testing.T) {
+	t.Parallel()
+
+	got, err := Text(FormatMarkdown, readFixture(t, "recipe.md"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `Garlic Butter Pasta
+
+A quick weeknight dinner from my blog.
+
+Ingredients
+
+- 1 lb spaghetti
+- 2 cloves garlic, minced
+- 1 1/2 tbsp butter
+
+Instructions
+
+1. Boil the pasta.
+2. Melt butter and fry the garlic.
+
+Toss everything together.`, got)
+}
+
+func TestText_PDF(t *testing.T) {
+	t.Parallel()
+
+	got, err := Text(FormatPDF, readFixture(t, "recipe.pdf"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `Lemon Bars
+Ingredients:
+1 cup flour
+2 eggs
+1/2 cup sugar
+Instructions:
+Bake the crust (20 min).
+Pour over filling.`, got)
+}
+
+func TestText_ScannedPDF(t *testing.T) {
+	t.Parallel()
+
+	_, err := Text(FormatPDF, readFixture(t, "scanned.pdf"))
+	require.ErrorIs(t, err, ErrNoText)
+}
+
+func TestText_PlainText(t *testing.T) {
+	t.Parallel()
+
+	got, err := Text(FormatText, []byte("\uFEFFToast\r\n2 slices bread\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "Toast\n2 slices bread", got)
+
+	_, err = Text(FormatText, []byte(" \n\t"))
+	require.ErrorIs(t, err, ErrNoText)
+}
+
+// flatePDF returns a PDF with one FlateDecode content stream per body.
+func flatePDF(t testing.TB, bodies ...[]byte) []byte {
+	t.Helper()
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	for i, body := range bodies {
+		var z bytes.Buffer
+		w := zlib.NewWriter(&z)
+		_, err := w.Write(body)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		fmt.Fprintf(&pdf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", i+1, z.Len())
+		pdf.Write(z.Bytes())
+		pdf.WriteString("\nendstream\nendobj\n")
+	}
+	pdf.WriteString("%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestText_PDFDeeplyNestedArrays(t *testing.T) {
+	t.Parallel()
+
+	content := "BT " + strings.Repeat("[", 8<<20)
+	_, err := Text(FormatPDF, flatePDF(t, []byte(content)))
+	require.ErrorIs(t, err, ErrMalformed)
+
+	nested := "BT [(Toast) " + strings.Repeat("[", maxArrayDepth-1) + strings.Repeat("]", maxArrayDepth) + " TJ ET"
+	got, err := Text(FormatPDF, flatePDF(t, []byte(nested)))
+	require.NoError(t, err, "arrays nested up to the cap still parse")
+	assert.Equal(t, "Toast", got)
+}
+
+func TestText_PDFInflatedSizeCap(t *testing.T) {
+	t.Parallel()
+
+	stream := bytes.Repeat([]byte{' '}, maxStreamBytes)
+	bodies := make([][]byte, maxInflatedBytes/maxStreamBytes+1)
+	for i := range bodies {
+		bodies[i] = stream
+	}
+	_, err := Text(FormatPDF, flatePDF(t, bodies...))
+	require.ErrorIs(t, err, ErrMalformed)
+}
+
+func FuzzExtractText(f *testing.F) {
+	for _, name := range []string{"recipe.pdf", "scanned.pdf", "recipe.md"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		require.NoError(f, err)
+		f.Add(name, data)
+	}
+	f.Add("nested.pdf", flatePDF(f, []byte("BT [[[(a) 1 (b)] -300] TJ (c) Tj ET")))
+	f.Add("notes.txt", []byte("Toast\n2 slices bread"))
+
+	f.Fuzz(func(t *testing.T, filename string, data []byte) {
+		format, err := Sniff(filename, "", data)
+		if err != nil {
+			return
+		}
+		text, err := Text(format, data)
+		if err == nil {
+			assert.NotEmpty(t, strings.TrimSpace(text))
+		}
+	})
+}
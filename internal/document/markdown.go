@@ -0,0 +1,52 @@
+package document
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mdFrontMatter = regexp.MustCompile(`(?s)\A---\r?\n(.*?)\r?\n---\r?\n`)
+	mdFrontTitle  = regexp.MustCompile(`(?m)^title:\s*["']?(.*?)["']?\s*$`)
+	mdImage       = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	mdLink        = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdHeading     = regexp.MustCompile(`^#{1,6}\s+`)
+	mdQuote       = regexp.MustCompile(`^(?:>\s?)+`)
+	mdStrong      = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdEmphasis    = regexp.MustCompile(`(^|[^\w*])[*_]([^*_\s](?:[^*_]*[^*_\s])?)[*_]`)
+	mdCode        = regexp.MustCompile("`([^`]*)`")
+	mdHTMLTag     = regexp.MustCompile(`<[^>]+>`)
+	mdFence       = regexp.MustCompile("^(?:```|~~~)")
+)
+
+// MarkdownText strips Markdown syntax, keeping the structure the recipe
+// extractor relies on: one block per line, heading text and list markers.
+// A YAML front matter title becomes the first line.
+func MarkdownText(src string) string {
+	src = strings.ReplaceAll(strings.TrimPrefix(src, "\uFEFF"), "\r\n", "\n")
+
+	var lines []string
+	if m := mdFrontMatter.FindStringSubmatch(src); m != nil {
+		if t := mdFrontTitle.FindStringSubmatch(m[1]); t != nil && t[1] != "" {
+			lines = append(lines, t[1])
+		}
+		src = src[len(m[0]):]
+	}
+
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if mdFence.MatchString(trimmed) {
+			continue
+		}
+		trimmed = mdQuote.ReplaceAllString(trimmed, "")
+		trimmed = mdHeading.ReplaceAllString(trimmed, "")
+		trimmed = mdImage.ReplaceAllString(trimmed, "")
+		trimmed = mdLink.ReplaceAllString(trimmed, "$1")
+		trimmed = mdCode.ReplaceAllString(trimmed, "$1")
+		trimmed = mdStrong.ReplaceAllString(trimmed, "$2")
+		trimmed = mdEmphasis.ReplaceAllString(trimmed, "$1$2")
+		trimmed = mdHTMLTag.ReplaceAllString(trimmed, "")
+		lines = append(lines, strings.TrimSpace(trimmed))
+	}
+	return collapseLines(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,411 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// maxStreamBytes caps the decompressed size of a single PDF stream so a
+	// crafted upload cannot inflate without bound.
+	maxStreamBytes = 8 << 20
+	// maxInflatedBytes caps the decompressed size of all of a document's
+	// streams together.
+	maxInflatedBytes = 32 << 20
+	// maxArrayDepth caps how deeply content stream arrays may nest.
+	maxArrayDepth = 64
+)
+
+var (
+	pdfStreamStart = regexp.MustCompile(`stream\r?\n`)
+	pdfSkipDict    = regexp.MustCompile(`/Subtype\s*/Image|/Type\s*/(?:XRef|ObjStm|XObject|Font)|/Length[123]\b`)
+	pdfFilter      = regexp.MustCompile(`/Filter\s*(?:\[\s*)?/(\w+)`)
+)
+
+// PDFText extracts the text layer of a PDF. It reads page content streams
+// (uncompressed or FlateDecode) and interprets the text-showing operators;
+// it does not resolve font encodings, so it suits the simple single-byte
+// fonts recipe exports typically use. Scanned PDFs yield ErrNoText, and
+// PDFs that inflate too far or nest arrays too deeply yield ErrMalformed.
+func PDFText(data []byte) (string, error) {
+	streams, err := pdfContentStreams(data)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	for _, stream := range streams {
+		if bytes.Contains(stream, []byte("BT")) {
+			if err := pdfShowText(&out, stream); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	text := collapseLines(out.String())
+	if strings.TrimSpace(text) == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+// pdfContentStreams returns the decoded body of every stream that may hold
+// page content, skipping images, fonts and cross-reference streams.
+func pdfContentStreams(data []byte) ([][]byte, error) {
+	var streams [][]byte
+	budget := maxInflatedBytes
+	offset := 0
+	for {
+		loc := pdfStreamStart.FindIndex(data[offset:])
+		if loc == nil {
+			return streams, nil
+		}
+		start, bodyStart := offset+loc[0], offset+loc[1]
+		end := bytes.Index(data[bodyStart:], []byte("endstream"))
+		if end < 0 {
+			return streams, nil
+		}
+		body := data[bodyStart : bodyStart+end]
+		offset = bodyStart + end + len("endstream")
+
+		// Skip the "endstream" keyword's own "stream" suffix.
+		if start >= 3 && string(data[start-3:start]) == "end" {
+			continue
+		}
+
+		dict := data[:start]
+		if i := bytes.LastIndex(dict, []byte("obj")); i >= 0 {
+			dict = dict[i:]
+		}
+		if pdfSkipDict.Match(dict) {
+			continue
+		}
+
+		switch m := pdfFilter.FindSubmatch(dict); {
+		case m == nil:
+			streams = append(streams, body)
+		case string(m[1]) == "FlateDecode":
+			decoded, ok := inflate(body, min(maxStreamBytes, budget+1))
+			if !ok {
+				continue
+			}
+			if len(decoded) > budget {
+				return nil, fmt.Errorf("%w: streams inflate to more than %d MB", ErrMalformed, maxInflatedBytes>>20)
+			}
+			budget -= len(decoded)
+			streams = append(streams, decoded)
+		}
+	}
+}
+
+// inflate decompresses a FlateDecode stream body, up to limit bytes of it.
+func inflate(body []byte, limit int) ([]byte, bool) {
+	r, err := zlib.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, false
+	}
+	defer r.Close()
+
+	// Truncated streams are common in the wild; keep whatever inflated.
+	decoded, err := io.ReadAll(io.LimitReader(r, int64(limit)))
+	if err != nil && len(decoded) == 0 {
+		return nil, false
+	}
+	return decoded, true
+}
+
+// pdfShowText writes the strings shown by a content stream, breaking lines
+// on text positioning operators that move to a new line.
+//
+//nolint:gocognit // A flat operator switch reads better than a dispatch table.
+func pdfShowText(out *strings.Builder, content []byte) error {
+	var operands []any
+	lex := &pdfLexer{data: content}
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			return lex.err
+		}
+		op, isOp := tok.(pdfOperator)
+		if !isOp {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch op {
+		case "Tj":
+			writeOperandString(out, operands, len(operands)-1)
+		case "'", "\"":
+			out.WriteByte('\n')
+			writeOperandString(out, operands, len(operands)-1)
+		case "TJ":
+			if len(operands) > 0 {
+				if arr, ok := operands[len(operands)-1].([]any); ok {
+					for _, item := range arr {
+						switch v := item.(type) {
+						case string:
+							out.WriteString(v)
+						case float64:
+							// Large negative kerning is a word gap.
+							if v < -200 {
+								out.WriteByte(' ')
+							}
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[len(operands)-1].(float64); ok && ty != 0 {
+					out.WriteByte('\n')
+				} else {
+					out.WriteByte(' ')
+				}
+			}
+		case "T*", "Tm", "ET":
+			out.WriteByte('\n')
+		}
+		operands = operands[:0]
+	}
+}
+
+func writeOperandString(out *strings.Builder, operands []any, i int) {
+	if i < 0 {
+		return
+	}
+	if s, ok := operands[i].(string); ok {
+		out.WriteString(s)
+	}
+}
+
+type pdfOperator string
+
+// pdfLexer tokenizes a content stream into operands (float64, string,
+// []any, names as pdfName) and operators. Arrays nested deeper than
+// maxArrayDepth stop it with err set.
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	depth int
+	err   error
+}
+
+type pdfName string
+
+func (l *pdfLexer) next() (any, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+
+	switch c := l.data[l.pos]; {
+	case c == '(':
+		l.pos++
+		return l.literalString(), true
+	case c == '<' && l.peek(1) == '<':
+		l.pos += 2
+		return pdfOperator("<<"), true
+	case c == '>' && l.peek(1) == '>':
+		l.pos += 2
+		return pdfOperator(">>"), true
+	case c == '<':
+		l.pos++
+		return l.hexString(), true
+	case c == '[':
+		if l.depth >= maxArrayDepth {
+			l.err = fmt.Errorf("%w: arrays nested deeper than %d", ErrMalformed, maxArrayDepth)
+			return nil, false
+		}
+		l.pos++
+		l.depth++
+		defer func() { l.depth-- }()
+		var arr []any
+		for {
+			l.skipSpace()
+			if l.pos >= len(l.data) {
+				return arr, true
+			}
+			if l.data[l.pos] == ']' {
+				l.pos++
+				return arr, true
+			}
+			tok, ok := l.next()
+			if !ok {
+				return arr, l.err == nil
+			}
+			arr = append(arr, tok)
+		}
+	case c == ']':
+		l.pos++
+		return pdfOperator("]"), true
+	case c == '/':
+		l.pos++
+		return pdfName(l.regular()), true
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		word := l.regular()
+		if f, err := strconv.ParseFloat(word, 64); err == nil {
+			return f, true
+		}
+		return pdfOperator(word), true
+	default:
+		word := l.regular()
+		if word == "" {
+			// Stray delimiter; step over it.
+			l.pos++
+			return pdfOperator(string(c)), true
+		}
+		return pdfOperator(word), true
+	}
+}
+
+func (l *pdfLexer) peek(n int) byte {
+	if l.pos+n < len(l.data) {
+		return l.data[l.pos+n]
+	}
+	return 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		switch l.data[l.pos] {
+		case ' ', '\t', '\r', '\n', '\f', 0:
+			l.pos++
+		case '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *pdfLexer) regular() string {
+	start := l.pos
+	for l.pos < len(l.data) && !strings.ContainsRune(" \t\r\n\f\x00()<>[]{}/%", rune(l.data[l.pos])) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+func (l *pdfLexer) literalString() string {
+	var buf []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return decodePDFString(buf)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				continue
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// Line continuation.
+				if e == '\r' && l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		buf = append(buf, c)
+	}
+	return decodePDFString(buf)
+}
+
+func (l *pdfLexer) hexString() string {
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; strings.IndexByte("0123456789abcdefABCDEF", c) >= 0 {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	buf := make([]byte, len(digits)/2)
+	for i := range buf {
+		v, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		buf[i] = byte(v)
+	}
+	return decodePDFString(buf)
+}
+
+// decodePDFString decodes UTF-16BE strings (marked by a BOM) and otherwise
+// treats bytes as Latin-1, which matches PDFDocEncoding for printable text.
+func decodePDFString(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		units := make([]uint16, 0, (len(b)-2)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	runes := make([]rune, 0, len(b))
+	for _, c := range b {
+		if c < 0x20 && c != '\t' && c != '\n' {
+			continue
+		}
+		runes = append(runes, rune(c))
+	}
+	return string(runes)
+}
+
+// collapseLines trims each line, folds runs of spaces and drops repeated
+// blank lines.
+func collapseLines(s string) string {
+	var lines []string
+	blank := true
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"strings"
+)
+
+// Bus publishes and consumes events on a topic exchange. RabbitMQBus is used
+// in deployed environments; MemoryBus keeps events in-process when no broker
+// is configured.
+type Bus interface {
+	// Publish sends an already-serialized JSON body with the given routing key.
+	Publish(ctx context.Context, routingKey string, body []byte) error
+
+	// Subscribe declares queue (if needed), binds it to bindingKey and returns
+	// its deliveries. At most prefetch deliveries are outstanding (unacked) at
+	// a time. The channel is closed once ctx is cancelled or the underlying
+	// connection is lost.
+	Subscribe(ctx context.Context, queue, bindingKey string, prefetch int) (<-chan Delivery, error)
+
+	Close() error
+}
+
+// Delivery is a single message handed to a subscriber. Exactly one of Ack or
+// Nack must be called once the message has been processed.
+type Delivery struct {
+	Body []byte
+	// Headers are the message's string headers, including the publisher's
+	// W3C trace context.
+	Headers map[string]string
+	Ack     func() error
+	Nack    func(requeue bool) error
+}
+
+// topicMatches reports whether routingKey matches an AMQP topic binding key,
+// where "*" matches exactly one word and "#" matches zero or more words.
+func topicMatches(bindingKey, routingKey string) bool {
+	return matchWords(strings.Split(bindingKey, "."), strings.Split(routingKey, "."))
+}
+
+func matchWords(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if matchWords(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && matchWords(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && pattern[0] == words[0] && matchWords(pattern[1:], words[1:])
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+)
+
+const localPipelineQueue = "recipes.local-pipeline"
+
+// RecipeImportRequestedEventHandler handles recipe.import.requested events.
+type RecipeImportRequestedEventHandler interface {
+	HandleRecipeImportRequestedEvent(ctx context.Context, event RecipeImportRequestedEvent) error
+}
+
+// RecipeImportRequestedSubscriber consumes recipe.import.requested events for
+// the built-in local pipeline. It is only run when no external ingestion
+// pipeline is attached, and handles one event at a time.
+type RecipeImportRequestedSubscriber struct {
+	bus     Bus
+	handler RecipeImportRequestedEventHandler
+	logger  *slog.Logger
+	running atomic.Bool
+}
+
+func NewRecipeImportRequestedSubscriber(
+	bus Bus,
+	handler RecipeImportRequestedEventHandler,
+	logger *slog.Logger,
+) (*RecipeImportRequestedSubscriber, error) {
+	if bus == nil {
+		return nil, errors.New("bus is required")
+	}
+	if logger == nil {
+		return nil, errors.New("logger is required")
+	}
+	return &RecipeImportRequestedSubscriber{bus: bus, handler: handler, logger: logger}, nil
+}
+
+// Check reports whether Run is consuming deliveries. It can serve
+// as a health.Check.
+func (s *RecipeImportRequestedSubscriber) Check(context.Context) error {
+	if !s.running.Load() {
+		return errors.New("recipe.import.requested subscriber is not running")
+	}
+	return nil
+}
+
+// Run consumes events until ctx is cancelled. The event being handled when
+// that happens is finished and acked first.
+func (s *RecipeImportRequestedSubscriber) Run(ctx context.Context) error {
+	// As in RecipeImportedSubscriber, the subscription outlives ctx so that
+	// the in-flight delivery can still be acked.
+	subCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	deliveries, err := s.bus.Subscribe(subCtx, localPipelineQueue, recipeImportRequestedRoutingKey, 1)
+	if err != nil {
+		return err
+	}
+
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	s.logger.InfoContext(ctx, "recipe.import.requested subscriber started", "queue", localPipelineQueue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return errors.New("recipe.import.requested delivery channel closed")
+			}
+			s.handle(context.WithoutCancel(ctx), d)
+		}
+	}
+}
+
+func (s *RecipeImportRequestedSubscriber) handle(ctx context.Context, d Delivery) {
+	var event RecipeImportRequestedEvent
+	if err := json.Unmarshal(d.Body, &event); err != nil {
+		s.logger.ErrorContext(ctx, "invalid recipe.import.requested payload", "error", err)
+		_ = d.Ack()
+		return
+	}
+
+	ctx = logging.With(ctx, slog.Any("job_id", event.JobID))
+	ctx, span := startProcessSpan(ctx, localPipelineQueue, d)
+	err := s.handler.HandleRecipeImportRequestedEvent(ctx, event)
+	endSpan(span, err)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to handle recipe.import.requested event", "error", err)
+		_ = d.Nack(true)
+		return
+	}
+	_ = d.Ack()
+}
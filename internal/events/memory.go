@@ -0,0 +1,202 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBusClosed is returned when publishing to or subscribing on a closed bus.
+var ErrBusClosed = errors.New("event bus closed")
+
+// MemoryBus is an in-process Bus with RabbitMQ-like topic routing. Queues are
+// created on first Subscribe and buffer messages until they are acked, so
+// several subscribers on one queue compete for its messages just as they
+// would on a broker. Messages published with no matching queue are dropped.
+//
+// MemoryBus is meant for local development and tests: nothing is persisted
+// and nothing is shared between processes.
+type MemoryBus struct {
+	mu     sync.Mutex
+	queues map[string]*memoryQueue
+	closed bool
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{queues: make(map[string]*memoryQueue)}
+}
+
+type memoryQueue struct {
+	mu       sync.Mutex
+	bindings []string
+	messages []memoryMessage
+	notify   chan struct{}
+}
+
+type memoryMessage struct {
+	body    []byte
+	headers map[string]string
+}
+
+func (q *memoryQueue) push(msg memoryMessage, front bool) {
+	q.mu.Lock()
+	if front {
+		q.messages = append([]memoryMessage{msg}, q.messages...)
+	} else {
+		q.messages = append(q.messages, msg)
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *memoryQueue) pop() (memoryMessage, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.messages) == 0 {
+		return memoryMessage{}, false
+	}
+	msg := q.messages[0]
+	q.messages = q.messages[1:]
+	if len(q.messages) > 0 {
+		// Wake another waiting subscriber for the remainder.
+		select {
+		case q.notify <- struct{}{}:
+		default:
+		}
+	}
+	return msg, true
+}
+
+func (q *memoryQueue) bound(routingKey string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, binding := range q.bindings {
+		if topicMatches(binding, routingKey) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, routingKey string, body []byte) (err error) {
+	_, span, headers := startPublishSpan(ctx, DefaultExchange, routingKey)
+	defer func() { endSpan(span, err) }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	for _, q := range b.queues {
+		if q.bound(routingKey) {
+			q.push(memoryMessage{body: append([]byte(nil), body...), headers: headers}, false)
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(
+	ctx context.Context,
+	queue, bindingKey string,
+	prefetch int,
+) (<-chan Delivery, error) {
+	if prefetch < 1 {
+		prefetch = 1
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, ErrBusClosed
+	}
+	q, ok := b.queues[queue]
+	if !ok {
+		q = &memoryQueue{notify: make(chan struct{}, 1)}
+		b.queues[queue] = q
+	}
+	q.mu.Lock()
+	q.bindings = appendUnique(q.bindings, bindingKey)
+	q.mu.Unlock()
+	b.mu.Unlock()
+
+	out := make(chan Delivery)
+	go b.deliver(ctx, q, out, prefetch)
+	return out, nil
+}
+
+// deliver hands messages from q to out, keeping at most prefetch unacked.
+func (b *MemoryBus) deliver(ctx context.Context, q *memoryQueue, out chan<- Delivery, prefetch int) {
+	defer close(out)
+
+	slots := make(chan struct{}, prefetch)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		msg, ok := q.pop()
+		for !ok {
+			select {
+			case <-q.notify:
+			case <-ctx.Done():
+				<-slots
+				return
+			}
+			msg, ok = q.pop()
+		}
+
+		var once sync.Once
+		settle := func(requeue bool) {
+			once.Do(func() {
+				if requeue {
+					q.push(msg, true)
+				}
+				<-slots
+			})
+		}
+		d := Delivery{
+			Body:    msg.body,
+			Headers: msg.headers,
+			Ack: func() error {
+				settle(false)
+				return nil
+			},
+			Nack: func(requeue bool) error {
+				settle(requeue)
+				return nil
+			},
+		}
+
+		select {
+		case out <- d:
+		case <-ctx.Done():
+			settle(true)
+			return
+		}
+	}
+}
+
+// Close stops accepting publishes and subscriptions. Existing subscriptions
+// end when their contexts are cancelled.
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
+
+func appendUnique(values []string, v string) []string {
+	for _, existing := range values {
+		if existing == v {
+			return values
+		}
+	}
+	return append(values, v)
+}
@@ -0,0 +1,182 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		binding    string
+		routingKey string
+		want       bool
+	}{
+		{"recipe.imported", "recipe.imported", true},
+		{"recipe.imported", "recipe.import.requested", false},
+		{"recipe.*", "recipe.created", true},
+		{"recipe.*", "recipe.import.requested", false},
+		{"recipe.#", "recipe.import.requested", true},
+		{"recipe.#", "recipe", true},
+		{"#", "anything.at.all", true},
+		{"*.created", "recipe.created", true},
+		{"*.created", "recipe.updated", false},
+		{"recipe.#.requested", "recipe.import.requested", true},
+		{"recipe.#.requested", "recipe.requested", true},
+		{"recipe.#.requested", "recipe.import.failed", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.binding+"/"+tt.routingKey, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, topicMatches(tt.binding, tt.routingKey))
+		})
+	}
+}
+
+func receive(t *testing.T, deliveries <-chan Delivery) Delivery {
+	t.Helper()
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery received")
+		return Delivery{}
+	}
+}
+
+func assertNoDelivery(t *testing.T, deliveries <-chan Delivery) {
+	t.Helper()
+	select {
+	case d := <-deliveries:
+		t.Fatalf("unexpected delivery %q", d.Body)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMemoryBus_RoutesByBindingKey(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := NewMemoryBus()
+	lifecycle, err := bus.Subscribe(ctx, "lifecycle", "recipe.*", 1)
+	require.NoError(t, err)
+	imports, err := bus.Subscribe(ctx, "imports", "recipe.import.requested", 1)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(ctx, "recipe.created", []byte("created")))
+	require.NoError(t, bus.Publish(ctx, "recipe.import.requested", []byte("requested")))
+
+	d := receive(t, lifecycle)
+	assert.Equal(t, "created", string(d.Body))
+	require.NoError(t, d.Ack())
+	assertNoDelivery(t, lifecycle)
+
+	d = receive(t, imports)
+	assert.Equal(t, "requested", string(d.Body))
+	require.NoError(t, d.Ack())
+}
+
+func TestMemoryBus_NackRequeues(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := NewMemoryBus()
+	deliveries, err := bus.Subscribe(ctx, "q", "recipe.imported", 1)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(ctx, "recipe.imported", []byte("first")))
+	require.NoError(t, bus.Publish(ctx, "recipe.imported", []byte("second")))
+
+	d := receive(t, deliveries)
+	assert.Equal(t, "first", string(d.Body))
+	require.NoError(t, d.Nack(true))
+
+	d = receive(t, deliveries)
+	assert.Equal(t, "first", string(d.Body), "requeued message is redelivered first")
+	require.NoError(t, d.Ack())
+
+	d = receive(t, deliveries)
+	assert.Equal(t, "second", string(d.Body))
+	require.NoError(t, d.Nack(false))
+	assertNoDelivery(t, deliveries)
+}
+
+func TestMemoryBus_LimitsUnackedToPrefetch(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := NewMemoryBus()
+	deliveries, err := bus.Subscribe(ctx, "q", "recipe.imported", 2)
+	require.NoError(t, err)
+
+	for range 3 {
+		require.NoError(t, bus.Publish(ctx, "recipe.imported", []byte("x")))
+	}
+
+	first := receive(t, deliveries)
+	receive(t, deliveries)
+	assertNoDelivery(t, deliveries)
+
+	require.NoError(t, first.Ack())
+	receive(t, deliveries)
+}
+
+func TestMemoryBus_CompetingSubscribersShareQueue(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := NewMemoryBus()
+	a, err := bus.Subscribe(ctx, "q", "recipe.imported", 1)
+	require.NoError(t, err)
+	b, err := bus.Subscribe(ctx, "q", "recipe.imported", 1)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(ctx, "recipe.imported", []byte("one")))
+	require.NoError(t, bus.Publish(ctx, "recipe.imported", []byte("two")))
+
+	// Each subscriber holds one unacked delivery, so both must get one.
+	got := []string{string(receive(t, a).Body), string(receive(t, b).Body)}
+	assert.ElementsMatch(t, []string{"one", "two"}, got)
+}
+
+func TestMemoryBus_Closed(t *testing.T) {
+	t.Parallel()
+
+	bus := NewMemoryBus()
+	require.NoError(t, bus.Close())
+
+	require.ErrorIs(t, bus.Publish(context.Background(), "recipe.created", nil), ErrBusClosed)
+	_, err := bus.Subscribe(context.Background(), "q", "#", 1)
+	require.ErrorIs(t, err, ErrBusClosed)
+}
+
+func TestMemoryBus_SubscriptionEndsWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := NewMemoryBus()
+	deliveries, err := bus.Subscribe(ctx, "q", "#", 1)
+	require.NoError(t, err)
+
+	cancel()
+	select {
+	case _, ok := <-deliveries:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery channel was not closed")
+	}
+}
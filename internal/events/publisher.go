@@ -3,73 +3,44 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"time"
-
-	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-// RecipeImportRequestedPublisher publishes recipe.import.requested events.
-type RecipeImportRequestedPublisher struct {
-	conn *amqp.Connection
+// Publisher publishes Recipe Service events on a Bus.
+type Publisher struct {
+	bus Bus
 }
 
-func NewRecipeImportRequestedPublisher(rabbitmqURL string) (*RecipeImportRequestedPublisher, error) {
-	conn, err := amqp.Dial(rabbitmqURL)
-	if err != nil {
-		return nil, fmt.Errorf("connect rabbitmq: %w", err)
-	}
-
-	ch, err := conn.Channel()
-	if err != nil {
-		_ = conn.Close()
-		return nil, fmt.Errorf("open channel: %w", err)
-	}
-	defer ch.Close()
-
-	if err := ch.ExchangeDeclare(
-		exchangeName,
-		"topic",
-		true,
-		false,
-		false,
-		false,
-		nil,
-	); err != nil {
-		_ = conn.Close()
-		return nil, fmt.Errorf("declare exchange %q: %w", exchangeName, err)
+func NewPublisher(bus Bus) (*Publisher, error) {
+	if bus == nil {
+		return nil, errors.New("bus is required")
 	}
+	return &Publisher{bus: bus}, nil
+}
 
-	return &RecipeImportRequestedPublisher{conn: conn}, nil
+// Publish sends an already-serialized JSON body with the given routing key.
+func (p *Publisher) Publish(ctx context.Context, routingKey string, body []byte) error {
+	return p.bus.Publish(ctx, routingKey, body)
 }
 
-func (p *RecipeImportRequestedPublisher) PublishRecipeImportRequested(
+func (p *Publisher) PublishRecipeImportRequested(
 	ctx context.Context,
 	event RecipeImportRequestedEvent,
 ) error {
-	ch, err := p.conn.Channel()
-	if err != nil {
-		return fmt.Errorf("open channel: %w", err)
-	}
-	defer ch.Close()
-
 	body, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("marshal recipe.import.requested event: %w", err)
 	}
 
-	if err := ch.PublishWithContext(ctx, exchangeName, recipeImportRequestedRoutingKey, false, false, amqp.Publishing{
-		ContentType:  "application/json",
-		DeliveryMode: amqp.Persistent,
-		Timestamp:    time.Now().UTC(),
-		Body:         body,
-	}); err != nil {
-		return fmt.Errorf("publish recipe.import.requested: %w", err)
-	}
-
-	return nil
+	return p.bus.Publish(ctx, recipeImportRequestedRoutingKey, body)
 }
 
-func (p *RecipeImportRequestedPublisher) Close() error {
-	return p.conn.Close()
+func (p *Publisher) PublishRecipeImported(ctx context.Context, event RecipeImportedEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal recipe.imported event: %w", err)
+	}
+
+	return p.bus.Publish(ctx, recipeImportedRoutingKey, body)
 }
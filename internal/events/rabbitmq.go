@@ -0,0 +1,219 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// RabbitMQBus is a Bus backed by a topic exchange on RabbitMQ,
+// DefaultExchange unless WithExchange names another.
+type RabbitMQBus struct {
+	conn     *amqp.Connection
+	exchange string
+}
+
+// RabbitMQOption configures a RabbitMQBus.
+type RabbitMQOption func(*RabbitMQBus)
+
+// WithExchange publishes to and binds queues on exchange instead of
+// DefaultExchange. An empty name is ignored.
+func WithExchange(exchange string) RabbitMQOption {
+	return func(b *RabbitMQBus) {
+		if exchange != "" {
+			b.exchange = exchange
+		}
+	}
+}
+
+func NewRabbitMQBus(rabbitmqURL string, opts ...RabbitMQOption) (*RabbitMQBus, error) {
+	b := &RabbitMQBus{exchange: DefaultExchange}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	conn, err := amqp.Dial(rabbitmqURL)
+	if err != nil {
+		return nil, fmt.Errorf("connect rabbitmq: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("open channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := declareExchange(ch, b.exchange); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	b.conn = conn
+	return b, nil
+}
+
+func (b *RabbitMQBus) Publish(ctx context.Context, routingKey string, body []byte) (err error) {
+	ctx, span, headers := startPublishSpan(ctx, b.exchange, routingKey, semconv.MessagingSystemRabbitMQ)
+	defer func() { endSpan(span, err) }()
+
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("open channel: %w", err)
+	}
+	defer ch.Close()
+
+	table := make(amqp.Table, len(headers))
+	for k, v := range headers {
+		table[k] = v
+	}
+	if err := ch.PublishWithContext(ctx, b.exchange, routingKey, false, false, amqp.Publishing{
+		Headers:      table,
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now().UTC(),
+		Body:         body,
+	}); err != nil {
+		return fmt.Errorf("publish %s: %w", routingKey, err)
+	}
+
+	return nil
+}
+
+func (b *RabbitMQBus) Subscribe(
+	ctx context.Context,
+	queue, bindingKey string,
+	prefetch int,
+) (<-chan Delivery, error) {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("open channel: %w", err)
+	}
+
+	msgs, err := consumeQueue(ch, b.exchange, queue, bindingKey, prefetch)
+	if err != nil {
+		_ = ch.Close()
+		return nil, err
+	}
+
+	// Closing the channel closes msgs, which in turn ends the forwarding
+	// loop below. Unacked deliveries are returned to the queue by the broker.
+	go func() {
+		<-ctx.Done()
+		_ = ch.Close()
+	}()
+
+	out := make(chan Delivery)
+	go func() {
+		defer close(out)
+		for msg := range msgs {
+			d := Delivery{
+				Body:    msg.Body,
+				Headers: stringHeaders(msg.Headers),
+				Ack:     func() error { return msg.Ack(false) },
+				Nack:    func(requeue bool) error { return msg.Nack(false, requeue) },
+			}
+			select {
+			case out <- d:
+			case <-ctx.Done():
+				_ = msg.Nack(false, true)
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// stringHeaders keeps the string-valued entries of an AMQP header table.
+func stringHeaders(table amqp.Table) map[string]string {
+	headers := make(map[string]string, len(table))
+	for k, v := range table {
+		if s, ok := v.(string); ok {
+			headers[k] = s
+		}
+	}
+	return headers
+}
+
+// Check reports whether the connection to the broker is still open. It can
+// serve as a health.Check.
+func (b *RabbitMQBus) Check(context.Context) error {
+	if b.conn.IsClosed() {
+		return errors.New("rabbitmq connection is closed")
+	}
+	return nil
+}
+
+func (b *RabbitMQBus) Close() error {
+	return b.conn.Close()
+}
+
+func declareExchange(ch *amqp.Channel, exchange string) error {
+	if err := ch.ExchangeDeclare(
+		exchange,
+		"topic",
+		true,
+		false,
+		false,
+		false,
+		nil,
+	); err != nil {
+		return fmt.Errorf("declare exchange %q: %w", exchange, err)
+	}
+	return nil
+}
+
+func consumeQueue(
+	ch *amqp.Channel,
+	exchange, queue, bindingKey string,
+	prefetch int,
+) (<-chan amqp.Delivery, error) {
+	if err := declareExchange(ch, exchange); err != nil {
+		return nil, err
+	}
+
+	if _, err := ch.QueueDeclare(
+		queue,
+		true,
+		false,
+		false,
+		false,
+		nil,
+	); err != nil {
+		return nil, fmt.Errorf("declare queue %q: %w", queue, err)
+	}
+
+	if err := ch.QueueBind(
+		queue,
+		bindingKey,
+		exchange,
+		false,
+		nil,
+	); err != nil {
+		return nil, fmt.Errorf("bind queue %q: %w", queue, err)
+	}
+
+	if err := ch.Qos(prefetch, 0, false); err != nil {
+		return nil, fmt.Errorf("set prefetch %d: %w", prefetch, err)
+	}
+
+	msgs, err := ch.Consume(
+		queue,
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("consume %q: %w", queue, err)
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Replay moves up to limit messages from the dead-letter queue back to the
+// queue each was first dead-lettered from, and returns how many it moved.
+// Messages go through the default exchange, which routes by queue name, so
+// only the queue that failed to handle a message sees it again; other
+// subscribers of the bus's exchange already handled it. A limit below 1
+// replays the messages in the queue when Replay starts, so messages that fail
+// again are not replayed in a loop. A message is acked on the dead-letter
+// queue only once the broker has confirmed its republish, so a failed replay
+// loses nothing.
+//
+// Subscribers requeue deliveries they fail to handle, so the dead-letter
+// queue only fills if the broker gives up on them: the consumed queue must be
+// a quorum queue with a delivery-limit policy that dead-letters into queue.
+func (b *RabbitMQBus) Replay(ctx context.Context, queue string, limit int) (int, error) {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("open channel: %w", err)
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclarePassive(queue, true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("find queue %q: %w", queue, err)
+	}
+	if limit < 1 {
+		limit = q.Messages
+	}
+	if err := ch.Confirm(false); err != nil {
+		return 0, fmt.Errorf("enable publisher confirms: %w", err)
+	}
+	// The broker returns a message it cannot route before confirming it.
+	returns := ch.NotifyReturn(make(chan amqp.Return, 1))
+
+	replayed := 0
+	for replayed < limit {
+		if err := ctx.Err(); err != nil {
+			return replayed, err
+		}
+		msg, ok, err := ch.Get(queue, false)
+		if err != nil {
+			return replayed, fmt.Errorf("get from %q: %w", queue, err)
+		}
+		if !ok {
+			break
+		}
+		if err := republish(ctx, ch, returns, msg); err != nil {
+			_ = msg.Nack(false, true)
+			return replayed, err
+		}
+		if err := msg.Ack(false); err != nil {
+			return replayed, fmt.Errorf("ack replayed message: %w", err)
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+func republish(ctx context.Context, ch *amqp.Channel, returns <-chan amqp.Return, msg amqp.Delivery) error {
+	queue, ok := deadLetteredFrom(msg)
+	if !ok {
+		return fmt.Errorf("message %q has no x-death queue to replay to", msg.MessageId)
+	}
+	headers := make(amqp.Table, len(msg.Headers))
+	for k, v := range msg.Headers {
+		if k != "x-death" && !strings.HasPrefix(k, "x-first-death-") && !strings.HasPrefix(k, "x-last-death-") {
+			headers[k] = v
+		}
+	}
+
+	// Mandatory, so that a queue that no longer exists fails the replay
+	// instead of silently dropping the message.
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx, "", queue, true, false, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    msg.MessageId,
+		Timestamp:    msg.Timestamp,
+		Body:         msg.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("republish to %q: %w", queue, err)
+	}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("confirm republish to %q: %w", queue, err)
+	}
+	if !acked {
+		return fmt.Errorf("broker refused message republished to %q", queue)
+	}
+	select {
+	case <-returns:
+		return fmt.Errorf("queue %q no longer exists", queue)
+	default:
+		return nil
+	}
+}
+
+// deadLetteredFrom returns the queue msg was first dead-lettered from.
+func deadLetteredFrom(msg amqp.Delivery) (string, bool) {
+	if deaths, ok := msg.Headers["x-death"].([]any); ok && len(deaths) > 0 {
+		// The oldest death is last.
+		if death, ok := deaths[len(deaths)-1].(amqp.Table); ok {
+			if queue, ok := death["queue"].(string); ok && queue != "" {
+				return queue, true
+			}
+		}
+	}
+	queue, ok := msg.Headers["x-first-death-queue"].(string)
+	return queue, ok && queue != ""
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetteredFrom(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		msg  amqp.Delivery
+		want string
+	}{
+		"not dead-lettered": {
+			msg: amqp.Delivery{RoutingKey: "recipe.imported"},
+		},
+		"dead-lettered twice": {
+			msg: amqp.Delivery{
+				RoutingKey: "recipes.dlq",
+				Headers: amqp.Table{"x-death": []any{
+					amqp.Table{"queue": "recipes.retry", "routing-keys": []any{"recipes.dlq"}},
+					amqp.Table{"queue": "recipes.recipe-imported", "routing-keys": []any{"recipe.imported"}},
+				}},
+			},
+			want: "recipes.recipe-imported",
+		},
+		"first death header only": {
+			msg: amqp.Delivery{
+				RoutingKey: "recipes.dlq",
+				Headers:    amqp.Table{"x-first-death-queue": "recipes.recipe-imported"},
+			},
+			want: "recipes.recipe-imported",
+		},
+		"malformed x-death": {
+			msg: amqp.Delivery{RoutingKey: "recipe.imported", Headers: amqp.Table{"x-death": "oops"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			queue, ok := deadLetteredFrom(tt.msg)
+			assert.Equal(t, tt.want, queue)
+			assert.Equal(t, tt.want != "", ok)
+		})
+	}
+}
@@ -5,144 +5,234 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"hash/fnv"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 
-	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
 )
 
-const recipeImportedQueue = "recipes.recipe-imported"
+const (
+	// DefaultRecipeImportedQueue is the queue RecipeImportedSubscriber
+	// consumes unless WithQueue names another.
+	DefaultRecipeImportedQueue = "recipes.recipe-imported"
+
+	defaultPrefetch = 16
+	defaultWorkers  = 4
+)
 
 // RecipeImportedEventHandler handles recipe.imported events.
 type RecipeImportedEventHandler interface {
 	HandleRecipeImportedEvent(ctx context.Context, event RecipeImportedEvent) error
 }
 
+// SubscriberOption configures a RecipeImportedSubscriber.
+type SubscriberOption func(*RecipeImportedSubscriber)
+
+// WithPrefetch sets the bus prefetch count (unacked deliveries in flight).
+// Values below 1 are ignored.
+func WithPrefetch(n int) SubscriberOption {
+	return func(s *RecipeImportedSubscriber) {
+		if n > 0 {
+			s.prefetch = n
+		}
+	}
+}
+
+// WithWorkers sets the number of concurrent handler goroutines. Values below
+// 1 are ignored.
+func WithWorkers(n int) SubscriberOption {
+	return func(s *RecipeImportedSubscriber) {
+		if n > 0 {
+			s.workers = n
+		}
+	}
+}
+
+// WithQueue consumes from queue instead of DefaultRecipeImportedQueue. An
+// empty name is ignored.
+func WithQueue(queue string) SubscriberOption {
+	return func(s *RecipeImportedSubscriber) {
+		if queue != "" {
+			s.queue = queue
+		}
+	}
+}
+
 // RecipeImportedSubscriber consumes recipe.imported events.
+//
+// Deliveries are fanned out to a fixed pool of workers keyed by job ID, so
+// events for different jobs are handled concurrently while events for the
+// same job are handled in the order they were received.
 type RecipeImportedSubscriber struct {
-	conn    *amqp.Connection
-	handler RecipeImportedEventHandler
-	logger  *slog.Logger
+	bus      Bus
+	handler  RecipeImportedEventHandler
+	logger   *slog.Logger
+	queue    string
+	prefetch int
+	workers  int
+	running  atomic.Bool
 }
 
 func NewRecipeImportedSubscriber(
-	rabbitmqURL string,
+	bus Bus,
 	handler RecipeImportedEventHandler,
 	logger *slog.Logger,
+	opts ...SubscriberOption,
 ) (*RecipeImportedSubscriber, error) {
+	if bus == nil {
+		return nil, errors.New("bus is required")
+	}
 	if logger == nil {
 		return nil, errors.New("logger is required")
 	}
 
-	conn, err := amqp.Dial(rabbitmqURL)
-	if err != nil {
-		return nil, fmt.Errorf("connect rabbitmq: %w", err)
+	s := &RecipeImportedSubscriber{
+		bus:      bus,
+		handler:  handler,
+		logger:   logger,
+		queue:    DefaultRecipeImportedQueue,
+		prefetch: defaultPrefetch,
+		workers:  defaultWorkers,
 	}
-
-	return &RecipeImportedSubscriber{
-		conn:    conn,
-		handler: handler,
-		logger:  logger,
-	}, nil
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 func (s *RecipeImportedSubscriber) Run(ctx context.Context) error {
-	ch, err := s.conn.Channel()
+	// The subscription outlives ctx until consume has finished draining, so
+	// in-flight deliveries can still be acked after shutdown begins.
+	subCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	deliveries, err := s.bus.Subscribe(subCtx, s.queue, recipeImportedRoutingKey, s.prefetch)
 	if err != nil {
-		return fmt.Errorf("open channel: %w", err)
-	}
-	defer ch.Close()
-
-	if err := ch.ExchangeDeclare(
-		exchangeName,
-		"topic",
-		true,
-		false,
-		false,
-		false,
-		nil,
-	); err != nil {
-		return fmt.Errorf("declare exchange %q: %w", exchangeName, err)
+		return err
 	}
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	s.logger.InfoContext(
+		ctx,
+		"recipe.imported subscriber started",
+		"queue",
+		s.queue,
+		"prefetch",
+		s.prefetch,
+		"workers",
+		s.workers,
+	)
 
-	if _, err := ch.QueueDeclare(
-		recipeImportedQueue,
-		true,
-		false,
-		false,
-		false,
-		nil,
-	); err != nil {
-		return fmt.Errorf("declare queue %q: %w", recipeImportedQueue, err)
-	}
+	return s.consume(ctx, deliveries)
+}
 
-	if err := ch.QueueBind(
-		recipeImportedQueue,
-		recipeImportedRoutingKey,
-		exchangeName,
-		false,
-		nil,
-	); err != nil {
-		return fmt.Errorf("bind queue %q: %w", recipeImportedQueue, err)
+// Check reports whether Run is consuming deliveries. It can serve
+// as a health.Check.
+func (s *RecipeImportedSubscriber) Check(context.Context) error {
+	if !s.running.Load() {
+		return errors.New("recipe.imported subscriber is not running")
 	}
+	return nil
+}
 
-	msgs, err := ch.Consume(
-		recipeImportedQueue,
-		"",
-		false,
-		false,
-		false,
-		false,
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("consume %q: %w", recipeImportedQueue, err)
+type workItem struct {
+	delivery Delivery
+	event    RecipeImportedEvent
+}
+
+// consume dispatches deliveries to the worker pool until ctx is cancelled or
+// deliveries is closed. On cancellation it stops taking new deliveries, lets
+// each worker finish the event it is handling, and hands any queued but
+// unstarted deliveries back to the broker.
+func (s *RecipeImportedSubscriber) consume(ctx context.Context, deliveries <-chan Delivery) error {
+	// Handlers run on a context that outlives ctx so an in-flight event is
+	// not abandoned halfway through its database writes during shutdown.
+	handlerCtx := context.WithoutCancel(ctx)
+
+	queues := make([]chan workItem, s.workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan workItem, s.prefetch)
+		wg.Add(1)
+		go func(queue <-chan workItem) {
+			defer wg.Done()
+			for item := range queue {
+				if ctx.Err() != nil {
+					_ = item.delivery.Nack(true)
+					continue
+				}
+				s.handle(handlerCtx, item)
+			}
+		}(queues[i])
 	}
 
-	s.logger.InfoContext(ctx, "recipe.imported subscriber started", "queue", recipeImportedQueue)
+	stop := func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		wg.Wait()
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
+			stop()
 			return nil
-		case msg, ok := <-msgs:
+		case d, ok := <-deliveries:
 			if !ok {
+				stop()
+				if ctx.Err() != nil {
+					return nil
+				}
 				return errors.New("recipe.imported delivery channel closed")
 			}
 
 			var event RecipeImportedEvent
-			if err := json.Unmarshal(msg.Body, &event); err != nil {
+			if err := json.Unmarshal(d.Body, &event); err != nil {
 				s.logger.ErrorContext(ctx, "invalid recipe.imported payload", "error", err)
-				_ = msg.Ack(false)
+				_ = d.Ack()
 				continue
 			}
 
-			if err := s.handler.HandleRecipeImportedEvent(ctx, event); err != nil {
-				if errors.Is(err, sql.ErrNoRows) {
-					s.logger.WarnContext(ctx, "dropping recipe.imported event for unknown job", "job_id", event.JobID)
-					_ = msg.Ack(false)
-					continue
-				}
-
-				s.logger.ErrorContext(
-					ctx,
-					"failed to handle recipe.imported event",
-					"job_id",
-					event.JobID,
-					"error",
-					err,
-				)
-				_ = msg.Nack(false, true)
-				continue
+			queue := queues[workerIndex(event, len(queues))]
+			select {
+			case queue <- workItem{delivery: d, event: event}:
+			case <-ctx.Done():
+				_ = d.Nack(true)
 			}
+		}
+	}
+}
 
-			if err := msg.Ack(false); err != nil {
-				s.logger.ErrorContext(ctx, "failed to ack recipe.imported event", "job_id", event.JobID, "error", err)
-			}
+func (s *RecipeImportedSubscriber) handle(ctx context.Context, item workItem) {
+	event := item.event
+	ctx = logging.With(ctx, slog.Any("job_id", event.JobID))
+	ctx, span := startProcessSpan(ctx, s.queue, item.delivery)
+	err := s.handler.HandleRecipeImportedEvent(ctx, event)
+	endSpan(span, err)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.logger.WarnContext(ctx, "dropping recipe.imported event for unknown job")
+			_ = item.delivery.Ack()
+			return
 		}
+
+		s.logger.ErrorContext(ctx, "failed to handle recipe.imported event", "error", err)
+		_ = item.delivery.Nack(true)
+		return
+	}
+
+	if err := item.delivery.Ack(); err != nil {
+		s.logger.ErrorContext(ctx, "failed to ack recipe.imported event", "error", err)
 	}
 }
 
-func (s *RecipeImportedSubscriber) Close() error {
-	return s.conn.Close()
+// workerIndex pins all events for a job to the same worker.
+func workerIndex(event RecipeImportedEvent, workers int) int {
+	h := fnv.New32a()
+	h.Write(event.JobID[:])                 //nolint:errcheck // hash.Hash writes never fail.
+	return int(h.Sum32() % uint32(workers)) //nolint:gosec // workers is a small positive pool size.
 }
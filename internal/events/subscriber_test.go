@@ -0,0 +1,318 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryDelivery records how a Delivery was settled.
+type memoryDelivery struct {
+	mu      sync.Mutex
+	acked   bool
+	nacked  bool
+	requeue bool
+	done    chan struct{}
+}
+
+func newMemoryDelivery(t *testing.T, event any) (*memoryDelivery, Delivery) {
+	t.Helper()
+
+	body, ok := event.([]byte)
+	if !ok {
+		var err error
+		body, err = json.Marshal(event)
+		require.NoError(t, err)
+	}
+
+	m := &memoryDelivery{done: make(chan struct{})}
+	return m, Delivery{
+		Body: body,
+		Ack: func() error {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.acked = true
+			close(m.done)
+			return nil
+		},
+		Nack: func(requeue bool) error {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.nacked = true
+			m.requeue = requeue
+			close(m.done)
+			return nil
+		},
+	}
+}
+
+func (m *memoryDelivery) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-m.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was never settled")
+	}
+}
+
+type handlerFunc func(ctx context.Context, event RecipeImportedEvent) error
+
+func (f handlerFunc) HandleRecipeImportedEvent(ctx context.Context, event RecipeImportedEvent) error {
+	return f(ctx, event)
+}
+
+func testSubscriber(handler RecipeImportedEventHandler, opts ...SubscriberOption) *RecipeImportedSubscriber {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sub, err := NewRecipeImportedSubscriber(NewMemoryBus(), handler, logger, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+func TestConsume_AcksHandledEvents(t *testing.T) {
+	t.Parallel()
+
+	sub := testSubscriber(handlerFunc(func(context.Context, RecipeImportedEvent) error { return nil }))
+	deliveries := make(chan Delivery, 1)
+	m, d := newMemoryDelivery(t, RecipeImportedEvent{JobID: uuid.New()})
+	deliveries <- d
+	close(deliveries)
+
+	err := sub.consume(context.Background(), deliveries)
+	require.EqualError(t, err, "recipe.imported delivery channel closed")
+	assert.True(t, m.acked)
+}
+
+func TestConsume_SettlesFailures(t *testing.T) {
+	t.Parallel()
+
+	unknownJob := uuid.New()
+	sub := testSubscriber(handlerFunc(func(_ context.Context, event RecipeImportedEvent) error {
+		if event.JobID == unknownJob {
+			return fmt.Errorf("stage: %w", sql.ErrNoRows)
+		}
+		return errors.New("database unavailable")
+	}))
+
+	deliveries := make(chan Delivery, 3)
+	invalid, d := newMemoryDelivery(t, []byte("not json"))
+	deliveries <- d
+	unknown, d := newMemoryDelivery(t, RecipeImportedEvent{JobID: unknownJob})
+	deliveries <- d
+	failing, d := newMemoryDelivery(t, RecipeImportedEvent{JobID: uuid.New()})
+	deliveries <- d
+	close(deliveries)
+
+	_ = sub.consume(context.Background(), deliveries)
+
+	assert.True(t, invalid.acked, "invalid payloads are dropped")
+	assert.True(t, unknown.acked, "events for unknown jobs are dropped")
+	assert.True(t, failing.nacked, "transient failures are retried")
+	assert.True(t, failing.requeue)
+}
+
+func TestConsume_RunsJobsConcurrently(t *testing.T) {
+	t.Parallel()
+
+	const workers = 4
+	started := make(chan struct{}, workers)
+	release := make(chan struct{})
+	sub := testSubscriber(handlerFunc(func(context.Context, RecipeImportedEvent) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}), WithWorkers(workers))
+
+	deliveries := make(chan Delivery)
+	done := make(chan error)
+	go func() { done <- sub.consume(context.Background(), deliveries) }()
+
+	// Pick job IDs that hash to distinct workers so every worker is busy.
+	var settled []*memoryDelivery
+	used := map[int]bool{}
+	for len(used) < workers {
+		event := RecipeImportedEvent{JobID: uuid.New()}
+		idx := workerIndex(event, workers)
+		if used[idx] {
+			continue
+		}
+		used[idx] = true
+		m, d := newMemoryDelivery(t, event)
+		settled = append(settled, m)
+		deliveries <- d
+	}
+
+	for range workers {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("events were not handled concurrently")
+		}
+	}
+	close(release)
+	for _, m := range settled {
+		m.wait(t)
+		assert.True(t, m.acked)
+	}
+
+	close(deliveries)
+	<-done
+}
+
+func TestConsume_PreservesPerJobOrder(t *testing.T) {
+	t.Parallel()
+
+	jobID := uuid.New()
+	var mu sync.Mutex
+	var order []string
+	sub := testSubscriber(handlerFunc(func(_ context.Context, event RecipeImportedEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if event.JobID == jobID {
+			order = append(order, event.Status)
+		}
+		return nil
+	}), WithWorkers(8), WithPrefetch(32))
+
+	deliveries := make(chan Delivery, 40)
+	want := make([]string, 0, 20)
+	for i := range 20 {
+		status := fmt.Sprintf("s%02d", i)
+		want = append(want, status)
+		_, d := newMemoryDelivery(t, RecipeImportedEvent{JobID: jobID, Status: status})
+		deliveries <- d
+		_, d = newMemoryDelivery(t, RecipeImportedEvent{JobID: uuid.New()})
+		deliveries <- d
+	}
+	close(deliveries)
+
+	_ = sub.consume(context.Background(), deliveries)
+
+	assert.Equal(t, want, order)
+}
+
+func TestConsume_DrainsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	jobID := uuid.New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var handlerCtxErr error
+	sub := testSubscriber(handlerFunc(func(ctx context.Context, event RecipeImportedEvent) error {
+		if event.Status == "first" {
+			close(started)
+			<-release
+			handlerCtxErr = ctx.Err()
+		}
+		return nil
+	}), WithWorkers(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	deliveries := make(chan Delivery, 2)
+	inFlight, d := newMemoryDelivery(t, RecipeImportedEvent{JobID: jobID, Status: "first"})
+	deliveries <- d
+	queued, d := newMemoryDelivery(t, RecipeImportedEvent{JobID: jobID, Status: "second"})
+
+	done := make(chan error)
+	go func() { done <- sub.consume(ctx, deliveries) }()
+	<-started
+	deliveries <- d
+
+	// Give the dispatcher a moment to queue the second delivery behind the
+	// first, then shut down while the first is still being handled.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	close(release)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("consume did not return after cancellation")
+	}
+
+	require.NoError(t, handlerCtxErr, "in-flight handler must not see cancellation")
+	assert.True(t, inFlight.acked, "in-flight delivery finishes and is acked")
+	queued.wait(t)
+	assert.True(t, queued.nacked, "unstarted delivery is handed back")
+	assert.True(t, queued.requeue)
+}
+
+func TestRecipeImportedSubscriber_Check(t *testing.T) {
+	t.Parallel()
+
+	sub := testSubscriber(handlerFunc(func(context.Context, RecipeImportedEvent) error { return nil }))
+	require.Error(t, sub.Check(context.Background()), "not running before Run")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- sub.Run(ctx) }()
+	running := func() bool { return sub.Check(context.Background()) == nil }
+	require.Eventually(t, running, 5*time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Error(t, sub.Check(context.Background()), "not running after Run returns")
+}
+
+type requestedHandlerFunc func(ctx context.Context, event RecipeImportRequestedEvent) error
+
+func (f requestedHandlerFunc) HandleRecipeImportRequestedEvent(
+	ctx context.Context,
+	event RecipeImportRequestedEvent,
+) error {
+	return f(ctx, event)
+}
+
+func TestRecipeImportRequestedSubscriber_FinishesDeliveryOnCancel(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var handlerCtxErr error
+	bus := NewMemoryBus()
+	sub, err := NewRecipeImportRequestedSubscriber(bus, requestedHandlerFunc(
+		func(ctx context.Context, _ RecipeImportRequestedEvent) error {
+			close(started)
+			<-release
+			handlerCtxErr = ctx.Err()
+			return nil
+		},
+	), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- sub.Run(ctx) }()
+	require.Eventually(t, func() bool { return sub.Check(ctx) == nil }, 5*time.Second, time.Millisecond)
+	body, err := json.Marshal(RecipeImportRequestedEvent{JobID: uuid.New()})
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(ctx, recipeImportRequestedRoutingKey, body))
+
+	<-started
+	cancel()
+	close(release)
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+	require.NoError(t, handlerCtxErr, "in-flight handler must not see cancellation")
+
+	deliveries, err := bus.Subscribe(context.Background(), localPipelineQueue, recipeImportRequestedRoutingKey, 1)
+	require.NoError(t, err)
+	assertNoDelivery(t, deliveries)
+}
@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/mwhite7112/woodpantry-recipes/internal/events")
+
+// startPublishSpan starts a producer span for a message to routingKey on
+// exchange and returns the headers that carry its W3C trace context to
+// consumers.
+func startPublishSpan(
+	ctx context.Context,
+	exchange, routingKey string,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span, map[string]string) {
+	attrs = append(attrs,
+		semconv.MessagingOperationTypeSend,
+		semconv.MessagingDestinationName(exchange),
+		semconv.MessagingRabbitMQDestinationRoutingKey(routingKey),
+	)
+	ctx, span := tracer.Start(ctx, "publish "+routingKey,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+	headers := make(map[string]string)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+	return ctx, span, headers
+}
+
+// startProcessSpan continues the trace carried in d's headers with a consumer
+// span for handling it from queue.
+func startProcessSpan(ctx context.Context, queue string, d Delivery) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(d.Headers))
+	return tracer.Start(ctx, "process "+queue,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationSubscriptionName(queue),
+		),
+	)
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
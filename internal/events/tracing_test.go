@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Not parallel: it swaps the global tracer provider and propagator.
+func TestMemoryBus_PropagatesTraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := NewMemoryBus()
+	deliveries, err := bus.Subscribe(ctx, "q", "recipe.imported", 1)
+	require.NoError(t, err)
+
+	pubCtx, parent := provider.Tracer("test").Start(ctx, "relay")
+	require.NoError(t, bus.Publish(pubCtx, "recipe.imported", []byte("x")))
+	parent.End()
+
+	d := receive(t, deliveries)
+	require.Contains(t, d.Headers, "traceparent")
+
+	_, span := startProcessSpan(ctx, "q", d)
+	endSpan(span, nil)
+	require.NoError(t, d.Ack())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	publish, process := spans[0], spans[2]
+	assert.Equal(t, "publish recipe.imported", publish.Name())
+	assert.Equal(t, trace.SpanKindProducer, publish.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), publish.Parent().SpanID())
+	assert.Equal(t, "process q", process.Name())
+	assert.Equal(t, trace.SpanKindConsumer, process.SpanKind())
+	assert.Equal(t, publish.SpanContext().TraceID(), process.SpanContext().TraceID())
+	assert.Equal(t, publish.SpanContext().SpanID(), process.Parent().SpanID())
+	assert.True(t, process.Parent().IsRemote())
+}
@@ -8,10 +8,24 @@ import (
 )
 
 const (
-	exchangeName = "woodpantry.topic"
+	// DefaultExchange is the topic exchange shared by the woodpantry services.
+	DefaultExchange = "woodpantry.topic"
 
 	recipeImportRequestedRoutingKey = "recipe.import.requested"
 	recipeImportedRoutingKey        = "recipe.imported"
+
+	recipeCreatedRoutingKey = "recipe.created"
+	recipeUpdatedRoutingKey = "recipe.updated"
+	recipeDeletedRoutingKey = "recipe.deleted"
+)
+
+// Recipe lifecycle event types. The suffix is the payload schema version:
+// breaking changes to RecipePayload get a new version rather than changing
+// the shape consumers already bind to.
+const (
+	RecipeCreatedEventType = "recipe.created.v1"
+	RecipeUpdatedEventType = "recipe.updated.v1"
+	RecipeDeletedEventType = "recipe.deleted.v1"
 )
 
 // RecipeImportRequestedEvent is published by Recipe Service when an ingest job is submitted.
@@ -39,3 +53,71 @@ type RecipeImportedEvent struct {
 	Error      string          `json:"error,omitempty"`
 	StagedData json.RawMessage `json:"staged_data,omitempty"`
 }
+
+// RecipeLifecycleEvent is published by Recipe Service when a recipe is
+// created, updated or deleted. Recipe carries the full recipe as it exists
+// after the write (or, for deletes, as it was immediately before).
+type RecipeLifecycleEvent struct {
+	EventID   uuid.UUID      `json:"event_id"`
+	EventType string         `json:"event_type"`
+	RecipeID  uuid.UUID      `json:"recipe_id"`
+	Timestamp string         `json:"timestamp"`
+	Recipe    *RecipePayload `json:"recipe,omitempty"`
+}
+
+// RecipePayload is the wire representation of a recipe in lifecycle events.
+type RecipePayload struct {
+	ID          uuid.UUID                 `json:"id"`
+	Title       string                    `json:"title"`
+	Description string                    `json:"description,omitempty"`
+	SourceURL   string                    `json:"source_url,omitempty"`
+	Servings    int32                     `json:"servings,omitempty"`
+	PrepMinutes int32                     `json:"prep_minutes,omitempty"`
+	CookMinutes int32                     `json:"cook_minutes,omitempty"`
+	Tags        []string                  `json:"tags"`
+	Steps       []RecipeStepPayload       `json:"steps"`
+	Ingredients []RecipeIngredientPayload `json:"ingredients"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+// RecipeStepPayload is a single recipe step in lifecycle events.
+type RecipeStepPayload struct {
+	StepNumber  int32  `json:"step_number"`
+	Instruction string `json:"instruction"`
+}
+
+// RecipeIngredientPayload is a single recipe ingredient in lifecycle events.
+type RecipeIngredientPayload struct {
+	ID               uuid.UUID `json:"id"`
+	IngredientID     uuid.UUID `json:"ingredient_id"`
+	Quantity         float64   `json:"quantity,omitempty"`
+	Unit             string    `json:"unit,omitempty"`
+	IsOptional       bool      `json:"is_optional"`
+	PreparationNotes string    `json:"preparation_notes,omitempty"`
+}
+
+func NewRecipeLifecycleEvent(eventType string, recipeID uuid.UUID, recipe *RecipePayload) RecipeLifecycleEvent {
+	return RecipeLifecycleEvent{
+		EventID:   uuid.New(),
+		EventType: eventType,
+		RecipeID:  recipeID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Recipe:    recipe,
+	}
+}
+
+// RoutingKey returns the topic routing key for the event type. Routing keys
+// are unversioned so consumers bind once and branch on EventType.
+func (e RecipeLifecycleEvent) RoutingKey() string {
+	switch e.EventType {
+	case RecipeCreatedEventType:
+		return recipeCreatedRoutingKey
+	case RecipeUpdatedEventType:
+		return recipeUpdatedRoutingKey
+	case RecipeDeletedEventType:
+		return recipeDeletedRoutingKey
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,196 @@
+// Package extract implements a deterministic, rule-based recipe extractor for
+// plain-text recipes. It is the local fallback for the LLM-backed ingestion
+// pipeline: it understands the common "Ingredients:" / "Instructions:" layout
+// but makes no attempt at free-form prose.
+package extract
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/parse"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+// ErrNoRecipe is returned when the input contains no recognisable recipe.
+var ErrNoRecipe = errors.New("no recipe found in text")
+
+type section int
+
+const (
+	sectionPreamble section = iota
+	sectionIngredients
+	sectionInstructions
+	sectionOther
+)
+
+var (
+	// listMarker matches bullets ("-", "*", "•") and list numbering ("1.",
+	// "2)", "Step 3:") at the start of a line. A bare leading number is not a
+	// marker, since it is usually an ingredient quantity.
+	listMarker = regexp.MustCompile(`^(?:[-*•·–]\s*|\d+[.)]\s+|(?i:step)\s*\d+[.:)]?\s*)`)
+
+	servingsLine = regexp.MustCompile(`(?i)^(?:serves|servings|yield|makes)\s*:?\s*(\d+)`)
+	prepLine     = regexp.MustCompile(`(?i)^prep(?:aration)?(?: time)?\s*:\s*(.+)$`)
+	cookLine     = regexp.MustCompile(`(?i)^cook(?:ing)?(?: time)?\s*:\s*(.+)$`)
+	totalLine    = regexp.MustCompile(`(?i)^total(?: time)?\s*:`)
+	tagsLine     = regexp.MustCompile(`(?i)^(?:tags|categories)\s*:\s*(.+)$`)
+
+	hoursPart   = regexp.MustCompile(`(?i)(\d+)\s*(?:h|hrs?|hours?)\b`)
+	minutesPart = regexp.MustCompile(`(?i)(\d+)\s*(?:m|mins?|minutes?)\b`)
+)
+
+var sectionHeaders = map[string]section{
+	"ingredients":   sectionIngredients,
+	"you will need": sectionIngredients,
+	"instructions":  sectionInstructions,
+	"directions":    sectionInstructions,
+	"method":        sectionInstructions,
+	"steps":         sectionInstructions,
+	"preparation":   sectionInstructions,
+	"notes":         sectionOther,
+	"nutrition":     sectionOther,
+}
+
+// RuleExtractor implements service.LLMExtractor without calling a model.
+type RuleExtractor struct{}
+
+func New() *RuleExtractor {
+	return &RuleExtractor{}
+}
+
+// ExtractRecipe parses rawText into a StagedRecipe. Ingredient lines are
+// split by parse.Ingredient; ingredient IDs are left for the Dictionary to
+// resolve on confirm.
+//
+//nolint:gocognit // One pass over the lines; splitting it hides the state machine.
+func (e *RuleExtractor) ExtractRecipe(_ context.Context, rawText string) (*service.StagedRecipe, error) {
+	recipe := &service.StagedRecipe{Ingredients: []service.StagedIngredient{}}
+	var description []string
+	var unsectioned []string
+	sawHeader := false
+	current := sectionPreamble
+
+	for _, line := range strings.Split(rawText, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if s, ok := headerSection(line); ok {
+			current = s
+			sawHeader = true
+			continue
+		}
+		if current != sectionInstructions && applyMetadata(recipe, line) {
+			continue
+		}
+
+		switch current {
+		case sectionPreamble:
+			if recipe.Title == "" {
+				recipe.Title = strings.TrimLeft(line, "# ")
+				continue
+			}
+			unsectioned = append(unsectioned, line)
+			description = append(description, line)
+		case sectionIngredients:
+			if ing, ok := parse.Ingredient(stripListMarker(line)); ok {
+				recipe.Ingredients = append(recipe.Ingredients, ing)
+			}
+		case sectionInstructions:
+			if step := stripListMarker(line); step != "" {
+				recipe.Steps = append(recipe.Steps, step)
+			}
+		case sectionOther:
+		}
+	}
+
+	if !sawHeader {
+		// No headers: treat quantity-led lines as ingredients and the rest
+		// as steps, which covers most pasted recipes without a layout.
+		description = nil
+		for _, line := range unsectioned {
+			text := stripListMarker(line)
+			if parse.StartsWithQuantity(text) {
+				if ing, ok := parse.Ingredient(text); ok {
+					recipe.Ingredients = append(recipe.Ingredients, ing)
+					continue
+				}
+			}
+			if text != "" {
+				recipe.Steps = append(recipe.Steps, text)
+			}
+		}
+	}
+	recipe.Description = strings.Join(description, " ")
+
+	if recipe.Title == "" || (len(recipe.Ingredients) == 0 && len(recipe.Steps) == 0) {
+		return nil, ErrNoRecipe
+	}
+	return recipe, nil
+}
+
+func headerSection(line string) (section, bool) {
+	key := strings.ToLower(strings.Trim(line, "#*: \t"))
+	s, ok := sectionHeaders[key]
+	return s, ok
+}
+
+// applyMetadata fills servings, times and tags from lines such as
+// "Serves 4" or "Cook time: 1 hour 10 minutes" and reports whether the line
+// was consumed.
+func applyMetadata(recipe *service.StagedRecipe, line string) bool {
+	if m := servingsLine.FindStringSubmatch(line); m != nil {
+		recipe.Servings, _ = strconv.Atoi(m[1])
+		return true
+	}
+	if m := prepLine.FindStringSubmatch(line); m != nil {
+		recipe.PrepMinutes = parseMinutes(m[1])
+		return true
+	}
+	if m := cookLine.FindStringSubmatch(line); m != nil {
+		recipe.CookMinutes = parseMinutes(m[1])
+		return true
+	}
+	if totalLine.MatchString(line) {
+		return true
+	}
+	if m := tagsLine.FindStringSubmatch(line); m != nil {
+		for _, tag := range strings.Split(m[1], ",") {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				recipe.Tags = append(recipe.Tags, tag)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// parseMinutes converts durations like "1 hour 20 mins" or "45 minutes" to
+// minutes. A bare number is taken as minutes.
+func parseMinutes(s string) int {
+	total := 0
+	matched := false
+	if m := hoursPart.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		total += h * 60
+		matched = true
+	}
+	if m := minutesPart.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		total += n
+		matched = true
+	}
+	if !matched {
+		total, _ = strconv.Atoi(strings.TrimSpace(s))
+	}
+	return total
+}
+
+func stripListMarker(line string) string {
+	return strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+}
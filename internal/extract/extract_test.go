@@ -0,0 +1,115 @@
+package extract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestExtractRecipe_SectionedLayout(t *testing.T) {
+	t.Parallel()
+
+	text := `Garlic Butter Pasta
+A quick weeknight dinner.
+Serves 4
+Prep time: 10 minutes
+Cook time: 1 hour 5 mins
+Tags: Dinner, Pasta
+
+Ingredients:
+- 1 lb spaghetti
+- 2 cloves garlic, minced
+* 1 1/2 tbsp butter
+• salt
+- 1/4 cup parsley, chopped (optional)
+
+Instructions:
+1. Boil the pasta.
+2) Melt butter and fry the garlic.
+Step 3: Toss everything together.
+`
+
+	got, err := New().ExtractRecipe(context.Background(), text)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Garlic Butter Pasta", got.Title)
+	assert.Equal(t, "A quick weeknight dinner.", got.Description)
+	assert.Equal(t, 4, got.Servings)
+	assert.Equal(t, 10, got.PrepMinutes)
+	assert.Equal(t, 65, got.CookMinutes)
+	assert.Equal(t, []string{"dinner", "pasta"}, got.Tags)
+	assert.Equal(t, []service.StagedIngredient{
+		{Name: "spaghetti", Quantity: 1, Unit: "lb"},
+		{Name: "garlic", Quantity: 2, Unit: "clove", PreparationNotes: "minced"},
+		{Name: "butter", Quantity: 1.5, Unit: "tbsp"},
+		{Name: "salt"},
+		{Name: "parsley", Quantity: 0.25, Unit: "cup", PreparationNotes: "chopped", IsOptional: true},
+	}, got.Ingredients)
+	assert.Equal(t, []string{
+		"Boil the pasta.",
+		"Melt butter and fry the garlic.",
+		"Toss everything together.",
+	}, got.Steps)
+}
+
+func TestExtractRecipe_HeaderVariants(t *testing.T) {
+	t.Parallel()
+
+	text := `# Pancakes
+
+## You will need
+200 g flour
+2 eggs
+
+## Method
+Whisk.
+Fry.
+
+Notes:
+Keeps for a day.
+`
+
+	got, err := New().ExtractRecipe(context.Background(), text)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Pancakes", got.Title)
+	assert.Equal(t, []service.StagedIngredient{
+		{Name: "flour", Quantity: 200, Unit: "g"},
+		{Name: "eggs", Quantity: 2},
+	}, got.Ingredients)
+	assert.Equal(t, []string{"Whisk.", "Fry."}, got.Steps)
+}
+
+func TestExtractRecipe_NoHeaders(t *testing.T) {
+	t.Parallel()
+
+	text := `Toast
+2 slices bread
+1 tbsp butter
+Toast the bread.
+Spread the butter.`
+
+	got, err := New().ExtractRecipe(context.Background(), text)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Toast", got.Title)
+	assert.Empty(t, got.Description)
+	assert.Equal(t, []service.StagedIngredient{
+		{Name: "bread", Quantity: 2, Unit: "slice"},
+		{Name: "butter", Quantity: 1, Unit: "tbsp"},
+	}, got.Ingredients)
+	assert.Equal(t, []string{"Toast the bread.", "Spread the butter."}, got.Steps)
+}
+
+func TestExtractRecipe_NoRecipe(t *testing.T) {
+	t.Parallel()
+
+	for _, text := range []string{"", "   \n\n", "Just a title"} {
+		_, err := New().ExtractRecipe(context.Background(), text)
+		require.ErrorIs(t, err, ErrNoRecipe, "input %q", text)
+	}
+}
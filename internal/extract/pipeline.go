@@ -0,0 +1,52 @@
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+// ImportedPublisher publishes recipe.imported events.
+type ImportedPublisher interface {
+	PublishRecipeImported(ctx context.Context, event events.RecipeImportedEvent) error
+}
+
+// LocalPipeline stands in for the Ingestion Pipeline when the service runs
+// without RabbitMQ: it answers recipe.import.requested with recipe.imported
+// using a local extractor, so ingest completes through the usual subscriber.
+type LocalPipeline struct {
+	extractor service.LLMExtractor
+	publisher ImportedPublisher
+}
+
+func NewLocalPipeline(extractor service.LLMExtractor, publisher ImportedPublisher) *LocalPipeline {
+	return &LocalPipeline{extractor: extractor, publisher: publisher}
+}
+
+// HandleRecipeImportRequestedEvent extracts the job's raw input. Extraction
+// failures are reported as a failed job rather than returned, so only
+// publish errors cause a redelivery.
+func (p *LocalPipeline) HandleRecipeImportRequestedEvent(
+	ctx context.Context,
+	event events.RecipeImportRequestedEvent,
+) error {
+	result := events.RecipeImportedEvent{JobID: event.JobID, Status: "staged"}
+
+	staged, err := p.extractor.ExtractRecipe(ctx, event.RawInput)
+	if err == nil {
+		result.StagedData, err = json.Marshal(staged)
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		result.StagedData = nil
+	}
+
+	if err := p.publisher.PublishRecipeImported(ctx, result); err != nil {
+		return fmt.Errorf("publish recipe.imported: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+type recordingPublisher struct {
+	events []events.RecipeImportedEvent
+	err    error
+}
+
+func (p *recordingPublisher) PublishRecipeImported(_ context.Context, event events.RecipeImportedEvent) error {
+	p.events = append(p.events, event)
+	return p.err
+}
+
+func TestLocalPipeline_PublishesStagedRecipe(t *testing.T) {
+	t.Parallel()
+
+	publisher := &recordingPublisher{}
+	pipeline := NewLocalPipeline(New(), publisher)
+	jobID := uuid.New()
+
+	err := pipeline.HandleRecipeImportRequestedEvent(context.Background(), events.RecipeImportRequestedEvent{
+		JobID:    jobID,
+		RawInput: "Toast\nIngredients:\n2 slices bread\nInstructions:\nToast it.",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, publisher.events, 1)
+	got := publisher.events[0]
+	assert.Equal(t, jobID, got.JobID)
+	assert.Equal(t, "staged", got.Status)
+
+	var staged service.StagedRecipe
+	require.NoError(t, json.Unmarshal(got.StagedData, &staged))
+	assert.Equal(t, "Toast", staged.Title)
+	assert.Equal(t, []string{"Toast it."}, staged.Steps)
+}
+
+func TestLocalPipeline_ReportsExtractionFailure(t *testing.T) {
+	t.Parallel()
+
+	publisher := &recordingPublisher{}
+	pipeline := NewLocalPipeline(New(), publisher)
+
+	err := pipeline.HandleRecipeImportRequestedEvent(context.Background(), events.RecipeImportRequestedEvent{
+		JobID:    uuid.New(),
+		RawInput: "",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, "failed", publisher.events[0].Status)
+	assert.Equal(t, ErrNoRecipe.Error(), publisher.events[0].Error)
+	assert.Empty(t, publisher.events[0].StagedData)
+}
+
+func TestLocalPipeline_ReturnsPublishError(t *testing.T) {
+	t.Parallel()
+
+	publisher := &recordingPublisher{err: errors.New("bus closed")}
+	pipeline := NewLocalPipeline(New(), publisher)
+
+	err := pipeline.HandleRecipeImportRequestedEvent(context.Background(), events.RecipeImportRequestedEvent{
+		JobID:    uuid.New(),
+		RawInput: "Toast\n2 slices bread",
+	})
+	require.ErrorContains(t, err, "bus closed")
+}
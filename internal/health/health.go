@@ -0,0 +1,125 @@
+// Package health serves the liveness and readiness probes. Liveness only
+// says the process is serving HTTP; readiness runs a set of dependency
+// checks and reports each one.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultTimeout = 2 * time.Second
+
+// Status values reported by Checker.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check reports whether one dependency is usable.
+type Check func(ctx context.Context) error
+
+// DetailedCheck is a Check that also describes the dependency's state. The
+// details are reported whether or not the check passes.
+type DetailedCheck func(ctx context.Context) (details any, err error)
+
+type namedCheck struct {
+	name  string
+	check DetailedCheck
+}
+
+// Checker runs readiness checks.
+type Checker struct {
+	checks  []namedCheck
+	timeout time.Duration
+}
+
+// NewChecker returns a Checker that gives each check timeout to finish.
+// Values below 1 mean a default of two seconds.
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Add registers check under name. Checks must be added before the Checker
+// serves requests.
+func (c *Checker) Add(name string, check Check) {
+	c.AddDetailed(name, func(ctx context.Context) (any, error) { return nil, check(ctx) })
+}
+
+// AddDetailed registers check under name, like Add.
+func (c *Checker) AddDetailed(name string, check DetailedCheck) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	Details   any     `json:"details,omitempty"`
+}
+
+// Report is the outcome of every check. Status is StatusOK only when all of
+// them passed.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Run runs all checks concurrently.
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, nc := range c.checks {
+		wg.Go(func() {
+			results[i] = c.run(ctx, nc.check)
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(c.checks))}
+	for i, nc := range c.checks {
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+		report.Checks[nc.name] = results[i]
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check DetailedCheck) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := check(ctx)
+	result := Result{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// ServeHTTP writes the Report as JSON, with 503 when any check failed.
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report) //nolint:errcheck
+}
@@ -0,0 +1,90 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/health"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	t.Parallel()
+
+	c := health.NewChecker(0)
+	c.Add("database", func(context.Context) error { return nil })
+	c.Add("rabbitmq", func(context.Context) error { return nil })
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var report health.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+}
+
+func TestChecker_NotReady(t *testing.T) {
+	t.Parallel()
+
+	c := health.NewChecker(20 * time.Millisecond)
+	c.Add("database", func(context.Context) error { return nil })
+	c.Add("rabbitmq", func(context.Context) error { return errors.New("connection is closed") })
+	c.Add("dictionary", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var report health.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, health.Result{Status: health.StatusOK, LatencyMS: report.Checks["database"].LatencyMS},
+		report.Checks["database"])
+	assert.Equal(t, "connection is closed", report.Checks["rabbitmq"].Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["dictionary"].Error)
+	assert.GreaterOrEqual(t, report.Checks["dictionary"].LatencyMS, 20.0, "slow checks time out")
+}
+
+func TestChecker_NoChecks(t *testing.T) {
+	t.Parallel()
+
+	report := health.NewChecker(0).Run(context.Background())
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Empty(t, report.Checks)
+}
+
+func TestChecker_Details(t *testing.T) {
+	t.Parallel()
+
+	c := health.NewChecker(0)
+	c.AddDetailed("database", func(context.Context) (any, error) {
+		return map[string]int{"in_use": 3}, errors.New("ping failed")
+	})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var body struct {
+		Checks map[string]struct {
+			Error   string         `json:"error"`
+			Details map[string]int `json:"details"`
+		} `json:"checks"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, "ping failed", body.Checks["database"].Error)
+	assert.Equal(t, map[string]int{"in_use": 3}, body.Checks["database"].Details, "details are kept on failure")
+}
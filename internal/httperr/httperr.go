@@ -0,0 +1,28 @@
+// Package httperr writes the JSON error bodies shared by the API handlers and
+// the middleware in front of them, so every error response has one shape.
+package httperr
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+)
+
+// Write writes an error body carrying msg and the request ID, which
+// logging.RequestIDMiddleware has already set on the response headers. For
+// a 5xx status the first of errs, if any, is logged.
+func Write(w http.ResponseWriter, msg string, status int, errs ...error) {
+	requestID := w.Header().Get(logging.RequestIDHeader)
+	if status >= 500 && len(errs) > 0 {
+		slog.Default().Error(msg, "status", status, "request_id", requestID, "error", errs[0])
+	}
+	body := map[string]string{"error": msg}
+	if requestID != "" {
+		body["request_id"] = requestID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body) //nolint:errcheck
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID. An incoming value is kept when it
+// looks sane; otherwise one is generated. Either way it is echoed on the
+// response and sent on to downstream services.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+type (
+	attrsKey     struct{}
+	requestIDKey struct{}
+)
+
+// With returns a copy of ctx whose log records carry attrs. A key already
+// on ctx is replaced rather than repeated.
+//
+// The fields are added by the handler installed by Setup, so they appear on
+// every record logged with ctx through a *Context method, whichever logger
+// emits it.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	merged := slices.Clone(Attrs(ctx))
+	for _, a := range attrs {
+		i := slices.IndexFunc(merged, func(b slog.Attr) bool { return b.Key == a.Key })
+		if i >= 0 {
+			merged[i] = a
+		} else {
+			merged = append(merged, a)
+		}
+	}
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// Attrs returns the log fields added to ctx by With.
+func Attrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// RequestID returns the ID of the request ctx belongs to, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the fields from With to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps h so that records logged with a context carry the
+// fields added to it by With.
+func NewContextHandler(h slog.Handler) slog.Handler {
+	return contextHandler{Handler: h}
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := Attrs(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// RequestIDMiddleware assigns each request an ID, taken from RequestIDHeader
+// when the caller sent a usable one, echoes it on the response and adds it
+// to the request's log fields as request_id.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		next.ServeHTTP(w, r.WithContext(With(ctx, slog.String("request_id", id))))
+	})
+}
+
+// validRequestID accepts short IDs of visible ASCII, so a caller cannot
+// inject arbitrary text into logs and downstream headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Transport wraps base so that outgoing requests carry the request ID of
+// their context in RequestIDHeader.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		id := RequestID(r.Context())
+		if id == "" || r.Header.Get(RequestIDHeader) != "" {
+			return base.RoundTrip(r)
+		}
+		r = r.Clone(r.Context())
+		r.Header.Set(RequestIDHeader, id)
+		return base.RoundTrip(r)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := With(context.Background(), slog.String("request_id", "r1"), slog.String("job_id", "j1"))
+	ctx = With(ctx, slog.String("job_id", "j2"), slog.String("recipe_id", "c1"))
+	logger.InfoContext(ctx, "committed", "title", "Pasta")
+	logger.Info("no context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "r1", record["request_id"])
+	assert.Equal(t, "j2", record["job_id"], "a key added again replaces the old value")
+	assert.Equal(t, "c1", record["recipe_id"])
+	assert.Equal(t, "Pasta", record["title"])
+	assert.Equal(t, 1, strings.Count(lines[0], `"job_id"`))
+	assert.NotContains(t, lines[1], "request_id")
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var got string
+	var fields []slog.Attr
+	handler := RequestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = RequestID(r.Context())
+		fields = Attrs(r.Context())
+	}))
+
+	tests := map[string]struct {
+		header   string
+		keep     bool
+		wantUUID bool
+	}{
+		"caller id kept": {header: "abc-123", keep: true},
+		"missing":        {header: "", wantUUID: true},
+		"too long":       {header: strings.Repeat("x", maxRequestIDLength+1), wantUUID: true},
+		"control chars":  {header: "abc\x7f", wantUUID: true},
+		"spaces":         {header: "a b", wantUUID: true},
+	}
+	for name, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			req.Header.Set(RequestIDHeader, tt.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if tt.keep {
+			assert.Equal(t, tt.header, got, name)
+		}
+		if tt.wantUUID {
+			assert.NoError(t, uuid.Validate(got), name)
+		}
+		assert.Equal(t, got, rec.Header().Get(RequestIDHeader), name)
+		assert.Equal(t, []slog.Attr{slog.String("request_id", got)}, fields, name)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(RequestIDHeader))
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: Transport(http.DefaultTransport)}
+
+	var ctx context.Context
+	RequestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	for _, c := range []context.Context{ctx, context.Background()} {
+		req, err := http.NewRequestWithContext(c, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, []string{RequestID(ctx), ""}, got)
+}
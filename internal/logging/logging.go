@@ -13,21 +13,27 @@ const (
 	warnStatusThreshold  = 400
 )
 
-// Setup configures the global slog default with a JSON handler at the level
-// specified by the LOG_LEVEL environment variable (debug, info, warn, error).
-func Setup() {
-	level := slog.LevelInfo
-	switch strings.ToLower(os.Getenv("LOG_LEVEL")) {
+// Setup configures the global slog default with a JSON handler at level, as
+// parsed by ParseLevel. Records logged with a context include the fields
+// added to it by With.
+func Setup(level string) {
+	slog.SetDefault(slog.New(NewContextHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: ParseLevel(level),
+	}))))
+}
+
+// ParseLevel returns the slog level named by level (debug, info, warn or
+// error). Anything else means info.
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
 	case "debug":
-		level = slog.LevelDebug
+		return slog.LevelDebug
 	case "warn":
-		level = slog.LevelWarn
+		return slog.LevelWarn
 	case "error":
-		level = slog.LevelError
+		return slog.LevelError
 	}
-	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: level,
-	})))
+	return slog.LevelInfo
 }
 
 // responseWriter wraps [http.ResponseWriter] to capture the status code.
@@ -43,10 +49,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 }
 
 // Middleware is a chi-compatible HTTP request logger.
-// It skips /healthz to avoid Kubernetes probe noise.
+// It skips the health probes to avoid Kubernetes probe noise.
 func Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/healthz" {
+		switch r.URL.Path {
+		case "/healthz", "/livez", "/readyz":
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+)
+
+// InstrumentBus wraps bus so that publishes are counted by routing key and
+// deliveries by queue as they are acked or nacked.
+func InstrumentBus(bus events.Bus) events.Bus {
+	return &instrumentedBus{Bus: bus}
+}
+
+type instrumentedBus struct {
+	events.Bus
+}
+
+func (b *instrumentedBus) Publish(ctx context.Context, routingKey string, body []byte) error {
+	err := b.Bus.Publish(ctx, routingKey, body)
+	eventsPublished.WithLabelValues(routingKey, outcome(err)).Inc()
+	return err
+}
+
+func (b *instrumentedBus) Subscribe(
+	ctx context.Context,
+	queue, bindingKey string,
+	prefetch int,
+) (<-chan events.Delivery, error) {
+	deliveries, err := b.Bus.Subscribe(ctx, queue, bindingKey, prefetch)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan events.Delivery)
+	go func() {
+		defer close(out)
+		for d := range deliveries {
+			ack, nack := d.Ack, d.Nack
+			d.Ack = func() error {
+				eventsConsumed.WithLabelValues(queue).Inc()
+				return ack()
+			}
+			d.Nack = func(requeue bool) error {
+				eventsNacked.WithLabelValues(queue, strconv.FormatBool(requeue)).Inc()
+				return nack(requeue)
+			}
+			// Hand each delivery on unless the subscriber has gone away; the
+			// source channel closes on cancellation as well.
+			select {
+			case out <- d:
+			case <-ctx.Done():
+				_ = nack(true)
+			}
+		}
+	}()
+	return out, nil
+}
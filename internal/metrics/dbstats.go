@@ -0,0 +1,14 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterDBStats registers the go_sql_* connection pool metrics for sqlDB,
+// labelled with db_name="recipe_db".
+func RegisterDBStats(sqlDB *sql.DB) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(sqlDB, "recipe_db"))
+}
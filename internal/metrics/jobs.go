@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+)
+
+const jobsScrapeTimeout = 5 * time.Second
+
+var ingestionJobsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "ingestion_jobs"),
+	"Ingestion jobs by status, across all households.",
+	[]string{"status"}, nil,
+)
+
+// RegisterIngestionJobs registers the recipes_ingestion_jobs gauge, which
+// reports the number of ingestion jobs in each status. Jobs are counted
+// through q on every scrape, so the gauge never lags the database.
+func RegisterIngestionJobs(q db.Querier, logger *slog.Logger) error {
+	return prometheus.Register(&ingestionJobsCollector{q: q, logger: logger})
+}
+
+type ingestionJobsCollector struct {
+	q      db.Querier
+	logger *slog.Logger
+}
+
+// Describe implements prometheus.Collector.
+func (c *ingestionJobsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ingestionJobsDesc
+}
+
+// Collect implements prometheus.Collector. A failed count is logged and the
+// gauge is left out of the scrape rather than reported as zero.
+func (c *ingestionJobsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), jobsScrapeTimeout)
+	defer cancel()
+
+	counts, err := c.q.CountIngestionJobsByStatus(ctx)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to count ingestion jobs for metrics", "error", err)
+		return
+	}
+	for _, row := range counts {
+		ch <- prometheus.MustNewConstMetric(ingestionJobsDesc, prometheus.GaugeValue, float64(row.Jobs), row.Status)
+	}
+}
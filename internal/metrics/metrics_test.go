@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+)
+
+// samples returns how many observations the histogram with labels holds.
+func samples(t *testing.T, vec *prometheus.HistogramVec, labels ...string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, vec.WithLabelValues(labels...).(prometheus.Histogram).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMiddleware_LabelsByRoutePattern(t *testing.T) {
+	t.Parallel()
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/widgets/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	for _, path := range []string{"/widgets/1", "/widgets/2", "/nowhere"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.InDelta(t, 2, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/widgets/{id}", "418")), 0)
+	assert.Equal(t, uint64(2), samples(t, httpDuration, "GET", "/widgets/{id}"))
+	assert.InDelta(t, 1, testutil.ToFloat64(httpRequests.WithLabelValues("GET", unmatchedRoute, "404")), 0)
+}
+
+func TestInstrumentQuerier(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	mockQ.EXPECT().GetRecipe(mock.Anything, mock.Anything).Return(db.Recipe{}, sql.ErrNoRows)
+	mockQ.EXPECT().GetRecipeRevision(mock.Anything, mock.Anything).Return(db.RecipeRevision{}, errors.New("boom"))
+	q := InstrumentQuerier(mockQ)
+
+	_, err := q.GetRecipe(context.Background(), db.GetRecipeParams{})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = q.GetRecipeRevision(context.Background(), db.GetRecipeRevisionParams{})
+	require.Error(t, err)
+
+	assert.Equal(t, uint64(1), samples(t, dbQueryDuration, "GetRecipe", outcomeOK))
+	assert.Equal(t, uint64(1), samples(t, dbQueryDuration, "GetRecipeRevision", outcomeError))
+}
+
+type stubResolver struct{ err error }
+
+func (s stubResolver) ResolveIngredient(context.Context, string) (uuid.UUID, error) {
+	return uuid.New(), s.err
+}
+
+func TestInstrumentResolver(t *testing.T) {
+	t.Parallel()
+
+	errorsBefore := testutil.ToFloat64(dictionaryErrors)
+	r := InstrumentResolver(stubResolver{err: errors.New("dictionary down")})
+	_, err := r.ResolveIngredient(context.Background(), "salt")
+	require.Error(t, err)
+
+	assert.InDelta(t, errorsBefore+1, testutil.ToFloat64(dictionaryErrors), 0)
+	assert.Positive(t, samples(t, dictionaryDuration, outcomeError))
+}
+
+func TestInstrumentBus(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := InstrumentBus(events.NewMemoryBus())
+	deliveries, err := bus.Subscribe(ctx, "metrics-test", "metrics.test", 2)
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(ctx, "metrics.test", []byte(`{}`)))
+	require.NoError(t, bus.Publish(ctx, "metrics.test", []byte(`{}`)))
+	receive := func() events.Delivery {
+		select {
+		case d := <-deliveries:
+			return d
+		case <-time.After(5 * time.Second):
+			t.Fatal("no delivery received")
+			return events.Delivery{}
+		}
+	}
+	require.NoError(t, receive().Ack())
+	require.NoError(t, receive().Nack(false))
+
+	assert.InDelta(t, 2, testutil.ToFloat64(eventsPublished.WithLabelValues("metrics.test", outcomeOK)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(eventsConsumed.WithLabelValues("metrics-test")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(eventsNacked.WithLabelValues("metrics-test", "false")), 0)
+}
+
+func TestIngestionJobsCollector(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	mockQ.EXPECT().CountIngestionJobsByStatus(mock.Anything).Return([]db.CountIngestionJobsByStatusRow{
+		{Status: "pending", Jobs: 3},
+		{Status: "staged", Jobs: 1},
+	}, nil).Once()
+	mockQ.EXPECT().CountIngestionJobsByStatus(mock.Anything).Return(nil, errors.New("db down")).Once()
+	c := &ingestionJobsCollector{q: mockQ, logger: slog.Default()}
+
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP recipes_ingestion_jobs Ingestion jobs by status, across all households.
+# TYPE recipes_ingestion_jobs gauge
+recipes_ingestion_jobs{status="pending"} 3
+recipes_ingestion_jobs{status="staged"} 1
+`)))
+	assert.Equal(t, 0, testutil.CollectAndCount(c), "a failed count reports nothing")
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+)
+
+// InstrumentQuerier wraps q so that every call records its latency in
+// recipes_db_query_duration_seconds under the sqlc query name.
+func InstrumentQuerier(q db.Querier) db.Querier {
+	return db.WithQueryHook(q, observeQuery)
+}
+
+// observeQuery times one query. sql.ErrNoRows is an answer, not a failure.
+func observeQuery(ctx context.Context, name string) (context.Context, func(error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+		dbQueryDuration.WithLabelValues(name, outcome(err)).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+// InstrumentResolver wraps the Ingredient Dictionary resolver so that every
+// lookup records its latency and failures.
+func InstrumentResolver(r service.IngredientResolver) service.IngredientResolver {
+	return &resolver{next: r}
+}
+
+type resolver struct {
+	next service.IngredientResolver
+}
+
+func (r *resolver) ResolveIngredient(ctx context.Context, name string) (uuid.UUID, error) {
+	start := time.Now()
+	id, err := r.next.ResolveIngredient(ctx, name)
+	dictionaryDuration.WithLabelValues(outcome(err)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		dictionaryErrors.Inc()
+	}
+	return id, err
+}
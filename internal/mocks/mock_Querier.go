@@ -4,7 +4,7 @@ package mocks
 
 import (
 	context "context"
-	sql "database/sql"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	db "github.com/mwhite7112/woodpantry-recipes/internal/db"
@@ -24,6 +24,225 @@ func (_m *MockQuerier) EXPECT() *MockQuerier_Expecter {
 	return &MockQuerier_Expecter{mock: &_m.Mock}
 }
 
+// AddRecipeShare provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) AddRecipeShare(ctx context.Context, arg db.AddRecipeShareParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRecipeShare")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.AddRecipeShareParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockQuerier_AddRecipeShare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddRecipeShare'
+type MockQuerier_AddRecipeShare_Call struct {
+	*mock.Call
+}
+
+// AddRecipeShare is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.AddRecipeShareParams
+func (_e *MockQuerier_Expecter) AddRecipeShare(ctx interface{}, arg interface{}) *MockQuerier_AddRecipeShare_Call {
+	return &MockQuerier_AddRecipeShare_Call{Call: _e.mock.On("AddRecipeShare", ctx, arg)}
+}
+
+func (_c *MockQuerier_AddRecipeShare_Call) Run(run func(ctx context.Context, arg db.AddRecipeShareParams)) *MockQuerier_AddRecipeShare_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.AddRecipeShareParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_AddRecipeShare_Call) Return(_a0 error) *MockQuerier_AddRecipeShare_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockQuerier_AddRecipeShare_Call) RunAndReturn(run func(context.Context, db.AddRecipeShareParams) error) *MockQuerier_AddRecipeShare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountIngestionJobsByStatus provides a mock function with given fields: ctx
+func (_m *MockQuerier) CountIngestionJobsByStatus(ctx context.Context) ([]db.CountIngestionJobsByStatusRow, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountIngestionJobsByStatus")
+	}
+
+	var r0 []db.CountIngestionJobsByStatusRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]db.CountIngestionJobsByStatusRow, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []db.CountIngestionJobsByStatusRow); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.CountIngestionJobsByStatusRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_CountIngestionJobsByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountIngestionJobsByStatus'
+type MockQuerier_CountIngestionJobsByStatus_Call struct {
+	*mock.Call
+}
+
+// CountIngestionJobsByStatus is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockQuerier_Expecter) CountIngestionJobsByStatus(ctx interface{}) *MockQuerier_CountIngestionJobsByStatus_Call {
+	return &MockQuerier_CountIngestionJobsByStatus_Call{Call: _e.mock.On("CountIngestionJobsByStatus", ctx)}
+}
+
+func (_c *MockQuerier_CountIngestionJobsByStatus_Call) Run(run func(ctx context.Context)) *MockQuerier_CountIngestionJobsByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_CountIngestionJobsByStatus_Call) Return(_a0 []db.CountIngestionJobsByStatusRow, _a1 error) *MockQuerier_CountIngestionJobsByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_CountIngestionJobsByStatus_Call) RunAndReturn(run func(context.Context) ([]db.CountIngestionJobsByStatusRow, error)) *MockQuerier_CountIngestionJobsByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateIngestionBatch provides a mock function with given fields: ctx, householdID
+func (_m *MockQuerier) CreateIngestionBatch(ctx context.Context, householdID uuid.UUID) (db.IngestionBatch, error) {
+	ret := _m.Called(ctx, householdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIngestionBatch")
+	}
+
+	var r0 db.IngestionBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (db.IngestionBatch, error)); ok {
+		return rf(ctx, householdID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) db.IngestionBatch); ok {
+		r0 = rf(ctx, householdID)
+	} else {
+		r0 = ret.Get(0).(db.IngestionBatch)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, householdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_CreateIngestionBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIngestionBatch'
+type MockQuerier_CreateIngestionBatch_Call struct {
+	*mock.Call
+}
+
+// CreateIngestionBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - householdID uuid.UUID
+func (_e *MockQuerier_Expecter) CreateIngestionBatch(ctx interface{}, householdID interface{}) *MockQuerier_CreateIngestionBatch_Call {
+	return &MockQuerier_CreateIngestionBatch_Call{Call: _e.mock.On("CreateIngestionBatch", ctx, householdID)}
+}
+
+func (_c *MockQuerier_CreateIngestionBatch_Call) Run(run func(ctx context.Context, householdID uuid.UUID)) *MockQuerier_CreateIngestionBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_CreateIngestionBatch_Call) Return(_a0 db.IngestionBatch, _a1 error) *MockQuerier_CreateIngestionBatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_CreateIngestionBatch_Call) RunAndReturn(run func(context.Context, uuid.UUID) (db.IngestionBatch, error)) *MockQuerier_CreateIngestionBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateIngestionFile provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) CreateIngestionFile(ctx context.Context, arg db.CreateIngestionFileParams) (db.IngestionFile, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIngestionFile")
+	}
+
+	var r0 db.IngestionFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateIngestionFileParams) (db.IngestionFile, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateIngestionFileParams) db.IngestionFile); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IngestionFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateIngestionFileParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_CreateIngestionFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIngestionFile'
+type MockQuerier_CreateIngestionFile_Call struct {
+	*mock.Call
+}
+
+// CreateIngestionFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.CreateIngestionFileParams
+func (_e *MockQuerier_Expecter) CreateIngestionFile(ctx interface{}, arg interface{}) *MockQuerier_CreateIngestionFile_Call {
+	return &MockQuerier_CreateIngestionFile_Call{Call: _e.mock.On("CreateIngestionFile", ctx, arg)}
+}
+
+func (_c *MockQuerier_CreateIngestionFile_Call) Run(run func(ctx context.Context, arg db.CreateIngestionFileParams)) *MockQuerier_CreateIngestionFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.CreateIngestionFileParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_CreateIngestionFile_Call) Return(_a0 db.IngestionFile, _a1 error) *MockQuerier_CreateIngestionFile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_CreateIngestionFile_Call) RunAndReturn(run func(context.Context, db.CreateIngestionFileParams) (db.IngestionFile, error)) *MockQuerier_CreateIngestionFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateIngestionJob provides a mock function with given fields: ctx, arg
 func (_m *MockQuerier) CreateIngestionJob(ctx context.Context, arg db.CreateIngestionJobParams) (db.IngestionJob, error) {
 	ret := _m.Called(ctx, arg)
@@ -299,49 +518,116 @@ func (_c *MockQuerier_DeleteIngredientsByRecipe_Call) RunAndReturn(run func(cont
 	return _c
 }
 
-// DeleteRecipe provides a mock function with given fields: ctx, id
-func (_m *MockQuerier) DeleteRecipe(ctx context.Context, id uuid.UUID) error {
-	ret := _m.Called(ctx, id)
+// DeleteRecipeIngredient provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) DeleteRecipeIngredient(ctx context.Context, arg db.DeleteRecipeIngredientParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteRecipe")
+		panic("no return value specified for DeleteRecipeIngredient")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, id)
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.DeleteRecipeIngredientParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.DeleteRecipeIngredientParams) int64); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, db.DeleteRecipeIngredientParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockQuerier_DeleteRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRecipe'
-type MockQuerier_DeleteRecipe_Call struct {
+// MockQuerier_DeleteRecipeIngredient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRecipeIngredient'
+type MockQuerier_DeleteRecipeIngredient_Call struct {
 	*mock.Call
 }
 
-// DeleteRecipe is a helper method to define mock.On call
+// DeleteRecipeIngredient is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uuid.UUID
-func (_e *MockQuerier_Expecter) DeleteRecipe(ctx interface{}, id interface{}) *MockQuerier_DeleteRecipe_Call {
-	return &MockQuerier_DeleteRecipe_Call{Call: _e.mock.On("DeleteRecipe", ctx, id)}
+//   - arg db.DeleteRecipeIngredientParams
+func (_e *MockQuerier_Expecter) DeleteRecipeIngredient(ctx interface{}, arg interface{}) *MockQuerier_DeleteRecipeIngredient_Call {
+	return &MockQuerier_DeleteRecipeIngredient_Call{Call: _e.mock.On("DeleteRecipeIngredient", ctx, arg)}
 }
 
-func (_c *MockQuerier_DeleteRecipe_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockQuerier_DeleteRecipe_Call {
+func (_c *MockQuerier_DeleteRecipeIngredient_Call) Run(run func(ctx context.Context, arg db.DeleteRecipeIngredientParams)) *MockQuerier_DeleteRecipeIngredient_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(db.DeleteRecipeIngredientParams))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_DeleteRecipe_Call) Return(_a0 error) *MockQuerier_DeleteRecipe_Call {
-	_c.Call.Return(_a0)
+func (_c *MockQuerier_DeleteRecipeIngredient_Call) Return(_a0 int64, _a1 error) *MockQuerier_DeleteRecipeIngredient_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_DeleteRecipeIngredient_Call) RunAndReturn(run func(context.Context, db.DeleteRecipeIngredientParams) (int64, error)) *MockQuerier_DeleteRecipeIngredient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteStep provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) DeleteStep(ctx context.Context, arg db.DeleteStepParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStep")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.DeleteStepParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.DeleteStepParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.DeleteStepParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_DeleteStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStep'
+type MockQuerier_DeleteStep_Call struct {
+	*mock.Call
+}
+
+// DeleteStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.DeleteStepParams
+func (_e *MockQuerier_Expecter) DeleteStep(ctx interface{}, arg interface{}) *MockQuerier_DeleteStep_Call {
+	return &MockQuerier_DeleteStep_Call{Call: _e.mock.On("DeleteStep", ctx, arg)}
+}
+
+func (_c *MockQuerier_DeleteStep_Call) Run(run func(ctx context.Context, arg db.DeleteStepParams)) *MockQuerier_DeleteStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.DeleteStepParams))
+	})
 	return _c
 }
 
-func (_c *MockQuerier_DeleteRecipe_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockQuerier_DeleteRecipe_Call {
+func (_c *MockQuerier_DeleteStep_Call) Return(_a0 int64, _a1 error) *MockQuerier_DeleteStep_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_DeleteStep_Call) RunAndReturn(run func(context.Context, db.DeleteStepParams) (int64, error)) *MockQuerier_DeleteStep_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -393,27 +679,29 @@ func (_c *MockQuerier_DeleteStepsByRecipe_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
-// GetIngestionJob provides a mock function with given fields: ctx, id
-func (_m *MockQuerier) GetIngestionJob(ctx context.Context, id uuid.UUID) (db.IngestionJob, error) {
-	ret := _m.Called(ctx, id)
+// FindRecipesBySourceURL provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) FindRecipesBySourceURL(ctx context.Context, arg db.FindRecipesBySourceURLParams) ([]db.FindRecipesBySourceURLRow, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetIngestionJob")
+		panic("no return value specified for FindRecipesBySourceURL")
 	}
 
-	var r0 db.IngestionJob
+	var r0 []db.FindRecipesBySourceURLRow
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (db.IngestionJob, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, db.FindRecipesBySourceURLParams) ([]db.FindRecipesBySourceURLRow, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) db.IngestionJob); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, db.FindRecipesBySourceURLParams) []db.FindRecipesBySourceURLRow); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Get(0).(db.IngestionJob)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.FindRecipesBySourceURLRow)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, db.FindRecipesBySourceURLParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -421,56 +709,58 @@ func (_m *MockQuerier) GetIngestionJob(ctx context.Context, id uuid.UUID) (db.In
 	return r0, r1
 }
 
-// MockQuerier_GetIngestionJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIngestionJob'
-type MockQuerier_GetIngestionJob_Call struct {
+// MockQuerier_FindRecipesBySourceURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRecipesBySourceURL'
+type MockQuerier_FindRecipesBySourceURL_Call struct {
 	*mock.Call
 }
 
-// GetIngestionJob is a helper method to define mock.On call
+// FindRecipesBySourceURL is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uuid.UUID
-func (_e *MockQuerier_Expecter) GetIngestionJob(ctx interface{}, id interface{}) *MockQuerier_GetIngestionJob_Call {
-	return &MockQuerier_GetIngestionJob_Call{Call: _e.mock.On("GetIngestionJob", ctx, id)}
+//   - arg db.FindRecipesBySourceURLParams
+func (_e *MockQuerier_Expecter) FindRecipesBySourceURL(ctx interface{}, arg interface{}) *MockQuerier_FindRecipesBySourceURL_Call {
+	return &MockQuerier_FindRecipesBySourceURL_Call{Call: _e.mock.On("FindRecipesBySourceURL", ctx, arg)}
 }
 
-func (_c *MockQuerier_GetIngestionJob_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockQuerier_GetIngestionJob_Call {
+func (_c *MockQuerier_FindRecipesBySourceURL_Call) Run(run func(ctx context.Context, arg db.FindRecipesBySourceURLParams)) *MockQuerier_FindRecipesBySourceURL_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(db.FindRecipesBySourceURLParams))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_GetIngestionJob_Call) Return(_a0 db.IngestionJob, _a1 error) *MockQuerier_GetIngestionJob_Call {
+func (_c *MockQuerier_FindRecipesBySourceURL_Call) Return(_a0 []db.FindRecipesBySourceURLRow, _a1 error) *MockQuerier_FindRecipesBySourceURL_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockQuerier_GetIngestionJob_Call) RunAndReturn(run func(context.Context, uuid.UUID) (db.IngestionJob, error)) *MockQuerier_GetIngestionJob_Call {
+func (_c *MockQuerier_FindRecipesBySourceURL_Call) RunAndReturn(run func(context.Context, db.FindRecipesBySourceURLParams) ([]db.FindRecipesBySourceURLRow, error)) *MockQuerier_FindRecipesBySourceURL_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetRecipe provides a mock function with given fields: ctx, id
-func (_m *MockQuerier) GetRecipe(ctx context.Context, id uuid.UUID) (db.Recipe, error) {
-	ret := _m.Called(ctx, id)
+// FindRecipesByTitleKey provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) FindRecipesByTitleKey(ctx context.Context, arg db.FindRecipesByTitleKeyParams) ([]db.FindRecipesByTitleKeyRow, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetRecipe")
+		panic("no return value specified for FindRecipesByTitleKey")
 	}
 
-	var r0 db.Recipe
+	var r0 []db.FindRecipesByTitleKeyRow
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (db.Recipe, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, db.FindRecipesByTitleKeyParams) ([]db.FindRecipesByTitleKeyRow, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) db.Recipe); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, db.FindRecipesByTitleKeyParams) []db.FindRecipesByTitleKeyRow); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Get(0).(db.Recipe)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.FindRecipesByTitleKeyRow)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, db.FindRecipesByTitleKeyParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -478,58 +768,2051 @@ func (_m *MockQuerier) GetRecipe(ctx context.Context, id uuid.UUID) (db.Recipe,
 	return r0, r1
 }
 
-// MockQuerier_GetRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecipe'
-type MockQuerier_GetRecipe_Call struct {
+// MockQuerier_FindRecipesByTitleKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRecipesByTitleKey'
+type MockQuerier_FindRecipesByTitleKey_Call struct {
 	*mock.Call
 }
 
-// GetRecipe is a helper method to define mock.On call
+// FindRecipesByTitleKey is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uuid.UUID
-func (_e *MockQuerier_Expecter) GetRecipe(ctx interface{}, id interface{}) *MockQuerier_GetRecipe_Call {
-	return &MockQuerier_GetRecipe_Call{Call: _e.mock.On("GetRecipe", ctx, id)}
+//   - arg db.FindRecipesByTitleKeyParams
+func (_e *MockQuerier_Expecter) FindRecipesByTitleKey(ctx interface{}, arg interface{}) *MockQuerier_FindRecipesByTitleKey_Call {
+	return &MockQuerier_FindRecipesByTitleKey_Call{Call: _e.mock.On("FindRecipesByTitleKey", ctx, arg)}
 }
 
-func (_c *MockQuerier_GetRecipe_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockQuerier_GetRecipe_Call {
+func (_c *MockQuerier_FindRecipesByTitleKey_Call) Run(run func(ctx context.Context, arg db.FindRecipesByTitleKeyParams)) *MockQuerier_FindRecipesByTitleKey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(db.FindRecipesByTitleKeyParams))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_GetRecipe_Call) Return(_a0 db.Recipe, _a1 error) *MockQuerier_GetRecipe_Call {
+func (_c *MockQuerier_FindRecipesByTitleKey_Call) Return(_a0 []db.FindRecipesByTitleKeyRow, _a1 error) *MockQuerier_FindRecipesByTitleKey_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockQuerier_GetRecipe_Call) RunAndReturn(run func(context.Context, uuid.UUID) (db.Recipe, error)) *MockQuerier_GetRecipe_Call {
+func (_c *MockQuerier_FindRecipesByTitleKey_Call) RunAndReturn(run func(context.Context, db.FindRecipesByTitleKeyParams) ([]db.FindRecipesByTitleKeyRow, error)) *MockQuerier_FindRecipesByTitleKey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIngredientsByRecipe provides a mock function with given fields: ctx, recipeID
-func (_m *MockQuerier) ListIngredientsByRecipe(ctx context.Context, recipeID uuid.UUID) ([]db.RecipeIngredient, error) {
-	ret := _m.Called(ctx, recipeID)
+// FindRecipesSharingIngredients provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) FindRecipesSharingIngredients(ctx context.Context, arg db.FindRecipesSharingIngredientsParams) ([]db.FindRecipesSharingIngredientsRow, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIngredientsByRecipe")
+		panic("no return value specified for FindRecipesSharingIngredients")
 	}
 
-	var r0 []db.RecipeIngredient
+	var r0 []db.FindRecipesSharingIngredientsRow
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]db.RecipeIngredient, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, db.FindRecipesSharingIngredientsParams) ([]db.FindRecipesSharingIngredientsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.FindRecipesSharingIngredientsParams) []db.FindRecipesSharingIngredientsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.FindRecipesSharingIngredientsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.FindRecipesSharingIngredientsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_FindRecipesSharingIngredients_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRecipesSharingIngredients'
+type MockQuerier_FindRecipesSharingIngredients_Call struct {
+	*mock.Call
+}
+
+// FindRecipesSharingIngredients is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.FindRecipesSharingIngredientsParams
+func (_e *MockQuerier_Expecter) FindRecipesSharingIngredients(ctx interface{}, arg interface{}) *MockQuerier_FindRecipesSharingIngredients_Call {
+	return &MockQuerier_FindRecipesSharingIngredients_Call{Call: _e.mock.On("FindRecipesSharingIngredients", ctx, arg)}
+}
+
+func (_c *MockQuerier_FindRecipesSharingIngredients_Call) Run(run func(ctx context.Context, arg db.FindRecipesSharingIngredientsParams)) *MockQuerier_FindRecipesSharingIngredients_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.FindRecipesSharingIngredientsParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_FindRecipesSharingIngredients_Call) Return(_a0 []db.FindRecipesSharingIngredientsRow, _a1 error) *MockQuerier_FindRecipesSharingIngredients_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_FindRecipesSharingIngredients_Call) RunAndReturn(run func(context.Context, db.FindRecipesSharingIngredientsParams) ([]db.FindRecipesSharingIngredientsRow, error)) *MockQuerier_FindRecipesSharingIngredients_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIngestionBatch provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) GetIngestionBatch(ctx context.Context, arg db.GetIngestionBatchParams) (db.IngestionBatch, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIngestionBatch")
+	}
+
+	var r0 db.IngestionBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetIngestionBatchParams) (db.IngestionBatch, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetIngestionBatchParams) db.IngestionBatch); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IngestionBatch)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetIngestionBatchParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetIngestionBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIngestionBatch'
+type MockQuerier_GetIngestionBatch_Call struct {
+	*mock.Call
+}
+
+// GetIngestionBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.GetIngestionBatchParams
+func (_e *MockQuerier_Expecter) GetIngestionBatch(ctx interface{}, arg interface{}) *MockQuerier_GetIngestionBatch_Call {
+	return &MockQuerier_GetIngestionBatch_Call{Call: _e.mock.On("GetIngestionBatch", ctx, arg)}
+}
+
+func (_c *MockQuerier_GetIngestionBatch_Call) Run(run func(ctx context.Context, arg db.GetIngestionBatchParams)) *MockQuerier_GetIngestionBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.GetIngestionBatchParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetIngestionBatch_Call) Return(_a0 db.IngestionBatch, _a1 error) *MockQuerier_GetIngestionBatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetIngestionBatch_Call) RunAndReturn(run func(context.Context, db.GetIngestionBatchParams) (db.IngestionBatch, error)) *MockQuerier_GetIngestionBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIngestionFile provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) GetIngestionFile(ctx context.Context, arg db.GetIngestionFileParams) (db.IngestionFile, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIngestionFile")
+	}
+
+	var r0 db.IngestionFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetIngestionFileParams) (db.IngestionFile, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetIngestionFileParams) db.IngestionFile); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IngestionFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetIngestionFileParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetIngestionFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIngestionFile'
+type MockQuerier_GetIngestionFile_Call struct {
+	*mock.Call
+}
+
+// GetIngestionFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.GetIngestionFileParams
+func (_e *MockQuerier_Expecter) GetIngestionFile(ctx interface{}, arg interface{}) *MockQuerier_GetIngestionFile_Call {
+	return &MockQuerier_GetIngestionFile_Call{Call: _e.mock.On("GetIngestionFile", ctx, arg)}
+}
+
+func (_c *MockQuerier_GetIngestionFile_Call) Run(run func(ctx context.Context, arg db.GetIngestionFileParams)) *MockQuerier_GetIngestionFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.GetIngestionFileParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetIngestionFile_Call) Return(_a0 db.IngestionFile, _a1 error) *MockQuerier_GetIngestionFile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetIngestionFile_Call) RunAndReturn(run func(context.Context, db.GetIngestionFileParams) (db.IngestionFile, error)) *MockQuerier_GetIngestionFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIngestionJob provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) GetIngestionJob(ctx context.Context, arg db.GetIngestionJobParams) (db.IngestionJob, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIngestionJob")
+	}
+
+	var r0 db.IngestionJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetIngestionJobParams) (db.IngestionJob, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetIngestionJobParams) db.IngestionJob); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IngestionJob)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetIngestionJobParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetIngestionJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIngestionJob'
+type MockQuerier_GetIngestionJob_Call struct {
+	*mock.Call
+}
+
+// GetIngestionJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.GetIngestionJobParams
+func (_e *MockQuerier_Expecter) GetIngestionJob(ctx interface{}, arg interface{}) *MockQuerier_GetIngestionJob_Call {
+	return &MockQuerier_GetIngestionJob_Call{Call: _e.mock.On("GetIngestionJob", ctx, arg)}
+}
+
+func (_c *MockQuerier_GetIngestionJob_Call) Run(run func(ctx context.Context, arg db.GetIngestionJobParams)) *MockQuerier_GetIngestionJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.GetIngestionJobParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetIngestionJob_Call) Return(_a0 db.IngestionJob, _a1 error) *MockQuerier_GetIngestionJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetIngestionJob_Call) RunAndReturn(run func(context.Context, db.GetIngestionJobParams) (db.IngestionJob, error)) *MockQuerier_GetIngestionJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIngestionJobHousehold provides a mock function with given fields: ctx, id
+func (_m *MockQuerier) GetIngestionJobHousehold(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIngestionJobHousehold")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (uuid.UUID, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) uuid.UUID); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetIngestionJobHousehold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIngestionJobHousehold'
+type MockQuerier_GetIngestionJobHousehold_Call struct {
+	*mock.Call
+}
+
+// GetIngestionJobHousehold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockQuerier_Expecter) GetIngestionJobHousehold(ctx interface{}, id interface{}) *MockQuerier_GetIngestionJobHousehold_Call {
+	return &MockQuerier_GetIngestionJobHousehold_Call{Call: _e.mock.On("GetIngestionJobHousehold", ctx, id)}
+}
+
+func (_c *MockQuerier_GetIngestionJobHousehold_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockQuerier_GetIngestionJobHousehold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetIngestionJobHousehold_Call) Return(_a0 uuid.UUID, _a1 error) *MockQuerier_GetIngestionJobHousehold_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetIngestionJobHousehold_Call) RunAndReturn(run func(context.Context, uuid.UUID) (uuid.UUID, error)) *MockQuerier_GetIngestionJobHousehold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMaxStepNumber provides a mock function with given fields: ctx, recipeID
+func (_m *MockQuerier) GetMaxStepNumber(ctx context.Context, recipeID uuid.UUID) (int32, error) {
+	ret := _m.Called(ctx, recipeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMaxStepNumber")
+	}
+
+	var r0 int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int32, error)); ok {
 		return rf(ctx, recipeID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []db.RecipeIngredient); ok {
-		r0 = rf(ctx, recipeID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int32); ok {
+		r0 = rf(ctx, recipeID)
+	} else {
+		r0 = ret.Get(0).(int32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, recipeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetMaxStepNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMaxStepNumber'
+type MockQuerier_GetMaxStepNumber_Call struct {
+	*mock.Call
+}
+
+// GetMaxStepNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipeID uuid.UUID
+func (_e *MockQuerier_Expecter) GetMaxStepNumber(ctx interface{}, recipeID interface{}) *MockQuerier_GetMaxStepNumber_Call {
+	return &MockQuerier_GetMaxStepNumber_Call{Call: _e.mock.On("GetMaxStepNumber", ctx, recipeID)}
+}
+
+func (_c *MockQuerier_GetMaxStepNumber_Call) Run(run func(ctx context.Context, recipeID uuid.UUID)) *MockQuerier_GetMaxStepNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetMaxStepNumber_Call) Return(_a0 int32, _a1 error) *MockQuerier_GetMaxStepNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetMaxStepNumber_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int32, error)) *MockQuerier_GetMaxStepNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRecipe provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) GetRecipe(ctx context.Context, arg db.GetRecipeParams) (db.Recipe, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecipe")
+	}
+
+	var r0 db.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetRecipeParams) (db.Recipe, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetRecipeParams) db.Recipe); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Recipe)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetRecipeParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecipe'
+type MockQuerier_GetRecipe_Call struct {
+	*mock.Call
+}
+
+// GetRecipe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.GetRecipeParams
+func (_e *MockQuerier_Expecter) GetRecipe(ctx interface{}, arg interface{}) *MockQuerier_GetRecipe_Call {
+	return &MockQuerier_GetRecipe_Call{Call: _e.mock.On("GetRecipe", ctx, arg)}
+}
+
+func (_c *MockQuerier_GetRecipe_Call) Run(run func(ctx context.Context, arg db.GetRecipeParams)) *MockQuerier_GetRecipe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.GetRecipeParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetRecipe_Call) Return(_a0 db.Recipe, _a1 error) *MockQuerier_GetRecipe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetRecipe_Call) RunAndReturn(run func(context.Context, db.GetRecipeParams) (db.Recipe, error)) *MockQuerier_GetRecipe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRecipeIngredient provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) GetRecipeIngredient(ctx context.Context, arg db.GetRecipeIngredientParams) (db.RecipeIngredient, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecipeIngredient")
+	}
+
+	var r0 db.RecipeIngredient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetRecipeIngredientParams) (db.RecipeIngredient, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetRecipeIngredientParams) db.RecipeIngredient); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.RecipeIngredient)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetRecipeIngredientParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetRecipeIngredient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecipeIngredient'
+type MockQuerier_GetRecipeIngredient_Call struct {
+	*mock.Call
+}
+
+// GetRecipeIngredient is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.GetRecipeIngredientParams
+func (_e *MockQuerier_Expecter) GetRecipeIngredient(ctx interface{}, arg interface{}) *MockQuerier_GetRecipeIngredient_Call {
+	return &MockQuerier_GetRecipeIngredient_Call{Call: _e.mock.On("GetRecipeIngredient", ctx, arg)}
+}
+
+func (_c *MockQuerier_GetRecipeIngredient_Call) Run(run func(ctx context.Context, arg db.GetRecipeIngredientParams)) *MockQuerier_GetRecipeIngredient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.GetRecipeIngredientParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetRecipeIngredient_Call) Return(_a0 db.RecipeIngredient, _a1 error) *MockQuerier_GetRecipeIngredient_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetRecipeIngredient_Call) RunAndReturn(run func(context.Context, db.GetRecipeIngredientParams) (db.RecipeIngredient, error)) *MockQuerier_GetRecipeIngredient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRecipeRevision provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) GetRecipeRevision(ctx context.Context, arg db.GetRecipeRevisionParams) (db.RecipeRevision, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecipeRevision")
+	}
+
+	var r0 db.RecipeRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetRecipeRevisionParams) (db.RecipeRevision, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetRecipeRevisionParams) db.RecipeRevision); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.RecipeRevision)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetRecipeRevisionParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetRecipeRevision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecipeRevision'
+type MockQuerier_GetRecipeRevision_Call struct {
+	*mock.Call
+}
+
+// GetRecipeRevision is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.GetRecipeRevisionParams
+func (_e *MockQuerier_Expecter) GetRecipeRevision(ctx interface{}, arg interface{}) *MockQuerier_GetRecipeRevision_Call {
+	return &MockQuerier_GetRecipeRevision_Call{Call: _e.mock.On("GetRecipeRevision", ctx, arg)}
+}
+
+func (_c *MockQuerier_GetRecipeRevision_Call) Run(run func(ctx context.Context, arg db.GetRecipeRevisionParams)) *MockQuerier_GetRecipeRevision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.GetRecipeRevisionParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetRecipeRevision_Call) Return(_a0 db.RecipeRevision, _a1 error) *MockQuerier_GetRecipeRevision_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetRecipeRevision_Call) RunAndReturn(run func(context.Context, db.GetRecipeRevisionParams) (db.RecipeRevision, error)) *MockQuerier_GetRecipeRevision_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetStep provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) GetStep(ctx context.Context, arg db.GetStepParams) (db.RecipeStep, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStep")
+	}
+
+	var r0 db.RecipeStep
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetStepParams) (db.RecipeStep, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetStepParams) db.RecipeStep); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.RecipeStep)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetStepParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_GetStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStep'
+type MockQuerier_GetStep_Call struct {
+	*mock.Call
+}
+
+// GetStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.GetStepParams
+func (_e *MockQuerier_Expecter) GetStep(ctx interface{}, arg interface{}) *MockQuerier_GetStep_Call {
+	return &MockQuerier_GetStep_Call{Call: _e.mock.On("GetStep", ctx, arg)}
+}
+
+func (_c *MockQuerier_GetStep_Call) Run(run func(ctx context.Context, arg db.GetStepParams)) *MockQuerier_GetStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.GetStepParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_GetStep_Call) Return(_a0 db.RecipeStep, _a1 error) *MockQuerier_GetStep_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_GetStep_Call) RunAndReturn(run func(context.Context, db.GetStepParams) (db.RecipeStep, error)) *MockQuerier_GetStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertOutboxEvent provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) InsertOutboxEvent(ctx context.Context, arg db.InsertOutboxEventParams) (db.EventOutbox, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOutboxEvent")
+	}
+
+	var r0 db.EventOutbox
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.InsertOutboxEventParams) (db.EventOutbox, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.InsertOutboxEventParams) db.EventOutbox); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.EventOutbox)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.InsertOutboxEventParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_InsertOutboxEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertOutboxEvent'
+type MockQuerier_InsertOutboxEvent_Call struct {
+	*mock.Call
+}
+
+// InsertOutboxEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.InsertOutboxEventParams
+func (_e *MockQuerier_Expecter) InsertOutboxEvent(ctx interface{}, arg interface{}) *MockQuerier_InsertOutboxEvent_Call {
+	return &MockQuerier_InsertOutboxEvent_Call{Call: _e.mock.On("InsertOutboxEvent", ctx, arg)}
+}
+
+func (_c *MockQuerier_InsertOutboxEvent_Call) Run(run func(ctx context.Context, arg db.InsertOutboxEventParams)) *MockQuerier_InsertOutboxEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.InsertOutboxEventParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_InsertOutboxEvent_Call) Return(_a0 db.EventOutbox, _a1 error) *MockQuerier_InsertOutboxEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_InsertOutboxEvent_Call) RunAndReturn(run func(context.Context, db.InsertOutboxEventParams) (db.EventOutbox, error)) *MockQuerier_InsertOutboxEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertRecipeAuditLog provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) InsertRecipeAuditLog(ctx context.Context, arg db.InsertRecipeAuditLogParams) (db.RecipeAuditLog, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertRecipeAuditLog")
+	}
+
+	var r0 db.RecipeAuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.InsertRecipeAuditLogParams) (db.RecipeAuditLog, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.InsertRecipeAuditLogParams) db.RecipeAuditLog); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.RecipeAuditLog)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.InsertRecipeAuditLogParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_InsertRecipeAuditLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertRecipeAuditLog'
+type MockQuerier_InsertRecipeAuditLog_Call struct {
+	*mock.Call
+}
+
+// InsertRecipeAuditLog is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.InsertRecipeAuditLogParams
+func (_e *MockQuerier_Expecter) InsertRecipeAuditLog(ctx interface{}, arg interface{}) *MockQuerier_InsertRecipeAuditLog_Call {
+	return &MockQuerier_InsertRecipeAuditLog_Call{Call: _e.mock.On("InsertRecipeAuditLog", ctx, arg)}
+}
+
+func (_c *MockQuerier_InsertRecipeAuditLog_Call) Run(run func(ctx context.Context, arg db.InsertRecipeAuditLogParams)) *MockQuerier_InsertRecipeAuditLog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.InsertRecipeAuditLogParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_InsertRecipeAuditLog_Call) Return(_a0 db.RecipeAuditLog, _a1 error) *MockQuerier_InsertRecipeAuditLog_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_InsertRecipeAuditLog_Call) RunAndReturn(run func(context.Context, db.InsertRecipeAuditLogParams) (db.RecipeAuditLog, error)) *MockQuerier_InsertRecipeAuditLog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertRecipeRevision provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) InsertRecipeRevision(ctx context.Context, arg db.InsertRecipeRevisionParams) (db.RecipeRevision, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertRecipeRevision")
+	}
+
+	var r0 db.RecipeRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.InsertRecipeRevisionParams) (db.RecipeRevision, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.InsertRecipeRevisionParams) db.RecipeRevision); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.RecipeRevision)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.InsertRecipeRevisionParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_InsertRecipeRevision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertRecipeRevision'
+type MockQuerier_InsertRecipeRevision_Call struct {
+	*mock.Call
+}
+
+// InsertRecipeRevision is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.InsertRecipeRevisionParams
+func (_e *MockQuerier_Expecter) InsertRecipeRevision(ctx interface{}, arg interface{}) *MockQuerier_InsertRecipeRevision_Call {
+	return &MockQuerier_InsertRecipeRevision_Call{Call: _e.mock.On("InsertRecipeRevision", ctx, arg)}
+}
+
+func (_c *MockQuerier_InsertRecipeRevision_Call) Run(run func(ctx context.Context, arg db.InsertRecipeRevisionParams)) *MockQuerier_InsertRecipeRevision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.InsertRecipeRevisionParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_InsertRecipeRevision_Call) Return(_a0 db.RecipeRevision, _a1 error) *MockQuerier_InsertRecipeRevision_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_InsertRecipeRevision_Call) RunAndReturn(run func(context.Context, db.InsertRecipeRevisionParams) (db.RecipeRevision, error)) *MockQuerier_InsertRecipeRevision_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIngestionJobs provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListIngestionJobs(ctx context.Context, arg db.ListIngestionJobsParams) ([]db.IngestionJob, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIngestionJobs")
+	}
+
+	var r0 []db.IngestionJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListIngestionJobsParams) ([]db.IngestionJob, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListIngestionJobsParams) []db.IngestionJob); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.IngestionJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListIngestionJobsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListIngestionJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIngestionJobs'
+type MockQuerier_ListIngestionJobs_Call struct {
+	*mock.Call
+}
+
+// ListIngestionJobs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListIngestionJobsParams
+func (_e *MockQuerier_Expecter) ListIngestionJobs(ctx interface{}, arg interface{}) *MockQuerier_ListIngestionJobs_Call {
+	return &MockQuerier_ListIngestionJobs_Call{Call: _e.mock.On("ListIngestionJobs", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListIngestionJobs_Call) Run(run func(ctx context.Context, arg db.ListIngestionJobsParams)) *MockQuerier_ListIngestionJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListIngestionJobsParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListIngestionJobs_Call) Return(_a0 []db.IngestionJob, _a1 error) *MockQuerier_ListIngestionJobs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListIngestionJobs_Call) RunAndReturn(run func(context.Context, db.ListIngestionJobsParams) ([]db.IngestionJob, error)) *MockQuerier_ListIngestionJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIngestionJobsByBatch provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListIngestionJobsByBatch(ctx context.Context, arg db.ListIngestionJobsByBatchParams) ([]db.IngestionJob, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIngestionJobsByBatch")
+	}
+
+	var r0 []db.IngestionJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListIngestionJobsByBatchParams) ([]db.IngestionJob, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListIngestionJobsByBatchParams) []db.IngestionJob); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.IngestionJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListIngestionJobsByBatchParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListIngestionJobsByBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIngestionJobsByBatch'
+type MockQuerier_ListIngestionJobsByBatch_Call struct {
+	*mock.Call
+}
+
+// ListIngestionJobsByBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListIngestionJobsByBatchParams
+func (_e *MockQuerier_Expecter) ListIngestionJobsByBatch(ctx interface{}, arg interface{}) *MockQuerier_ListIngestionJobsByBatch_Call {
+	return &MockQuerier_ListIngestionJobsByBatch_Call{Call: _e.mock.On("ListIngestionJobsByBatch", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListIngestionJobsByBatch_Call) Run(run func(ctx context.Context, arg db.ListIngestionJobsByBatchParams)) *MockQuerier_ListIngestionJobsByBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListIngestionJobsByBatchParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListIngestionJobsByBatch_Call) Return(_a0 []db.IngestionJob, _a1 error) *MockQuerier_ListIngestionJobsByBatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListIngestionJobsByBatch_Call) RunAndReturn(run func(context.Context, db.ListIngestionJobsByBatchParams) ([]db.IngestionJob, error)) *MockQuerier_ListIngestionJobsByBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIngredientsByRecipe provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListIngredientsByRecipe(ctx context.Context, arg db.ListIngredientsByRecipeParams) ([]db.RecipeIngredient, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIngredientsByRecipe")
+	}
+
+	var r0 []db.RecipeIngredient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListIngredientsByRecipeParams) ([]db.RecipeIngredient, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListIngredientsByRecipeParams) []db.RecipeIngredient); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.RecipeIngredient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListIngredientsByRecipeParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListIngredientsByRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIngredientsByRecipe'
+type MockQuerier_ListIngredientsByRecipe_Call struct {
+	*mock.Call
+}
+
+// ListIngredientsByRecipe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListIngredientsByRecipeParams
+func (_e *MockQuerier_Expecter) ListIngredientsByRecipe(ctx interface{}, arg interface{}) *MockQuerier_ListIngredientsByRecipe_Call {
+	return &MockQuerier_ListIngredientsByRecipe_Call{Call: _e.mock.On("ListIngredientsByRecipe", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListIngredientsByRecipe_Call) Run(run func(ctx context.Context, arg db.ListIngredientsByRecipeParams)) *MockQuerier_ListIngredientsByRecipe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListIngredientsByRecipeParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListIngredientsByRecipe_Call) Return(_a0 []db.RecipeIngredient, _a1 error) *MockQuerier_ListIngredientsByRecipe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListIngredientsByRecipe_Call) RunAndReturn(run func(context.Context, db.ListIngredientsByRecipeParams) ([]db.RecipeIngredient, error)) *MockQuerier_ListIngredientsByRecipe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPendingOutboxEvents provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListPendingOutboxEvents(ctx context.Context, arg db.ListPendingOutboxEventsParams) ([]db.EventOutbox, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPendingOutboxEvents")
+	}
+
+	var r0 []db.EventOutbox
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListPendingOutboxEventsParams) ([]db.EventOutbox, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListPendingOutboxEventsParams) []db.EventOutbox); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.EventOutbox)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListPendingOutboxEventsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListPendingOutboxEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPendingOutboxEvents'
+type MockQuerier_ListPendingOutboxEvents_Call struct {
+	*mock.Call
+}
+
+// ListPendingOutboxEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListPendingOutboxEventsParams
+func (_e *MockQuerier_Expecter) ListPendingOutboxEvents(ctx interface{}, arg interface{}) *MockQuerier_ListPendingOutboxEvents_Call {
+	return &MockQuerier_ListPendingOutboxEvents_Call{Call: _e.mock.On("ListPendingOutboxEvents", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListPendingOutboxEvents_Call) Run(run func(ctx context.Context, arg db.ListPendingOutboxEventsParams)) *MockQuerier_ListPendingOutboxEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListPendingOutboxEventsParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListPendingOutboxEvents_Call) Return(_a0 []db.EventOutbox, _a1 error) *MockQuerier_ListPendingOutboxEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListPendingOutboxEvents_Call) RunAndReturn(run func(context.Context, db.ListPendingOutboxEventsParams) ([]db.EventOutbox, error)) *MockQuerier_ListPendingOutboxEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRecipeAuditLog provides a mock function with given fields: ctx, recipeID
+func (_m *MockQuerier) ListRecipeAuditLog(ctx context.Context, recipeID uuid.UUID) ([]db.RecipeAuditLog, error) {
+	ret := _m.Called(ctx, recipeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecipeAuditLog")
+	}
+
+	var r0 []db.RecipeAuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]db.RecipeAuditLog, error)); ok {
+		return rf(ctx, recipeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []db.RecipeAuditLog); ok {
+		r0 = rf(ctx, recipeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.RecipeAuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, recipeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListRecipeAuditLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipeAuditLog'
+type MockQuerier_ListRecipeAuditLog_Call struct {
+	*mock.Call
+}
+
+// ListRecipeAuditLog is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipeID uuid.UUID
+func (_e *MockQuerier_Expecter) ListRecipeAuditLog(ctx interface{}, recipeID interface{}) *MockQuerier_ListRecipeAuditLog_Call {
+	return &MockQuerier_ListRecipeAuditLog_Call{Call: _e.mock.On("ListRecipeAuditLog", ctx, recipeID)}
+}
+
+func (_c *MockQuerier_ListRecipeAuditLog_Call) Run(run func(ctx context.Context, recipeID uuid.UUID)) *MockQuerier_ListRecipeAuditLog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipeAuditLog_Call) Return(_a0 []db.RecipeAuditLog, _a1 error) *MockQuerier_ListRecipeAuditLog_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipeAuditLog_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]db.RecipeAuditLog, error)) *MockQuerier_ListRecipeAuditLog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRecipeRevisions provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListRecipeRevisions(ctx context.Context, arg db.ListRecipeRevisionsParams) ([]db.RecipeRevision, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecipeRevisions")
+	}
+
+	var r0 []db.RecipeRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListRecipeRevisionsParams) ([]db.RecipeRevision, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListRecipeRevisionsParams) []db.RecipeRevision); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.RecipeRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListRecipeRevisionsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListRecipeRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipeRevisions'
+type MockQuerier_ListRecipeRevisions_Call struct {
+	*mock.Call
+}
+
+// ListRecipeRevisions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListRecipeRevisionsParams
+func (_e *MockQuerier_Expecter) ListRecipeRevisions(ctx interface{}, arg interface{}) *MockQuerier_ListRecipeRevisions_Call {
+	return &MockQuerier_ListRecipeRevisions_Call{Call: _e.mock.On("ListRecipeRevisions", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListRecipeRevisions_Call) Run(run func(ctx context.Context, arg db.ListRecipeRevisionsParams)) *MockQuerier_ListRecipeRevisions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListRecipeRevisionsParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipeRevisions_Call) Return(_a0 []db.RecipeRevision, _a1 error) *MockQuerier_ListRecipeRevisions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipeRevisions_Call) RunAndReturn(run func(context.Context, db.ListRecipeRevisionsParams) ([]db.RecipeRevision, error)) *MockQuerier_ListRecipeRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRecipeShares provides a mock function with given fields: ctx, recipeID
+func (_m *MockQuerier) ListRecipeShares(ctx context.Context, recipeID uuid.UUID) ([]db.RecipeShare, error) {
+	ret := _m.Called(ctx, recipeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecipeShares")
+	}
+
+	var r0 []db.RecipeShare
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]db.RecipeShare, error)); ok {
+		return rf(ctx, recipeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []db.RecipeShare); ok {
+		r0 = rf(ctx, recipeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.RecipeShare)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, recipeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListRecipeShares_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipeShares'
+type MockQuerier_ListRecipeShares_Call struct {
+	*mock.Call
+}
+
+// ListRecipeShares is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipeID uuid.UUID
+func (_e *MockQuerier_Expecter) ListRecipeShares(ctx interface{}, recipeID interface{}) *MockQuerier_ListRecipeShares_Call {
+	return &MockQuerier_ListRecipeShares_Call{Call: _e.mock.On("ListRecipeShares", ctx, recipeID)}
+}
+
+func (_c *MockQuerier_ListRecipeShares_Call) Run(run func(ctx context.Context, recipeID uuid.UUID)) *MockQuerier_ListRecipeShares_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipeShares_Call) Return(_a0 []db.RecipeShare, _a1 error) *MockQuerier_ListRecipeShares_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipeShares_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]db.RecipeShare, error)) *MockQuerier_ListRecipeShares_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRecipes provides a mock function with given fields: ctx, householdID
+func (_m *MockQuerier) ListRecipes(ctx context.Context, householdID uuid.UUID) ([]db.Recipe, error) {
+	ret := _m.Called(ctx, householdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecipes")
+	}
+
+	var r0 []db.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]db.Recipe, error)); ok {
+		return rf(ctx, householdID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []db.Recipe); ok {
+		r0 = rf(ctx, householdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, householdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListRecipes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipes'
+type MockQuerier_ListRecipes_Call struct {
+	*mock.Call
+}
+
+// ListRecipes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - householdID uuid.UUID
+func (_e *MockQuerier_Expecter) ListRecipes(ctx interface{}, householdID interface{}) *MockQuerier_ListRecipes_Call {
+	return &MockQuerier_ListRecipes_Call{Call: _e.mock.On("ListRecipes", ctx, householdID)}
+}
+
+func (_c *MockQuerier_ListRecipes_Call) Run(run func(ctx context.Context, householdID uuid.UUID)) *MockQuerier_ListRecipes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipes_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListRecipes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipes_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]db.Recipe, error)) *MockQuerier_ListRecipes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRecipesByCookTime provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListRecipesByCookTime(ctx context.Context, arg db.ListRecipesByCookTimeParams) ([]db.Recipe, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecipesByCookTime")
+	}
+
+	var r0 []db.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListRecipesByCookTimeParams) ([]db.Recipe, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListRecipesByCookTimeParams) []db.Recipe); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListRecipesByCookTimeParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListRecipesByCookTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipesByCookTime'
+type MockQuerier_ListRecipesByCookTime_Call struct {
+	*mock.Call
+}
+
+// ListRecipesByCookTime is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListRecipesByCookTimeParams
+func (_e *MockQuerier_Expecter) ListRecipesByCookTime(ctx interface{}, arg interface{}) *MockQuerier_ListRecipesByCookTime_Call {
+	return &MockQuerier_ListRecipesByCookTime_Call{Call: _e.mock.On("ListRecipesByCookTime", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListRecipesByCookTime_Call) Run(run func(ctx context.Context, arg db.ListRecipesByCookTimeParams)) *MockQuerier_ListRecipesByCookTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListRecipesByCookTimeParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipesByCookTime_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListRecipesByCookTime_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipesByCookTime_Call) RunAndReturn(run func(context.Context, db.ListRecipesByCookTimeParams) ([]db.Recipe, error)) *MockQuerier_ListRecipesByCookTime_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRecipesByTag provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListRecipesByTag(ctx context.Context, arg db.ListRecipesByTagParams) ([]db.Recipe, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecipesByTag")
+	}
+
+	var r0 []db.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListRecipesByTagParams) ([]db.Recipe, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListRecipesByTagParams) []db.Recipe); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListRecipesByTagParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListRecipesByTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipesByTag'
+type MockQuerier_ListRecipesByTag_Call struct {
+	*mock.Call
+}
+
+// ListRecipesByTag is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListRecipesByTagParams
+func (_e *MockQuerier_Expecter) ListRecipesByTag(ctx interface{}, arg interface{}) *MockQuerier_ListRecipesByTag_Call {
+	return &MockQuerier_ListRecipesByTag_Call{Call: _e.mock.On("ListRecipesByTag", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListRecipesByTag_Call) Run(run func(ctx context.Context, arg db.ListRecipesByTagParams)) *MockQuerier_ListRecipesByTag_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListRecipesByTagParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipesByTag_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListRecipesByTag_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipesByTag_Call) RunAndReturn(run func(context.Context, db.ListRecipesByTagParams) ([]db.Recipe, error)) *MockQuerier_ListRecipesByTag_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRecipesByTitle provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListRecipesByTitle(ctx context.Context, arg db.ListRecipesByTitleParams) ([]db.Recipe, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecipesByTitle")
+	}
+
+	var r0 []db.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListRecipesByTitleParams) ([]db.Recipe, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListRecipesByTitleParams) []db.Recipe); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListRecipesByTitleParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListRecipesByTitle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipesByTitle'
+type MockQuerier_ListRecipesByTitle_Call struct {
+	*mock.Call
+}
+
+// ListRecipesByTitle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListRecipesByTitleParams
+func (_e *MockQuerier_Expecter) ListRecipesByTitle(ctx interface{}, arg interface{}) *MockQuerier_ListRecipesByTitle_Call {
+	return &MockQuerier_ListRecipesByTitle_Call{Call: _e.mock.On("ListRecipesByTitle", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListRecipesByTitle_Call) Run(run func(ctx context.Context, arg db.ListRecipesByTitleParams)) *MockQuerier_ListRecipesByTitle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListRecipesByTitleParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipesByTitle_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListRecipesByTitle_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListRecipesByTitle_Call) RunAndReturn(run func(context.Context, db.ListRecipesByTitleParams) ([]db.Recipe, error)) *MockQuerier_ListRecipesByTitle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListStepsByRecipe provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ListStepsByRecipe(ctx context.Context, arg db.ListStepsByRecipeParams) ([]db.RecipeStep, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStepsByRecipe")
+	}
+
+	var r0 []db.RecipeStep
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListStepsByRecipeParams) ([]db.RecipeStep, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListStepsByRecipeParams) []db.RecipeStep); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.RecipeStep)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListStepsByRecipeParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListStepsByRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListStepsByRecipe'
+type MockQuerier_ListStepsByRecipe_Call struct {
+	*mock.Call
+}
+
+// ListStepsByRecipe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ListStepsByRecipeParams
+func (_e *MockQuerier_Expecter) ListStepsByRecipe(ctx interface{}, arg interface{}) *MockQuerier_ListStepsByRecipe_Call {
+	return &MockQuerier_ListStepsByRecipe_Call{Call: _e.mock.On("ListStepsByRecipe", ctx, arg)}
+}
+
+func (_c *MockQuerier_ListStepsByRecipe_Call) Run(run func(ctx context.Context, arg db.ListStepsByRecipeParams)) *MockQuerier_ListStepsByRecipe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ListStepsByRecipeParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListStepsByRecipe_Call) Return(_a0 []db.RecipeStep, _a1 error) *MockQuerier_ListStepsByRecipe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListStepsByRecipe_Call) RunAndReturn(run func(context.Context, db.ListStepsByRecipeParams) ([]db.RecipeStep, error)) *MockQuerier_ListStepsByRecipe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTrashedRecipes provides a mock function with given fields: ctx, householdID
+func (_m *MockQuerier) ListTrashedRecipes(ctx context.Context, householdID uuid.UUID) ([]db.Recipe, error) {
+	ret := _m.Called(ctx, householdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrashedRecipes")
+	}
+
+	var r0 []db.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]db.Recipe, error)); ok {
+		return rf(ctx, householdID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []db.Recipe); ok {
+		r0 = rf(ctx, householdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, householdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_ListTrashedRecipes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTrashedRecipes'
+type MockQuerier_ListTrashedRecipes_Call struct {
+	*mock.Call
+}
+
+// ListTrashedRecipes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - householdID uuid.UUID
+func (_e *MockQuerier_Expecter) ListTrashedRecipes(ctx interface{}, householdID interface{}) *MockQuerier_ListTrashedRecipes_Call {
+	return &MockQuerier_ListTrashedRecipes_Call{Call: _e.mock.On("ListTrashedRecipes", ctx, householdID)}
+}
+
+func (_c *MockQuerier_ListTrashedRecipes_Call) Run(run func(ctx context.Context, householdID uuid.UUID)) *MockQuerier_ListTrashedRecipes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ListTrashedRecipes_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListTrashedRecipes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_ListTrashedRecipes_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]db.Recipe, error)) *MockQuerier_ListTrashedRecipes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockRecipe provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) LockRecipe(ctx context.Context, arg db.LockRecipeParams) (db.Recipe, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockRecipe")
+	}
+
+	var r0 db.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.LockRecipeParams) (db.Recipe, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.LockRecipeParams) db.Recipe); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Recipe)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.LockRecipeParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_LockRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockRecipe'
+type MockQuerier_LockRecipe_Call struct {
+	*mock.Call
+}
+
+// LockRecipe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.LockRecipeParams
+func (_e *MockQuerier_Expecter) LockRecipe(ctx interface{}, arg interface{}) *MockQuerier_LockRecipe_Call {
+	return &MockQuerier_LockRecipe_Call{Call: _e.mock.On("LockRecipe", ctx, arg)}
+}
+
+func (_c *MockQuerier_LockRecipe_Call) Run(run func(ctx context.Context, arg db.LockRecipeParams)) *MockQuerier_LockRecipe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.LockRecipeParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_LockRecipe_Call) Return(_a0 db.Recipe, _a1 error) *MockQuerier_LockRecipe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_LockRecipe_Call) RunAndReturn(run func(context.Context, db.LockRecipeParams) (db.Recipe, error)) *MockQuerier_LockRecipe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkIngestionJobConfirmed provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) MarkIngestionJobConfirmed(ctx context.Context, arg db.MarkIngestionJobConfirmedParams) (db.IngestionJob, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkIngestionJobConfirmed")
+	}
+
+	var r0 db.IngestionJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.MarkIngestionJobConfirmedParams) (db.IngestionJob, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.MarkIngestionJobConfirmedParams) db.IngestionJob); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IngestionJob)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.MarkIngestionJobConfirmedParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_MarkIngestionJobConfirmed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkIngestionJobConfirmed'
+type MockQuerier_MarkIngestionJobConfirmed_Call struct {
+	*mock.Call
+}
+
+// MarkIngestionJobConfirmed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.MarkIngestionJobConfirmedParams
+func (_e *MockQuerier_Expecter) MarkIngestionJobConfirmed(ctx interface{}, arg interface{}) *MockQuerier_MarkIngestionJobConfirmed_Call {
+	return &MockQuerier_MarkIngestionJobConfirmed_Call{Call: _e.mock.On("MarkIngestionJobConfirmed", ctx, arg)}
+}
+
+func (_c *MockQuerier_MarkIngestionJobConfirmed_Call) Run(run func(ctx context.Context, arg db.MarkIngestionJobConfirmedParams)) *MockQuerier_MarkIngestionJobConfirmed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.MarkIngestionJobConfirmedParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_MarkIngestionJobConfirmed_Call) Return(_a0 db.IngestionJob, _a1 error) *MockQuerier_MarkIngestionJobConfirmed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_MarkIngestionJobConfirmed_Call) RunAndReturn(run func(context.Context, db.MarkIngestionJobConfirmedParams) (db.IngestionJob, error)) *MockQuerier_MarkIngestionJobConfirmed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkOutboxEventFailed provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) MarkOutboxEventFailed(ctx context.Context, arg db.MarkOutboxEventFailedParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkOutboxEventFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.MarkOutboxEventFailedParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockQuerier_MarkOutboxEventFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkOutboxEventFailed'
+type MockQuerier_MarkOutboxEventFailed_Call struct {
+	*mock.Call
+}
+
+// MarkOutboxEventFailed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.MarkOutboxEventFailedParams
+func (_e *MockQuerier_Expecter) MarkOutboxEventFailed(ctx interface{}, arg interface{}) *MockQuerier_MarkOutboxEventFailed_Call {
+	return &MockQuerier_MarkOutboxEventFailed_Call{Call: _e.mock.On("MarkOutboxEventFailed", ctx, arg)}
+}
+
+func (_c *MockQuerier_MarkOutboxEventFailed_Call) Run(run func(ctx context.Context, arg db.MarkOutboxEventFailedParams)) *MockQuerier_MarkOutboxEventFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.MarkOutboxEventFailedParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_MarkOutboxEventFailed_Call) Return(_a0 error) *MockQuerier_MarkOutboxEventFailed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockQuerier_MarkOutboxEventFailed_Call) RunAndReturn(run func(context.Context, db.MarkOutboxEventFailedParams) error) *MockQuerier_MarkOutboxEventFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkOutboxEventPublished provides a mock function with given fields: ctx, id
+func (_m *MockQuerier) MarkOutboxEventPublished(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkOutboxEventPublished")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockQuerier_MarkOutboxEventPublished_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkOutboxEventPublished'
+type MockQuerier_MarkOutboxEventPublished_Call struct {
+	*mock.Call
+}
+
+// MarkOutboxEventPublished is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockQuerier_Expecter) MarkOutboxEventPublished(ctx interface{}, id interface{}) *MockQuerier_MarkOutboxEventPublished_Call {
+	return &MockQuerier_MarkOutboxEventPublished_Call{Call: _e.mock.On("MarkOutboxEventPublished", ctx, id)}
+}
+
+func (_c *MockQuerier_MarkOutboxEventPublished_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockQuerier_MarkOutboxEventPublished_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_MarkOutboxEventPublished_Call) Return(_a0 error) *MockQuerier_MarkOutboxEventPublished_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockQuerier_MarkOutboxEventPublished_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockQuerier_MarkOutboxEventPublished_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MoveStep provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) MoveStep(ctx context.Context, arg db.MoveStepParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.MoveStepParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockQuerier_MoveStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MoveStep'
+type MockQuerier_MoveStep_Call struct {
+	*mock.Call
+}
+
+// MoveStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.MoveStepParams
+func (_e *MockQuerier_Expecter) MoveStep(ctx interface{}, arg interface{}) *MockQuerier_MoveStep_Call {
+	return &MockQuerier_MoveStep_Call{Call: _e.mock.On("MoveStep", ctx, arg)}
+}
+
+func (_c *MockQuerier_MoveStep_Call) Run(run func(ctx context.Context, arg db.MoveStepParams)) *MockQuerier_MoveStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.MoveStepParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_MoveStep_Call) Return(_a0 error) *MockQuerier_MoveStep_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockQuerier_MoveStep_Call) RunAndReturn(run func(context.Context, db.MoveStepParams) error) *MockQuerier_MoveStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PrefillIngestionJob provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) PrefillIngestionJob(ctx context.Context, arg db.PrefillIngestionJobParams) (db.IngestionJob, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrefillIngestionJob")
+	}
+
+	var r0 db.IngestionJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.PrefillIngestionJobParams) (db.IngestionJob, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.PrefillIngestionJobParams) db.IngestionJob); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IngestionJob)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.PrefillIngestionJobParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_PrefillIngestionJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrefillIngestionJob'
+type MockQuerier_PrefillIngestionJob_Call struct {
+	*mock.Call
+}
+
+// PrefillIngestionJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.PrefillIngestionJobParams
+func (_e *MockQuerier_Expecter) PrefillIngestionJob(ctx interface{}, arg interface{}) *MockQuerier_PrefillIngestionJob_Call {
+	return &MockQuerier_PrefillIngestionJob_Call{Call: _e.mock.On("PrefillIngestionJob", ctx, arg)}
+}
+
+func (_c *MockQuerier_PrefillIngestionJob_Call) Run(run func(ctx context.Context, arg db.PrefillIngestionJobParams)) *MockQuerier_PrefillIngestionJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.PrefillIngestionJobParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_PrefillIngestionJob_Call) Return(_a0 db.IngestionJob, _a1 error) *MockQuerier_PrefillIngestionJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_PrefillIngestionJob_Call) RunAndReturn(run func(context.Context, db.PrefillIngestionJobParams) (db.IngestionJob, error)) *MockQuerier_PrefillIngestionJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeDeletedRecipes provides a mock function with given fields: ctx, cutoff
+func (_m *MockQuerier) PurgeDeletedRecipes(ctx context.Context, cutoff time.Time) (int64, error) {
+	ret := _m.Called(ctx, cutoff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedRecipes")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, cutoff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, cutoff)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, cutoff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_PurgeDeletedRecipes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeletedRecipes'
+type MockQuerier_PurgeDeletedRecipes_Call struct {
+	*mock.Call
+}
+
+// PurgeDeletedRecipes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cutoff time.Time
+func (_e *MockQuerier_Expecter) PurgeDeletedRecipes(ctx interface{}, cutoff interface{}) *MockQuerier_PurgeDeletedRecipes_Call {
+	return &MockQuerier_PurgeDeletedRecipes_Call{Call: _e.mock.On("PurgeDeletedRecipes", ctx, cutoff)}
+}
+
+func (_c *MockQuerier_PurgeDeletedRecipes_Call) Run(run func(ctx context.Context, cutoff time.Time)) *MockQuerier_PurgeDeletedRecipes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_PurgeDeletedRecipes_Call) Return(_a0 int64, _a1 error) *MockQuerier_PurgeDeletedRecipes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_PurgeDeletedRecipes_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *MockQuerier_PurgeDeletedRecipes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RejectIngestionJob provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) RejectIngestionJob(ctx context.Context, arg db.RejectIngestionJobParams) (db.IngestionJob, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectIngestionJob")
+	}
+
+	var r0 db.IngestionJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.RejectIngestionJobParams) (db.IngestionJob, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.RejectIngestionJobParams) db.IngestionJob); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IngestionJob)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.RejectIngestionJobParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_RejectIngestionJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RejectIngestionJob'
+type MockQuerier_RejectIngestionJob_Call struct {
+	*mock.Call
+}
+
+// RejectIngestionJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.RejectIngestionJobParams
+func (_e *MockQuerier_Expecter) RejectIngestionJob(ctx interface{}, arg interface{}) *MockQuerier_RejectIngestionJob_Call {
+	return &MockQuerier_RejectIngestionJob_Call{Call: _e.mock.On("RejectIngestionJob", ctx, arg)}
+}
+
+func (_c *MockQuerier_RejectIngestionJob_Call) Run(run func(ctx context.Context, arg db.RejectIngestionJobParams)) *MockQuerier_RejectIngestionJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.RejectIngestionJobParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_RejectIngestionJob_Call) Return(_a0 db.IngestionJob, _a1 error) *MockQuerier_RejectIngestionJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_RejectIngestionJob_Call) RunAndReturn(run func(context.Context, db.RejectIngestionJobParams) (db.IngestionJob, error)) *MockQuerier_RejectIngestionJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveRecipeShare provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) RemoveRecipeShare(ctx context.Context, arg db.RemoveRecipeShareParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveRecipeShare")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.RemoveRecipeShareParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.RemoveRecipeShareParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.RemoveRecipeShareParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_RemoveRecipeShare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveRecipeShare'
+type MockQuerier_RemoveRecipeShare_Call struct {
+	*mock.Call
+}
+
+// RemoveRecipeShare is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.RemoveRecipeShareParams
+func (_e *MockQuerier_Expecter) RemoveRecipeShare(ctx interface{}, arg interface{}) *MockQuerier_RemoveRecipeShare_Call {
+	return &MockQuerier_RemoveRecipeShare_Call{Call: _e.mock.On("RemoveRecipeShare", ctx, arg)}
+}
+
+func (_c *MockQuerier_RemoveRecipeShare_Call) Run(run func(ctx context.Context, arg db.RemoveRecipeShareParams)) *MockQuerier_RemoveRecipeShare_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.RemoveRecipeShareParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_RemoveRecipeShare_Call) Return(_a0 int64, _a1 error) *MockQuerier_RemoveRecipeShare_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_RemoveRecipeShare_Call) RunAndReturn(run func(context.Context, db.RemoveRecipeShareParams) (int64, error)) *MockQuerier_RemoveRecipeShare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RepointIngestionJobs provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) RepointIngestionJobs(ctx context.Context, arg db.RepointIngestionJobsParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepointIngestionJobs")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.RepointIngestionJobsParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.RepointIngestionJobsParams) int64); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.RecipeIngredient)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, recipeID)
+	if rf, ok := ret.Get(1).(func(context.Context, db.RepointIngestionJobsParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -537,58 +2820,56 @@ func (_m *MockQuerier) ListIngredientsByRecipe(ctx context.Context, recipeID uui
 	return r0, r1
 }
 
-// MockQuerier_ListIngredientsByRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIngredientsByRecipe'
-type MockQuerier_ListIngredientsByRecipe_Call struct {
+// MockQuerier_RepointIngestionJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepointIngestionJobs'
+type MockQuerier_RepointIngestionJobs_Call struct {
 	*mock.Call
 }
 
-// ListIngredientsByRecipe is a helper method to define mock.On call
+// RepointIngestionJobs is a helper method to define mock.On call
 //   - ctx context.Context
-//   - recipeID uuid.UUID
-func (_e *MockQuerier_Expecter) ListIngredientsByRecipe(ctx interface{}, recipeID interface{}) *MockQuerier_ListIngredientsByRecipe_Call {
-	return &MockQuerier_ListIngredientsByRecipe_Call{Call: _e.mock.On("ListIngredientsByRecipe", ctx, recipeID)}
+//   - arg db.RepointIngestionJobsParams
+func (_e *MockQuerier_Expecter) RepointIngestionJobs(ctx interface{}, arg interface{}) *MockQuerier_RepointIngestionJobs_Call {
+	return &MockQuerier_RepointIngestionJobs_Call{Call: _e.mock.On("RepointIngestionJobs", ctx, arg)}
 }
 
-func (_c *MockQuerier_ListIngredientsByRecipe_Call) Run(run func(ctx context.Context, recipeID uuid.UUID)) *MockQuerier_ListIngredientsByRecipe_Call {
+func (_c *MockQuerier_RepointIngestionJobs_Call) Run(run func(ctx context.Context, arg db.RepointIngestionJobsParams)) *MockQuerier_RepointIngestionJobs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(db.RepointIngestionJobsParams))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_ListIngredientsByRecipe_Call) Return(_a0 []db.RecipeIngredient, _a1 error) *MockQuerier_ListIngredientsByRecipe_Call {
+func (_c *MockQuerier_RepointIngestionJobs_Call) Return(_a0 int64, _a1 error) *MockQuerier_RepointIngestionJobs_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockQuerier_ListIngredientsByRecipe_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]db.RecipeIngredient, error)) *MockQuerier_ListIngredientsByRecipe_Call {
+func (_c *MockQuerier_RepointIngestionJobs_Call) RunAndReturn(run func(context.Context, db.RepointIngestionJobsParams) (int64, error)) *MockQuerier_RepointIngestionJobs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListRecipes provides a mock function with given fields: ctx
-func (_m *MockQuerier) ListRecipes(ctx context.Context) ([]db.Recipe, error) {
-	ret := _m.Called(ctx)
+// RepointRecipeShares provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) RepointRecipeShares(ctx context.Context, arg db.RepointRecipeSharesParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListRecipes")
+		panic("no return value specified for RepointRecipeShares")
 	}
 
-	var r0 []db.Recipe
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]db.Recipe, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, db.RepointRecipeSharesParams) (int64, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []db.Recipe); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, db.RepointRecipeSharesParams) int64); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.Recipe)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, db.RepointRecipeSharesParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -596,57 +2877,56 @@ func (_m *MockQuerier) ListRecipes(ctx context.Context) ([]db.Recipe, error) {
 	return r0, r1
 }
 
-// MockQuerier_ListRecipes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipes'
-type MockQuerier_ListRecipes_Call struct {
+// MockQuerier_RepointRecipeShares_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepointRecipeShares'
+type MockQuerier_RepointRecipeShares_Call struct {
 	*mock.Call
 }
 
-// ListRecipes is a helper method to define mock.On call
+// RepointRecipeShares is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockQuerier_Expecter) ListRecipes(ctx interface{}) *MockQuerier_ListRecipes_Call {
-	return &MockQuerier_ListRecipes_Call{Call: _e.mock.On("ListRecipes", ctx)}
+//   - arg db.RepointRecipeSharesParams
+func (_e *MockQuerier_Expecter) RepointRecipeShares(ctx interface{}, arg interface{}) *MockQuerier_RepointRecipeShares_Call {
+	return &MockQuerier_RepointRecipeShares_Call{Call: _e.mock.On("RepointRecipeShares", ctx, arg)}
 }
 
-func (_c *MockQuerier_ListRecipes_Call) Run(run func(ctx context.Context)) *MockQuerier_ListRecipes_Call {
+func (_c *MockQuerier_RepointRecipeShares_Call) Run(run func(ctx context.Context, arg db.RepointRecipeSharesParams)) *MockQuerier_RepointRecipeShares_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(db.RepointRecipeSharesParams))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_ListRecipes_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListRecipes_Call {
+func (_c *MockQuerier_RepointRecipeShares_Call) Return(_a0 int64, _a1 error) *MockQuerier_RepointRecipeShares_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockQuerier_ListRecipes_Call) RunAndReturn(run func(context.Context) ([]db.Recipe, error)) *MockQuerier_ListRecipes_Call {
+func (_c *MockQuerier_RepointRecipeShares_Call) RunAndReturn(run func(context.Context, db.RepointRecipeSharesParams) (int64, error)) *MockQuerier_RepointRecipeShares_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListRecipesByCookTime provides a mock function with given fields: ctx, cookMinutes
-func (_m *MockQuerier) ListRecipesByCookTime(ctx context.Context, cookMinutes sql.NullInt32) ([]db.Recipe, error) {
-	ret := _m.Called(ctx, cookMinutes)
+// RestoreRecipe provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) RestoreRecipe(ctx context.Context, arg db.RestoreRecipeParams) (db.Recipe, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListRecipesByCookTime")
+		panic("no return value specified for RestoreRecipe")
 	}
 
-	var r0 []db.Recipe
+	var r0 db.Recipe
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, sql.NullInt32) ([]db.Recipe, error)); ok {
-		return rf(ctx, cookMinutes)
+	if rf, ok := ret.Get(0).(func(context.Context, db.RestoreRecipeParams) (db.Recipe, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, sql.NullInt32) []db.Recipe); ok {
-		r0 = rf(ctx, cookMinutes)
+	if rf, ok := ret.Get(0).(func(context.Context, db.RestoreRecipeParams) db.Recipe); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.Recipe)
-		}
+		r0 = ret.Get(0).(db.Recipe)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, sql.NullInt32) error); ok {
-		r1 = rf(ctx, cookMinutes)
+	if rf, ok := ret.Get(1).(func(context.Context, db.RestoreRecipeParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -654,58 +2934,103 @@ func (_m *MockQuerier) ListRecipesByCookTime(ctx context.Context, cookMinutes sq
 	return r0, r1
 }
 
-// MockQuerier_ListRecipesByCookTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipesByCookTime'
-type MockQuerier_ListRecipesByCookTime_Call struct {
+// MockQuerier_RestoreRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreRecipe'
+type MockQuerier_RestoreRecipe_Call struct {
 	*mock.Call
 }
 
-// ListRecipesByCookTime is a helper method to define mock.On call
+// RestoreRecipe is a helper method to define mock.On call
 //   - ctx context.Context
-//   - cookMinutes sql.NullInt32
-func (_e *MockQuerier_Expecter) ListRecipesByCookTime(ctx interface{}, cookMinutes interface{}) *MockQuerier_ListRecipesByCookTime_Call {
-	return &MockQuerier_ListRecipesByCookTime_Call{Call: _e.mock.On("ListRecipesByCookTime", ctx, cookMinutes)}
+//   - arg db.RestoreRecipeParams
+func (_e *MockQuerier_Expecter) RestoreRecipe(ctx interface{}, arg interface{}) *MockQuerier_RestoreRecipe_Call {
+	return &MockQuerier_RestoreRecipe_Call{Call: _e.mock.On("RestoreRecipe", ctx, arg)}
 }
 
-func (_c *MockQuerier_ListRecipesByCookTime_Call) Run(run func(ctx context.Context, cookMinutes sql.NullInt32)) *MockQuerier_ListRecipesByCookTime_Call {
+func (_c *MockQuerier_RestoreRecipe_Call) Run(run func(ctx context.Context, arg db.RestoreRecipeParams)) *MockQuerier_RestoreRecipe_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(sql.NullInt32))
+		run(args[0].(context.Context), args[1].(db.RestoreRecipeParams))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_ListRecipesByCookTime_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListRecipesByCookTime_Call {
+func (_c *MockQuerier_RestoreRecipe_Call) Return(_a0 db.Recipe, _a1 error) *MockQuerier_RestoreRecipe_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockQuerier_ListRecipesByCookTime_Call) RunAndReturn(run func(context.Context, sql.NullInt32) ([]db.Recipe, error)) *MockQuerier_ListRecipesByCookTime_Call {
+func (_c *MockQuerier_RestoreRecipe_Call) RunAndReturn(run func(context.Context, db.RestoreRecipeParams) (db.Recipe, error)) *MockQuerier_RestoreRecipe_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListRecipesByTag provides a mock function with given fields: ctx, tags
-func (_m *MockQuerier) ListRecipesByTag(ctx context.Context, tags []string) ([]db.Recipe, error) {
-	ret := _m.Called(ctx, tags)
+// ShiftStepsFrom provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) ShiftStepsFrom(ctx context.Context, arg db.ShiftStepsFromParams) error {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListRecipesByTag")
+		panic("no return value specified for ShiftStepsFrom")
 	}
 
-	var r0 []db.Recipe
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.ShiftStepsFromParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockQuerier_ShiftStepsFrom_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ShiftStepsFrom'
+type MockQuerier_ShiftStepsFrom_Call struct {
+	*mock.Call
+}
+
+// ShiftStepsFrom is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.ShiftStepsFromParams
+func (_e *MockQuerier_Expecter) ShiftStepsFrom(ctx interface{}, arg interface{}) *MockQuerier_ShiftStepsFrom_Call {
+	return &MockQuerier_ShiftStepsFrom_Call{Call: _e.mock.On("ShiftStepsFrom", ctx, arg)}
+}
+
+func (_c *MockQuerier_ShiftStepsFrom_Call) Run(run func(ctx context.Context, arg db.ShiftStepsFromParams)) *MockQuerier_ShiftStepsFrom_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.ShiftStepsFromParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_ShiftStepsFrom_Call) Return(_a0 error) *MockQuerier_ShiftStepsFrom_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockQuerier_ShiftStepsFrom_Call) RunAndReturn(run func(context.Context, db.ShiftStepsFromParams) error) *MockQuerier_ShiftStepsFrom_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SoftDeleteRecipe provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) SoftDeleteRecipe(ctx context.Context, arg db.SoftDeleteRecipeParams) (db.Recipe, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDeleteRecipe")
+	}
+
+	var r0 db.Recipe
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]db.Recipe, error)); ok {
-		return rf(ctx, tags)
+	if rf, ok := ret.Get(0).(func(context.Context, db.SoftDeleteRecipeParams) (db.Recipe, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string) []db.Recipe); ok {
-		r0 = rf(ctx, tags)
+	if rf, ok := ret.Get(0).(func(context.Context, db.SoftDeleteRecipeParams) db.Recipe); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.Recipe)
-		}
+		r0 = ret.Get(0).(db.Recipe)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
-		r1 = rf(ctx, tags)
+	if rf, ok := ret.Get(1).(func(context.Context, db.SoftDeleteRecipeParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -713,58 +3038,56 @@ func (_m *MockQuerier) ListRecipesByTag(ctx context.Context, tags []string) ([]d
 	return r0, r1
 }
 
-// MockQuerier_ListRecipesByTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipesByTag'
-type MockQuerier_ListRecipesByTag_Call struct {
+// MockQuerier_SoftDeleteRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDeleteRecipe'
+type MockQuerier_SoftDeleteRecipe_Call struct {
 	*mock.Call
 }
 
-// ListRecipesByTag is a helper method to define mock.On call
+// SoftDeleteRecipe is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tags []string
-func (_e *MockQuerier_Expecter) ListRecipesByTag(ctx interface{}, tags interface{}) *MockQuerier_ListRecipesByTag_Call {
-	return &MockQuerier_ListRecipesByTag_Call{Call: _e.mock.On("ListRecipesByTag", ctx, tags)}
+//   - arg db.SoftDeleteRecipeParams
+func (_e *MockQuerier_Expecter) SoftDeleteRecipe(ctx interface{}, arg interface{}) *MockQuerier_SoftDeleteRecipe_Call {
+	return &MockQuerier_SoftDeleteRecipe_Call{Call: _e.mock.On("SoftDeleteRecipe", ctx, arg)}
 }
 
-func (_c *MockQuerier_ListRecipesByTag_Call) Run(run func(ctx context.Context, tags []string)) *MockQuerier_ListRecipesByTag_Call {
+func (_c *MockQuerier_SoftDeleteRecipe_Call) Run(run func(ctx context.Context, arg db.SoftDeleteRecipeParams)) *MockQuerier_SoftDeleteRecipe_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]string))
+		run(args[0].(context.Context), args[1].(db.SoftDeleteRecipeParams))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_ListRecipesByTag_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListRecipesByTag_Call {
+func (_c *MockQuerier_SoftDeleteRecipe_Call) Return(_a0 db.Recipe, _a1 error) *MockQuerier_SoftDeleteRecipe_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockQuerier_ListRecipesByTag_Call) RunAndReturn(run func(context.Context, []string) ([]db.Recipe, error)) *MockQuerier_ListRecipesByTag_Call {
+func (_c *MockQuerier_SoftDeleteRecipe_Call) RunAndReturn(run func(context.Context, db.SoftDeleteRecipeParams) (db.Recipe, error)) *MockQuerier_SoftDeleteRecipe_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListRecipesByTitle provides a mock function with given fields: ctx, dollar_1
-func (_m *MockQuerier) ListRecipesByTitle(ctx context.Context, dollar_1 sql.NullString) ([]db.Recipe, error) {
-	ret := _m.Called(ctx, dollar_1)
+// TouchRecipe provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) TouchRecipe(ctx context.Context, arg db.TouchRecipeParams) (db.Recipe, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListRecipesByTitle")
+		panic("no return value specified for TouchRecipe")
 	}
 
-	var r0 []db.Recipe
+	var r0 db.Recipe
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, sql.NullString) ([]db.Recipe, error)); ok {
-		return rf(ctx, dollar_1)
+	if rf, ok := ret.Get(0).(func(context.Context, db.TouchRecipeParams) (db.Recipe, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, sql.NullString) []db.Recipe); ok {
-		r0 = rf(ctx, dollar_1)
+	if rf, ok := ret.Get(0).(func(context.Context, db.TouchRecipeParams) db.Recipe); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.Recipe)
-		}
+		r0 = ret.Get(0).(db.Recipe)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, sql.NullString) error); ok {
-		r1 = rf(ctx, dollar_1)
+	if rf, ok := ret.Get(1).(func(context.Context, db.TouchRecipeParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -772,58 +3095,56 @@ func (_m *MockQuerier) ListRecipesByTitle(ctx context.Context, dollar_1 sql.Null
 	return r0, r1
 }
 
-// MockQuerier_ListRecipesByTitle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRecipesByTitle'
-type MockQuerier_ListRecipesByTitle_Call struct {
+// MockQuerier_TouchRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchRecipe'
+type MockQuerier_TouchRecipe_Call struct {
 	*mock.Call
 }
 
-// ListRecipesByTitle is a helper method to define mock.On call
+// TouchRecipe is a helper method to define mock.On call
 //   - ctx context.Context
-//   - dollar_1 sql.NullString
-func (_e *MockQuerier_Expecter) ListRecipesByTitle(ctx interface{}, dollar_1 interface{}) *MockQuerier_ListRecipesByTitle_Call {
-	return &MockQuerier_ListRecipesByTitle_Call{Call: _e.mock.On("ListRecipesByTitle", ctx, dollar_1)}
+//   - arg db.TouchRecipeParams
+func (_e *MockQuerier_Expecter) TouchRecipe(ctx interface{}, arg interface{}) *MockQuerier_TouchRecipe_Call {
+	return &MockQuerier_TouchRecipe_Call{Call: _e.mock.On("TouchRecipe", ctx, arg)}
 }
 
-func (_c *MockQuerier_ListRecipesByTitle_Call) Run(run func(ctx context.Context, dollar_1 sql.NullString)) *MockQuerier_ListRecipesByTitle_Call {
+func (_c *MockQuerier_TouchRecipe_Call) Run(run func(ctx context.Context, arg db.TouchRecipeParams)) *MockQuerier_TouchRecipe_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(sql.NullString))
+		run(args[0].(context.Context), args[1].(db.TouchRecipeParams))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_ListRecipesByTitle_Call) Return(_a0 []db.Recipe, _a1 error) *MockQuerier_ListRecipesByTitle_Call {
+func (_c *MockQuerier_TouchRecipe_Call) Return(_a0 db.Recipe, _a1 error) *MockQuerier_TouchRecipe_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockQuerier_ListRecipesByTitle_Call) RunAndReturn(run func(context.Context, sql.NullString) ([]db.Recipe, error)) *MockQuerier_ListRecipesByTitle_Call {
+func (_c *MockQuerier_TouchRecipe_Call) RunAndReturn(run func(context.Context, db.TouchRecipeParams) (db.Recipe, error)) *MockQuerier_TouchRecipe_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListStepsByRecipe provides a mock function with given fields: ctx, recipeID
-func (_m *MockQuerier) ListStepsByRecipe(ctx context.Context, recipeID uuid.UUID) ([]db.RecipeStep, error) {
-	ret := _m.Called(ctx, recipeID)
+// TryLockOutboxRelay provides a mock function with given fields: ctx
+func (_m *MockQuerier) TryLockOutboxRelay(ctx context.Context) (bool, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListStepsByRecipe")
+		panic("no return value specified for TryLockOutboxRelay")
 	}
 
-	var r0 []db.RecipeStep
+	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]db.RecipeStep, error)); ok {
-		return rf(ctx, recipeID)
+	if rf, ok := ret.Get(0).(func(context.Context) (bool, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []db.RecipeStep); ok {
-		r0 = rf(ctx, recipeID)
+	if rf, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = rf(ctx)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.RecipeStep)
-		}
+		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, recipeID)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -831,31 +3152,30 @@ func (_m *MockQuerier) ListStepsByRecipe(ctx context.Context, recipeID uuid.UUID
 	return r0, r1
 }
 
-// MockQuerier_ListStepsByRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListStepsByRecipe'
-type MockQuerier_ListStepsByRecipe_Call struct {
+// MockQuerier_TryLockOutboxRelay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TryLockOutboxRelay'
+type MockQuerier_TryLockOutboxRelay_Call struct {
 	*mock.Call
 }
 
-// ListStepsByRecipe is a helper method to define mock.On call
+// TryLockOutboxRelay is a helper method to define mock.On call
 //   - ctx context.Context
-//   - recipeID uuid.UUID
-func (_e *MockQuerier_Expecter) ListStepsByRecipe(ctx interface{}, recipeID interface{}) *MockQuerier_ListStepsByRecipe_Call {
-	return &MockQuerier_ListStepsByRecipe_Call{Call: _e.mock.On("ListStepsByRecipe", ctx, recipeID)}
+func (_e *MockQuerier_Expecter) TryLockOutboxRelay(ctx interface{}) *MockQuerier_TryLockOutboxRelay_Call {
+	return &MockQuerier_TryLockOutboxRelay_Call{Call: _e.mock.On("TryLockOutboxRelay", ctx)}
 }
 
-func (_c *MockQuerier_ListStepsByRecipe_Call) Run(run func(ctx context.Context, recipeID uuid.UUID)) *MockQuerier_ListStepsByRecipe_Call {
+func (_c *MockQuerier_TryLockOutboxRelay_Call) Run(run func(ctx context.Context)) *MockQuerier_TryLockOutboxRelay_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *MockQuerier_ListStepsByRecipe_Call) Return(_a0 []db.RecipeStep, _a1 error) *MockQuerier_ListStepsByRecipe_Call {
+func (_c *MockQuerier_TryLockOutboxRelay_Call) Return(_a0 bool, _a1 error) *MockQuerier_TryLockOutboxRelay_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockQuerier_ListStepsByRecipe_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]db.RecipeStep, error)) *MockQuerier_ListStepsByRecipe_Call {
+func (_c *MockQuerier_TryLockOutboxRelay_Call) RunAndReturn(run func(context.Context) (bool, error)) *MockQuerier_TryLockOutboxRelay_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -917,6 +3237,63 @@ func (_c *MockQuerier_UpdateIngestionJobStaged_Call) RunAndReturn(run func(conte
 	return _c
 }
 
+// UpdateIngestionJobStagedData provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) UpdateIngestionJobStagedData(ctx context.Context, arg db.UpdateIngestionJobStagedDataParams) (db.IngestionJob, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateIngestionJobStagedData")
+	}
+
+	var r0 db.IngestionJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateIngestionJobStagedDataParams) (db.IngestionJob, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateIngestionJobStagedDataParams) db.IngestionJob); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IngestionJob)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateIngestionJobStagedDataParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_UpdateIngestionJobStagedData_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateIngestionJobStagedData'
+type MockQuerier_UpdateIngestionJobStagedData_Call struct {
+	*mock.Call
+}
+
+// UpdateIngestionJobStagedData is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.UpdateIngestionJobStagedDataParams
+func (_e *MockQuerier_Expecter) UpdateIngestionJobStagedData(ctx interface{}, arg interface{}) *MockQuerier_UpdateIngestionJobStagedData_Call {
+	return &MockQuerier_UpdateIngestionJobStagedData_Call{Call: _e.mock.On("UpdateIngestionJobStagedData", ctx, arg)}
+}
+
+func (_c *MockQuerier_UpdateIngestionJobStagedData_Call) Run(run func(ctx context.Context, arg db.UpdateIngestionJobStagedDataParams)) *MockQuerier_UpdateIngestionJobStagedData_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.UpdateIngestionJobStagedDataParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_UpdateIngestionJobStagedData_Call) Return(_a0 db.IngestionJob, _a1 error) *MockQuerier_UpdateIngestionJobStagedData_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_UpdateIngestionJobStagedData_Call) RunAndReturn(run func(context.Context, db.UpdateIngestionJobStagedDataParams) (db.IngestionJob, error)) *MockQuerier_UpdateIngestionJobStagedData_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateIngestionJobStatus provides a mock function with given fields: ctx, arg
 func (_m *MockQuerier) UpdateIngestionJobStatus(ctx context.Context, arg db.UpdateIngestionJobStatusParams) (db.IngestionJob, error) {
 	ret := _m.Called(ctx, arg)
@@ -1031,6 +3408,120 @@ func (_c *MockQuerier_UpdateRecipe_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// UpdateRecipeIngredient provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) UpdateRecipeIngredient(ctx context.Context, arg db.UpdateRecipeIngredientParams) (db.RecipeIngredient, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRecipeIngredient")
+	}
+
+	var r0 db.RecipeIngredient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateRecipeIngredientParams) (db.RecipeIngredient, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateRecipeIngredientParams) db.RecipeIngredient); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.RecipeIngredient)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateRecipeIngredientParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_UpdateRecipeIngredient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRecipeIngredient'
+type MockQuerier_UpdateRecipeIngredient_Call struct {
+	*mock.Call
+}
+
+// UpdateRecipeIngredient is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.UpdateRecipeIngredientParams
+func (_e *MockQuerier_Expecter) UpdateRecipeIngredient(ctx interface{}, arg interface{}) *MockQuerier_UpdateRecipeIngredient_Call {
+	return &MockQuerier_UpdateRecipeIngredient_Call{Call: _e.mock.On("UpdateRecipeIngredient", ctx, arg)}
+}
+
+func (_c *MockQuerier_UpdateRecipeIngredient_Call) Run(run func(ctx context.Context, arg db.UpdateRecipeIngredientParams)) *MockQuerier_UpdateRecipeIngredient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.UpdateRecipeIngredientParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_UpdateRecipeIngredient_Call) Return(_a0 db.RecipeIngredient, _a1 error) *MockQuerier_UpdateRecipeIngredient_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_UpdateRecipeIngredient_Call) RunAndReturn(run func(context.Context, db.UpdateRecipeIngredientParams) (db.RecipeIngredient, error)) *MockQuerier_UpdateRecipeIngredient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateStepInstruction provides a mock function with given fields: ctx, arg
+func (_m *MockQuerier) UpdateStepInstruction(ctx context.Context, arg db.UpdateStepInstructionParams) (db.RecipeStep, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStepInstruction")
+	}
+
+	var r0 db.RecipeStep
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateStepInstructionParams) (db.RecipeStep, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateStepInstructionParams) db.RecipeStep); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.RecipeStep)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateStepInstructionParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuerier_UpdateStepInstruction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateStepInstruction'
+type MockQuerier_UpdateStepInstruction_Call struct {
+	*mock.Call
+}
+
+// UpdateStepInstruction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg db.UpdateStepInstructionParams
+func (_e *MockQuerier_Expecter) UpdateStepInstruction(ctx interface{}, arg interface{}) *MockQuerier_UpdateStepInstruction_Call {
+	return &MockQuerier_UpdateStepInstruction_Call{Call: _e.mock.On("UpdateStepInstruction", ctx, arg)}
+}
+
+func (_c *MockQuerier_UpdateStepInstruction_Call) Run(run func(ctx context.Context, arg db.UpdateStepInstructionParams)) *MockQuerier_UpdateStepInstruction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(db.UpdateStepInstructionParams))
+	})
+	return _c
+}
+
+func (_c *MockQuerier_UpdateStepInstruction_Call) Return(_a0 db.RecipeStep, _a1 error) *MockQuerier_UpdateStepInstruction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuerier_UpdateStepInstruction_Call) RunAndReturn(run func(context.Context, db.UpdateStepInstructionParams) (db.RecipeStep, error)) *MockQuerier_UpdateStepInstruction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockQuerier creates a new instance of MockQuerier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockQuerier(t interface {
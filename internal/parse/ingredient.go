@@ -0,0 +1,238 @@
+// Package parse turns free-text recipe fragments into structured values.
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+var unicodeFractions = map[rune]string{
+	'½': "1/2", '⅓': "1/3", '⅔': "2/3", '¼': "1/4", '¾': "3/4",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5", '⅙': "1/6",
+	'⅚': "5/6", '⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+// units maps lower-cased spellings to a canonical unit. "T" and "t" are
+// matched case-sensitively in caseSensitiveUnits.
+var units = map[string]string{
+	"tsp": "tsp", "tsps": "tsp", "teaspoon": "tsp", "teaspoons": "tsp",
+	"tbsp": "tbsp", "tbsps": "tbsp", "tbs": "tbsp", "tablespoon": "tbsp", "tablespoons": "tbsp",
+	"cup": "cup", "cups": "cup", "c": "cup",
+	"fl oz": "fl oz", "fluid ounce": "fl oz", "fluid ounces": "fl oz",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"pt": "pint", "pint": "pint", "pints": "pint",
+	"qt": "quart", "quart": "quart", "quarts": "quart",
+	"gal": "gallon", "gallon": "gallon", "gallons": "gallon",
+	"mg": "mg", "milligram": "mg", "milligrams": "mg",
+	"g": "g", "gr": "g", "gram": "g", "grams": "g", "gramme": "g", "grammes": "g",
+	"kg": "kg", "kilo": "kg", "kilos": "kg", "kilogram": "kg", "kilograms": "kg",
+	"ml": "ml", "millilitre": "ml", "millilitres": "ml", "milliliter": "ml", "milliliters": "ml",
+	"cl": "cl", "centilitre": "cl", "centilitres": "cl", "centiliter": "cl", "centiliters": "cl",
+	"dl": "dl", "decilitre": "dl", "decilitres": "dl", "deciliter": "dl", "deciliters": "dl",
+	"l": "l", "litre": "l", "litres": "l", "liter": "l", "liters": "l",
+	"pinch": "pinch", "pinches": "pinch",
+	"dash": "dash", "dashes": "dash",
+	"clove": "clove", "cloves": "clove",
+	"can": "can", "cans": "can", "tin": "can", "tins": "can",
+	"jar": "jar", "jars": "jar",
+	"package": "package", "packages": "package", "pkg": "package", "packet": "package", "packets": "package",
+	"slice": "slice", "slices": "slice",
+	"stick": "stick", "sticks": "stick",
+	"sprig": "sprig", "sprigs": "sprig",
+	"bunch": "bunch", "bunches": "bunch",
+	"handful": "handful", "handfuls": "handful",
+	"piece": "piece", "pieces": "piece",
+}
+
+var caseSensitiveUnits = map[string]string{"T": "tbsp", "t": "tsp"}
+
+const numberPattern = `\d+\s+\d+/\d+|\d+/\d+|\d+(?:\.\d+)?`
+
+var (
+	quantityPrefix = regexp.MustCompile(
+		`^(` + numberPattern + `)(?:\s*-\s*|\s+to\s+)?(` + numberPattern + `)?`,
+	)
+	parenthetical = regexp.MustCompile(`\s*\(([^)]*)\)`)
+	trailingNotes = regexp.MustCompile(`(?i)[,\s]*\b(to taste|as needed|for garnish|for serving)\s*$`)
+	optionalWord  = regexp.MustCompile(`(?i)(?:^optional\s*:\s*|,\s*optional\s*$)`)
+	indefinite    = regexp.MustCompile(`(?i)^an?\s+`)
+)
+
+// Ingredient parses an ingredient line such as
+// "1 1/2 cups all-purpose flour, sifted (optional)" into a StagedIngredient.
+//
+// Ranges ("2-3", "2 to 3") use the lower bound as Quantity and keep the upper
+// bound in PreparationNotes. Lines without a quantity keep only a name. The
+// second result is false when no ingredient name remains.
+func Ingredient(line string) (service.StagedIngredient, bool) {
+	var ing service.StagedIngredient
+	var notes []string
+
+	s := normalize(line)
+
+	if optionalWord.MatchString(s) {
+		ing.IsOptional = true
+		s = optionalWord.ReplaceAllString(s, "")
+	}
+
+	for _, m := range parenthetical.FindAllStringSubmatch(s, -1) {
+		inner := strings.TrimSpace(m[1])
+		switch {
+		case strings.EqualFold(inner, "optional"):
+			ing.IsOptional = true
+		case inner != "":
+			notes = append(notes, inner)
+		}
+	}
+	s = strings.TrimSpace(parenthetical.ReplaceAllString(s, ""))
+
+	var trailing string
+	if m := trailingNotes.FindStringSubmatch(s); m != nil {
+		trailing = strings.ToLower(m[1])
+		s = strings.TrimSpace(s[:len(s)-len(m[0])])
+	}
+
+	s, ing.Quantity, ing.Unit, notes = splitMeasure(s, notes)
+
+	if name, rest, ok := strings.Cut(s, ","); ok {
+		s = name
+		if rest = strings.TrimSpace(rest); rest != "" {
+			notes = append([]string{rest}, notes...)
+		}
+	}
+	if trailing != "" {
+		notes = append(notes, trailing)
+	}
+
+	ing.Name = strings.TrimSpace(strings.Trim(s, ",;:- "))
+	ing.PreparationNotes = strings.Join(notes, "; ")
+	return ing, ing.Name != ""
+}
+
+// StartsWithQuantity reports whether line begins with a number, fraction or
+// unicode fraction, the usual sign of an ingredient line.
+func StartsWithQuantity(line string) bool {
+	return quantityPrefix.MatchString(normalize(line))
+}
+
+// Quantity parses a whole number, decimal, fraction ("3/4"), mixed number
+// ("1 1/2") or unicode fraction ("1½").
+func Quantity(s string) (float64, bool) {
+	s = normalize(s)
+	if whole, frac, ok := strings.Cut(s, " "); ok {
+		w, ok1 := parseNumber(whole)
+		f, ok2 := parseNumber(strings.TrimSpace(frac))
+		if !ok1 || !ok2 || !strings.Contains(frac, "/") {
+			return 0, false
+		}
+		return w + f, true
+	}
+	return parseNumber(s)
+}
+
+// splitMeasure removes a leading quantity (or range) and unit from s.
+func splitMeasure(s string, notes []string) (string, float64, string, []string) {
+	var qty float64
+	measured := false
+	m := quantityPrefix.FindStringSubmatchIndex(s)
+	switch {
+	case m != nil:
+		qty, measured = Quantity(s[m[2]:m[3]])
+		if m[4] >= 0 {
+			notes = append(notes, "up to "+s[m[4]:m[5]])
+		}
+		s = strings.TrimSpace(s[m[1]:])
+	case indefinite.MatchString(s):
+		// "a pinch of salt": only treat the article as 1 when a unit follows.
+		rest := indefinite.ReplaceAllString(s, "")
+		if _, n := leadingUnit(rest); n > 0 {
+			qty, measured = 1, true
+			s = rest
+		}
+	}
+	if !measured {
+		return s, 0, "", notes
+	}
+
+	unit, n := leadingUnit(s)
+	if n > 0 {
+		s = strings.TrimSpace(s[n:])
+	}
+	s = strings.TrimPrefix(s, "of ")
+	return s, qty, unit, notes
+}
+
+// leadingUnit returns the canonical unit at the start of s and the number of
+// bytes it spans, or 0 when s does not start with a unit.
+func leadingUnit(s string) (string, int) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return "", 0
+	}
+
+	if len(fields) > 1 {
+		two := strings.ToLower(strings.TrimRight(fields[0], ".") + " " + strings.TrimRight(fields[1], "."))
+		if unit, ok := units[two]; ok {
+			return unit, strings.Index(s, fields[1]) + len(fields[1])
+		}
+	}
+
+	word := strings.TrimRight(fields[0], ".")
+	if unit, ok := caseSensitiveUnits[word]; ok {
+		return unit, strings.Index(s, fields[0]) + len(fields[0])
+	}
+	if unit, ok := units[strings.ToLower(word)]; ok {
+		return unit, strings.Index(s, fields[0]) + len(fields[0])
+	}
+	return "", 0
+}
+
+// normalize expands unicode fractions, unifies dashes and separates units
+// written directly after a number ("200g" becomes "200 g").
+func normalize(s string) string {
+	var b strings.Builder
+	prevDigit := false
+	for _, r := range strings.TrimSpace(s) {
+		if frac, ok := unicodeFractions[r]; ok {
+			if prevDigit {
+				b.WriteByte(' ')
+			}
+			b.WriteString(frac)
+			prevDigit = true
+			continue
+		}
+		switch r {
+		case '⁄':
+			r = '/'
+		case '–', '—':
+			r = '-'
+		}
+		if prevDigit && isLetter(r) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+		prevDigit = r >= '0' && r <= '9'
+	}
+	return b.String()
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func parseNumber(s string) (float64, bool) {
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err1 := strconv.ParseFloat(num, 64)
+		d, err2 := strconv.ParseFloat(den, 64)
+		if err1 != nil || err2 != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
@@ -0,0 +1,180 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+type ing = service.StagedIngredient
+
+func TestIngredient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		line string
+		want ing
+	}{
+		// Plain quantities and units.
+		{"2 eggs", ing{Name: "eggs", Quantity: 2}},
+		{"1 cup sugar", ing{Name: "sugar", Quantity: 1, Unit: "cup"}},
+		{"3 cups milk", ing{Name: "milk", Quantity: 3, Unit: "cup"}},
+		{"0.5 tsp salt", ing{Name: "salt", Quantity: 0.5, Unit: "tsp"}},
+		{"1.25 lbs chicken thighs", ing{Name: "chicken thighs", Quantity: 1.25, Unit: "lb"}},
+		{"2 Tbsp. olive oil", ing{Name: "olive oil", Quantity: 2, Unit: "tbsp"}},
+		{"2 T butter", ing{Name: "butter", Quantity: 2, Unit: "tbsp"}},
+		{"1 t vanilla", ing{Name: "vanilla", Quantity: 1, Unit: "tsp"}},
+		{"4 tablespoons honey", ing{Name: "honey", Quantity: 4, Unit: "tbsp"}},
+		{"8 oz cream cheese", ing{Name: "cream cheese", Quantity: 8, Unit: "oz"}},
+		{"12 fl oz beer", ing{Name: "beer", Quantity: 12, Unit: "fl oz"}},
+		{"2 fl. oz. lime juice", ing{Name: "lime juice", Quantity: 2, Unit: "fl oz"}},
+		{"1 pint cream", ing{Name: "cream", Quantity: 1, Unit: "pint"}},
+		{"1 qt stock", ing{Name: "stock", Quantity: 1, Unit: "quart"}},
+		{"3 large eggs", ing{Name: "large eggs", Quantity: 3}},
+		{"1 onion", ing{Name: "onion", Quantity: 1}},
+		{"2 cloves garlic, minced", ing{Name: "garlic", Quantity: 2, Unit: "clove", PreparationNotes: "minced"}},
+		{"1 pinch of nutmeg", ing{Name: "nutmeg", Quantity: 1, Unit: "pinch"}},
+		{"2 sprigs thyme", ing{Name: "thyme", Quantity: 2, Unit: "sprig"}},
+		{"1 bunch cilantro, chopped", ing{
+			Name: "cilantro", Quantity: 1, Unit: "bunch", PreparationNotes: "chopped",
+		}},
+		{"1 stick butter, softened", ing{Name: "butter", Quantity: 1, Unit: "stick", PreparationNotes: "softened"}},
+
+		// Fractions and mixed numbers.
+		{"1/2 cup flour", ing{Name: "flour", Quantity: 0.5, Unit: "cup"}},
+		{"3/4 tsp baking soda", ing{Name: "baking soda", Quantity: 0.75, Unit: "tsp"}},
+		{"1 1/2 cups water", ing{Name: "water", Quantity: 1.5, Unit: "cup"}},
+		{"2 1/4 tsp yeast", ing{Name: "yeast", Quantity: 2.25, Unit: "tsp"}},
+		{"1⁄2 cup rice", ing{Name: "rice", Quantity: 0.5, Unit: "cup"}},
+
+		// Unicode fractions.
+		{"½ cup butter", ing{Name: "butter", Quantity: 0.5, Unit: "cup"}},
+		{"¼ tsp pepper", ing{Name: "pepper", Quantity: 0.25, Unit: "tsp"}},
+		{"¾ cup oats", ing{Name: "oats", Quantity: 0.75, Unit: "cup"}},
+		{"1½ cups broth", ing{Name: "broth", Quantity: 1.5, Unit: "cup"}},
+		{"1 ½ cups broth", ing{Name: "broth", Quantity: 1.5, Unit: "cup"}},
+		{"2⅓ cups flour", ing{Name: "flour", Quantity: 2 + 1.0/3, Unit: "cup"}},
+		{"⅛ tsp cayenne", ing{Name: "cayenne", Quantity: 0.125, Unit: "tsp"}},
+
+		// Ranges.
+		{"2-3 cloves garlic", ing{Name: "garlic", Quantity: 2, Unit: "clove", PreparationNotes: "up to 3"}},
+		{"2 - 3 tbsp sugar", ing{Name: "sugar", Quantity: 2, Unit: "tbsp", PreparationNotes: "up to 3"}},
+		{"2–3 tbsp sugar", ing{Name: "sugar", Quantity: 2, Unit: "tbsp", PreparationNotes: "up to 3"}},
+		{"1 to 2 cups stock", ing{Name: "stock", Quantity: 1, Unit: "cup", PreparationNotes: "up to 2"}},
+		{"1/2-1 tsp chili flakes", ing{
+			Name: "chili flakes", Quantity: 0.5, Unit: "tsp", PreparationNotes: "up to 1",
+		}},
+		{"4-6 chicken thighs, skin on", ing{
+			Name: "chicken thighs", Quantity: 4, PreparationNotes: "skin on; up to 6",
+		}},
+
+		// Metric, including units written against the number.
+		{"200 g flour", ing{Name: "flour", Quantity: 200, Unit: "g"}},
+		{"200g flour", ing{Name: "flour", Quantity: 200, Unit: "g"}},
+		{"1.5kg potatoes", ing{Name: "potatoes", Quantity: 1.5, Unit: "kg"}},
+		{"250ml milk", ing{Name: "milk", Quantity: 250, Unit: "ml"}},
+		{"250 mL milk", ing{Name: "milk", Quantity: 250, Unit: "ml"}},
+		{"1 L water", ing{Name: "water", Quantity: 1, Unit: "l"}},
+		{"2 litres stock", ing{Name: "stock", Quantity: 2, Unit: "l"}},
+		{"5 cl gin", ing{Name: "gin", Quantity: 5, Unit: "cl"}},
+		{"2 dl cream", ing{Name: "cream", Quantity: 2, Unit: "dl"}},
+		{"500 grams beef mince", ing{Name: "beef mince", Quantity: 500, Unit: "g"}},
+		{"100mg saffron", ing{Name: "saffron", Quantity: 100, Unit: "mg"}},
+
+		// Parenthetical notes.
+		{"1 (14 oz) can tomatoes, drained", ing{
+			Name: "tomatoes", Quantity: 1, Unit: "can", PreparationNotes: "drained; 14 oz",
+		}},
+		{"2 cups flour (sifted)", ing{Name: "flour", Quantity: 2, Unit: "cup", PreparationNotes: "sifted"}},
+		{"1 cup (2 sticks) butter", ing{Name: "butter", Quantity: 1, Unit: "cup", PreparationNotes: "2 sticks"}},
+		{"3 carrots (about 300 g), diced", ing{
+			Name: "carrots", Quantity: 3, PreparationNotes: "diced; about 300 g",
+		}},
+
+		// Optional ingredients.
+		{"1 1/2 cups all-purpose flour, sifted (optional)", ing{
+			Name: "all-purpose flour", Quantity: 1.5, Unit: "cup", PreparationNotes: "sifted", IsOptional: true,
+		}},
+		{"1/4 cup walnuts (Optional)", ing{Name: "walnuts", Quantity: 0.25, Unit: "cup", IsOptional: true}},
+		{"fresh basil, optional", ing{Name: "fresh basil", IsOptional: true}},
+		{"Optional: 1 tsp chili oil", ing{Name: "chili oil", Quantity: 1, Unit: "tsp", IsOptional: true}},
+
+		// "To taste" and similar trailing phrases.
+		{"salt to taste", ing{Name: "salt", PreparationNotes: "to taste"}},
+		{"Salt and pepper, to taste", ing{Name: "Salt and pepper", PreparationNotes: "to taste"}},
+		{"black pepper, freshly ground, to taste", ing{
+			Name: "black pepper", PreparationNotes: "freshly ground; to taste",
+		}},
+		{"olive oil, as needed", ing{Name: "olive oil", PreparationNotes: "as needed"}},
+		{"parsley, for garnish", ing{Name: "parsley", PreparationNotes: "for garnish"}},
+		{"1 lemon, for serving", ing{Name: "lemon", Quantity: 1, PreparationNotes: "for serving"}},
+
+		// Articles and no quantity.
+		{"a pinch of salt", ing{Name: "salt", Quantity: 1, Unit: "pinch"}},
+		{"A handful of spinach", ing{Name: "spinach", Quantity: 1, Unit: "handful"}},
+		{"an apple", ing{Name: "an apple"}},
+		{"olive oil", ing{Name: "olive oil"}},
+		{"Cup of tea", ing{Name: "Cup of tea"}},
+		{"Juice of 1 lemon", ing{Name: "Juice of 1 lemon"}},
+		{"  2   cups   rice  ", ing{Name: "rice", Quantity: 2, Unit: "cup"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			t.Parallel()
+			got, ok := Ingredient(tt.line)
+			require.True(t, ok)
+			assert.InDelta(t, tt.want.Quantity, got.Quantity, 1e-9)
+			got.Quantity = tt.want.Quantity
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIngredient_Empty(t *testing.T) {
+	t.Parallel()
+
+	for _, line := range []string{"", "   ", "2 cups", "(optional)", ", to taste"} {
+		_, ok := Ingredient(line)
+		assert.False(t, ok, "line %q", line)
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"3", 3, true},
+		{"2.5", 2.5, true},
+		{"3/4", 0.75, true},
+		{"1 1/2", 1.5, true},
+		{"1½", 1.5, true},
+		{"¼", 0.25, true},
+		{"1/0", 0, false},
+		{"1 2", 0, false},
+		{"abc", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Quantity(tt.in)
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.InDelta(t, tt.want, got, 1e-9, tt.in)
+	}
+}
+
+func TestStartsWithQuantity(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, StartsWithQuantity("2 eggs"))
+	assert.True(t, StartsWithQuantity("½ cup milk"))
+	assert.True(t, StartsWithQuantity("200g flour"))
+	assert.False(t, StartsWithQuantity("Boil the water."))
+	assert.False(t, StartsWithQuantity(""))
+}
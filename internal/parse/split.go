@@ -0,0 +1,23 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// recipeSeparator matches a line made only of three or more "-", "=", "*"
+// or "_" characters, or a form feed, as used between recipes in exports and
+// pasted cookbooks.
+var recipeSeparator = regexp.MustCompile(`(?m)^[ \t]*(?:[-=*_][ \t]*){3,}$|\f`)
+
+// SplitRecipes splits text holding several recipes into one string per
+// recipe. Empty parts are dropped; text without separators is returned whole.
+func SplitRecipes(text string) []string {
+	var recipes []string
+	for _, part := range recipeSeparator.Split(text, -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			recipes = append(recipes, part)
+		}
+	}
+	return recipes
+}
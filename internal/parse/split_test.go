@@ -0,0 +1,32 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitRecipes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"single", "Toast\n1 slice bread", []string{"Toast\n1 slice bread"}},
+		{"dashes", "Toast\n---\nTea", []string{"Toast", "Tea"}},
+		{"spaced stars", "Toast\n * * * \nTea\n=====\nJam", []string{"Toast", "Tea", "Jam"}},
+		{"form feed", "Toast\fTea", []string{"Toast", "Tea"}},
+		{"empty parts", "---\nToast\n---\n\n---\n", []string{"Toast"}},
+		{"hyphenated text is kept", "Toast - buttered\n1 slice", []string{"Toast - buttered\n1 slice"}},
+		{"blank", "  \n ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, SplitRecipes(tt.text))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	dialTimeout  = 5 * time.Second
+	maxRedirects = 10
+)
+
+// ErrForbiddenAddress is returned for URLs that resolve to, or redirect to,
+// an address that is not on the public internet.
+var ErrForbiddenAddress = errors.New("url does not point to a public address")
+
+// nonPublic are the special-purpose ranges that netip.Addr's predicates do
+// not already cover.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// IsPublic reports whether addr is a globally routable unicast address,
+// i.e. not loopback, private, link-local (which covers cloud metadata
+// endpoints), multicast or otherwise reserved.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkURL rejects u unless it is an absolute http or https URL whose host,
+// if it is localhost or a literal IP address, is allowed by allow. Host
+// names are only resolved when the page is fetched, where the dialer checks
+// every address again.
+func checkURL(u *url.URL, allow func(netip.Addr) bool) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		host = "127.0.0.1"
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && allow != nil && !allow(addr) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// newClient returns a client that only connects to addresses allowed by
+// allow. The check runs in the dialer's Control hook, after DNS resolution
+// and on every connection, so it also covers redirects and DNS rebinding.
+// Proxies are disabled since they would connect on the scraper's behalf.
+func newClient(allow func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+			}
+			if !allow(addr.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   defaultTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return checkURL(req.URL, allow)
+		},
+	}
+}
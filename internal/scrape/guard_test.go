@@ -0,0 +1,97 @@
+package scrape
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublic(t *testing.T) {
+	t.Parallel()
+
+	for addr, want := range map[string]bool{
+		"93.184.215.14":        true,
+		"127.0.0.1":            false,
+		"10.1.2.3":             false,
+		"169.254.169.254":      false,
+		"100.100.100.200":      false,
+		"0.0.0.0":              false,
+		"224.0.0.1":            false,
+		"::1":                  false,
+		"fd00::1":              false,
+		"fe80::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:93.184.215.14": true,
+		"64:ff9b::a9fe:a9fe":   false,
+		"2001:db8::1":          false,
+		"198.18.0.1":           false,
+		"2001:4860:4860::8888": true,
+		"8.8.8.8":              true,
+		"::":                   false,
+	} {
+		assert.Equal(t, want, IsPublic(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestScrapeRecipe_RejectsPrivateAddress(t *testing.T) {
+	t.Parallel()
+	srv := fixtureServer(t)
+
+	s := New(nil)
+	_, err := s.ScrapeRecipe(context.Background(), srv.URL+"/jsonld_recipe.html")
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+
+	localhost := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	_, err = s.ScrapeRecipe(context.Background(), localhost+"/jsonld_recipe.html")
+	require.ErrorIs(t, err, ErrForbiddenAddress, "names are checked once resolved")
+
+	for _, u := range []string{srv.URL, localhost, "http://169.254.169.254/latest/meta-data/", "http://[::1]/"} {
+		require.ErrorIs(t, s.CheckURL(u), ErrForbiddenAddress, u)
+	}
+	require.NoError(t, s.CheckURL("https://example.com/recipe"))
+	require.Error(t, s.CheckURL("ftp://example.com/recipe"))
+}
+
+func TestScrapeRecipe_RejectsRedirectToPrivateAddress(t *testing.T) {
+	t.Parallel()
+	pages := fixtureServer(t)
+	redirects := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := pages.URL + "/jsonld_recipe.html"
+		if r.URL.Path == "/metadata" {
+			target = "http://169.254.169.254/latest/meta-data/"
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+	}))
+	t.Cleanup(redirects.Close)
+
+	// Loopback stands in for the public internet here, so the test servers
+	// are reachable while every other private address stays forbidden.
+	s := &Scraper{client: newClient(netip.Addr.IsLoopback), allow: netip.Addr.IsLoopback}
+
+	page, err := s.ScrapeRecipe(context.Background(), redirects.URL+"/recipe")
+	require.NoError(t, err, "redirects between allowed addresses are followed")
+	require.NotNil(t, page.Recipe)
+
+	_, err = s.ScrapeRecipe(context.Background(), redirects.URL+"/metadata")
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+}
+
+func TestScrapeRecipe_PageSizeLimit(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		// Flushing first forces a chunked response without a Content-Length.
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte("<html><body>" + strings.Repeat("a", maxPageBytes) + "</body></html>"))
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := New(srv.Client()).ScrapeRecipe(context.Background(), srv.URL)
+	require.ErrorContains(t, err, "larger than")
+}
@@ -0,0 +1,98 @@
+package scrape
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+// fromMicrodata returns the first itemscope typed schema.org Recipe.
+func fromMicrodata(doc *html.Node) *service.StagedRecipe {
+	var found *service.StagedRecipe
+	walk(doc, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if hasAttr(n, "itemscope") && isRecipeType(itemTypes(n)) {
+			found = toStagedRecipe(microdataItem(n))
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// microdataItem collects the itemprop values of an itemscope element. Nested
+// items become maps; repeated properties become lists.
+func microdataItem(scope *html.Node) map[string]any {
+	props := map[string][]any{}
+	walk(scope, func(n *html.Node) bool {
+		names := strings.Fields(attr(n, "itemprop"))
+		if len(names) == 0 {
+			// An unnamed nested item is a separate entity, not ours.
+			return !hasAttr(n, "itemscope")
+		}
+
+		var value any
+		if hasAttr(n, "itemscope") {
+			value = microdataItem(n)
+		} else {
+			value = microdataValue(n)
+		}
+		for _, name := range names {
+			props[name] = append(props[name], value)
+		}
+		return false
+	})
+
+	item := map[string]any{"@type": itemTypes(scope)}
+	for name, values := range props {
+		if len(values) == 1 {
+			item[name] = values[0]
+		} else {
+			item[name] = values
+		}
+	}
+	return item
+}
+
+func microdataValue(n *html.Node) any {
+	switch n.Data {
+	case "meta":
+		return attr(n, "content")
+	case "time":
+		if v := attr(n, "datetime"); v != "" {
+			return v
+		}
+	case "data", "meter":
+		return attr(n, "value")
+	}
+	if v := attr(n, "content"); v != "" {
+		return v
+	}
+
+	// A list of steps marked up as one property: one string per item.
+	var items []any
+	walk(n, func(c *html.Node) bool {
+		if c.Data == "li" {
+			items = append(items, textContent(c))
+			return false
+		}
+		return true
+	})
+	if len(items) > 0 {
+		return items
+	}
+	return textContent(n)
+}
+
+// itemTypes returns the space-separated itemtype URLs of n.
+func itemTypes(n *html.Node) []any {
+	var types []any
+	for _, t := range strings.Fields(attr(n, "itemtype")) {
+		types = append(types, t)
+	}
+	return types
+}
@@ -0,0 +1,212 @@
+package scrape
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/parse"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+var (
+	isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:[\d.]+S)?)?$`)
+	firstNumber = regexp.MustCompile(`\d+`)
+	markup      = regexp.MustCompile(`<[^>]*>`)
+)
+
+// fromJSONLD returns the first schema.org Recipe found in the document's
+// application/ld+json scripts.
+func fromJSONLD(doc *html.Node) *service.StagedRecipe {
+	var found *service.StagedRecipe
+	walk(doc, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if n.Data != "script" || !strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") {
+			return true
+		}
+		if n.FirstChild == nil {
+			return false
+		}
+
+		var v any
+		if err := json.Unmarshal([]byte(n.FirstChild.Data), &v); err != nil {
+			return false
+		}
+		if obj := findRecipeObject(v); obj != nil {
+			found = toStagedRecipe(obj)
+		}
+		return false
+	})
+	return found
+}
+
+// findRecipeObject searches a decoded JSON-LD value, including arrays and
+// @graph containers, for an object typed Recipe.
+func findRecipeObject(v any) map[string]any {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			if obj := findRecipeObject(item); obj != nil {
+				return obj
+			}
+		}
+	case map[string]any:
+		if isRecipeType(v["@type"]) {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findRecipeObject(graph)
+		}
+	}
+	return nil
+}
+
+func isRecipeType(t any) bool {
+	for _, s := range stringList(t) {
+		if s == "Recipe" || strings.HasSuffix(s, "/Recipe") || strings.HasSuffix(s, ":Recipe") {
+			return true
+		}
+	}
+	return false
+}
+
+// toStagedRecipe maps schema.org Recipe properties onto a StagedRecipe. It
+// returns nil when the data lacks a name or has neither ingredients nor
+// instructions, so the caller can fall back to text extraction.
+func toStagedRecipe(obj map[string]any) *service.StagedRecipe {
+	recipe := &service.StagedRecipe{
+		Title:       firstString(obj["name"]),
+		Description: firstString(obj["description"]),
+		Servings:    yieldServings(obj["recipeYield"]),
+		PrepMinutes: durationMinutes(firstString(obj["prepTime"])),
+		CookMinutes: durationMinutes(firstString(obj["cookTime"])),
+		Ingredients: []service.StagedIngredient{},
+	}
+
+	seen := map[string]bool{}
+	for _, key := range []string{"recipeCategory", "recipeCuisine", "keywords"} {
+		for _, value := range stringList(obj[key]) {
+			for _, tag := range strings.Split(value, ",") {
+				tag = strings.ToLower(cleanText(tag))
+				if tag != "" && !seen[tag] {
+					seen[tag] = true
+					recipe.Tags = append(recipe.Tags, tag)
+				}
+			}
+		}
+	}
+
+	ingredients := obj["recipeIngredient"]
+	if ingredients == nil {
+		ingredients = obj["ingredients"]
+	}
+	for _, line := range stringList(ingredients) {
+		if ing, ok := parse.Ingredient(cleanText(line)); ok {
+			recipe.Ingredients = append(recipe.Ingredients, ing)
+		}
+	}
+
+	recipe.Steps = instructionSteps(obj["recipeInstructions"])
+
+	if recipe.Title == "" || (len(recipe.Ingredients) == 0 && len(recipe.Steps) == 0) {
+		return nil
+	}
+	return recipe
+}
+
+// instructionSteps flattens recipeInstructions, which may be a block of text,
+// a list of strings, HowToStep objects or HowToSection objects.
+func instructionSteps(v any) []string {
+	var steps []string
+	switch v := v.(type) {
+	case string:
+		for _, line := range strings.Split(markupToLines(v), "\n") {
+			if line = cleanText(line); line != "" {
+				steps = append(steps, line)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			steps = append(steps, instructionSteps(item)...)
+		}
+	case map[string]any:
+		if items, ok := v["itemListElement"]; ok {
+			return instructionSteps(items)
+		}
+		text := firstString(v["text"])
+		if text == "" {
+			text = firstString(v["name"])
+		}
+		if text != "" {
+			steps = append(steps, text)
+		}
+	}
+	return steps
+}
+
+// stringList normalises a string, number or list property to strings.
+func stringList(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []any:
+		var out []string
+		for _, item := range v {
+			out = append(out, stringList(item)...)
+		}
+		return out
+	}
+	return nil
+}
+
+func firstString(v any) string {
+	for _, s := range stringList(v) {
+		if s = cleanText(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// yieldServings reads a serving count from values like 4, "4", "4 servings"
+// or ["4", "4 servings"].
+func yieldServings(v any) int {
+	for _, s := range stringList(v) {
+		if m := firstNumber.FindString(s); m != "" {
+			n, _ := strconv.Atoi(m)
+			return n
+		}
+	}
+	return 0
+}
+
+// durationMinutes converts an ISO 8601 duration such as "PT1H30M" to minutes.
+func durationMinutes(s string) int {
+	m := isoDuration.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return 0
+	}
+	days, _ := strconv.Atoi(m[1])
+	hours, _ := strconv.Atoi(m[2])
+	minutes, _ := strconv.Atoi(m[3])
+	return days*24*60 + hours*60 + minutes
+}
+
+// markupToLines turns embedded HTML in a text property into plain lines.
+func markupToLines(s string) string {
+	r := strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n", "</li>", "\n")
+	return markup.ReplaceAllString(r.Replace(s), "")
+}
+
+// cleanText unescapes entities, strips stray tags and collapses whitespace.
+func cleanText(s string) string {
+	s = html.UnescapeString(markup.ReplaceAllString(s, " "))
+	return strings.Join(strings.Fields(s), " ")
+}
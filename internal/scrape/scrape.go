@@ -0,0 +1,146 @@
+// Package scrape fetches recipe web pages for URL ingest. Pages that embed a
+// schema.org Recipe, as JSON-LD or microdata, are mapped straight to a
+// StagedRecipe; for anything else the page's readable text is returned for
+// the ingestion pipeline to extract from.
+package scrape
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+const (
+	defaultTimeout = 15 * time.Second
+	maxPageBytes   = 5 << 20
+	userAgent      = "woodpantry-recipes/1.0 (+recipe import)"
+)
+
+// Scraper implements service.RecipeScraper over HTTP.
+type Scraper struct {
+	client *http.Client
+	allow  func(netip.Addr) bool
+}
+
+// New returns a Scraper using client. If client is nil, it uses one with a
+// 15s timeout that refuses to connect to, or follow redirects to, addresses
+// that are not public (see IsPublic), so submitted URLs cannot reach the
+// service's own network. A non-nil client is used as given.
+func New(client *http.Client) *Scraper {
+	if client == nil {
+		return &Scraper{client: newClient(IsPublic), allow: IsPublic}
+	}
+	return &Scraper{client: client}
+}
+
+// CheckURL implements service.URLChecker. It rejects URLs that are not
+// absolute http or https and, when s guards its addresses, ones naming
+// localhost or a non-public IP address, before anything is fetched.
+func (s *Scraper) CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	return checkURL(u, s.allow)
+}
+
+func (s *Scraper) ScrapeRecipe(ctx context.Context, url string) (*service.ScrapedPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch page: status %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, _ := mime.ParseMediaType(ct)
+		if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+			return nil, fmt.Errorf("unsupported content type %q", mediaType)
+		}
+	}
+
+	if resp.ContentLength > maxPageBytes {
+		return nil, fmt.Errorf("page is larger than %d bytes", maxPageBytes)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read page: %w", err)
+	}
+	if len(body) > maxPageBytes {
+		return nil, fmt.Errorf("page is larger than %d bytes", maxPageBytes)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parse page: %w", err)
+	}
+
+	return Page(doc)
+}
+
+// Page extracts a recipe from a parsed HTML document, preferring JSON-LD
+// over microdata and falling back to the page's text.
+func Page(doc *html.Node) (*service.ScrapedPage, error) {
+	if recipe := fromJSONLD(doc); recipe != nil {
+		return &service.ScrapedPage{Recipe: recipe}, nil
+	}
+	if recipe := fromMicrodata(doc); recipe != nil {
+		return &service.ScrapedPage{Recipe: recipe}, nil
+	}
+
+	text := readableText(doc)
+	if text == "" {
+		return nil, errors.New("page has no readable text")
+	}
+	return &service.ScrapedPage{Text: text}, nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls fn for every element below n in document order. Returning false
+// from fn skips that element's children.
+func walk(n *html.Node, fn func(*html.Node) bool) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && !fn(c) {
+			continue
+		}
+		walk(c, fn)
+	}
+}
@@ -0,0 +1,133 @@
+package scrape
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func fixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestScrapeRecipe_JSONLD(t *testing.T) {
+	t.Parallel()
+	srv := fixtureServer(t)
+
+	page, err := New(srv.Client()).ScrapeRecipe(context.Background(), srv.URL+"/jsonld_recipe.html")
+	require.NoError(t, err)
+	require.NotNil(t, page.Recipe)
+	assert.Empty(t, page.Text)
+
+	got := page.Recipe
+	assert.Equal(t, "Weeknight Chili", got.Title)
+	assert.Equal(t, "Smoky beef chili & beans in under an hour.", got.Description)
+	assert.Equal(t, 6, got.Servings)
+	assert.Equal(t, 15, got.PrepMinutes)
+	assert.Equal(t, 65, got.CookMinutes)
+	assert.Equal(t, []string{"dinner", "tex-mex", "chili", "beef"}, got.Tags)
+	assert.Equal(t, []service.StagedIngredient{
+		{Name: "ground beef", Quantity: 1, Unit: "lb"},
+		{Name: "kidney beans", Quantity: 1, Unit: "can", PreparationNotes: "drained; 15 oz"},
+		{Name: "chili powder", Quantity: 2, Unit: "tbsp"},
+		{Name: "salt", PreparationNotes: "to taste"},
+	}, got.Ingredients)
+	assert.Equal(t, []string{
+		"Brown the beef.",
+		"Add beans and chili powder.",
+		"Simmer for 45 minutes.",
+	}, got.Steps)
+}
+
+func TestScrapeRecipe_Microdata(t *testing.T) {
+	t.Parallel()
+	srv := fixtureServer(t)
+
+	page, err := New(srv.Client()).ScrapeRecipe(context.Background(), srv.URL+"/microdata_recipe.html")
+	require.NoError(t, err)
+	require.NotNil(t, page.Recipe)
+
+	got := page.Recipe
+	assert.Equal(t, "Lemon Bars", got.Title, "author name must not leak into the recipe")
+	assert.Equal(t, "Bright, tart and buttery.", got.Description)
+	assert.Equal(t, 16, got.Servings)
+	assert.Equal(t, 20, got.PrepMinutes)
+	assert.Equal(t, 45, got.CookMinutes)
+	assert.Equal(t, []string{"dessert"}, got.Tags)
+	assert.Equal(t, []service.StagedIngredient{
+		{Name: "butter", Quantity: 1, Unit: "cup", PreparationNotes: "softened"},
+		{Name: "all-purpose flour", Quantity: 2, Unit: "cup"},
+		{Name: "eggs", Quantity: 4},
+		{Name: "lemon juice", Quantity: 0.5, Unit: "cup"},
+	}, got.Ingredients)
+	assert.Equal(t, []string{
+		"Press the crust into the pan and bake.",
+		"Whisk the filling and pour it over.",
+		"Bake until set.",
+	}, got.Steps)
+}
+
+func TestScrapeRecipe_FallsBackToText(t *testing.T) {
+	t.Parallel()
+	srv := fixtureServer(t)
+
+	page, err := New(srv.Client()).ScrapeRecipe(context.Background(), srv.URL+"/plain_recipe.html")
+	require.NoError(t, err)
+	assert.Nil(t, page.Recipe)
+	assert.Equal(t, `Grandma's Pancakes
+These are the best pancakes.
+Ingredients
+200 g flour
+2 eggs
+Instructions
+Whisk everything.
+Fry in butter.`, page.Text)
+}
+
+func TestScrapeRecipe_HTTPErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := New(srv.Client()).ScrapeRecipe(context.Background(), srv.URL+"/missing")
+	require.ErrorContains(t, err, "status 404")
+
+	_, err = New(srv.Client()).ScrapeRecipe(context.Background(), srv.URL+"/json")
+	require.ErrorContains(t, err, "unsupported content type")
+}
+
+func TestDurationMinutes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]int{
+		"PT20M":      20,
+		"PT1H":       60,
+		"PT1H30M":    90,
+		"P0DT0H45M":  45,
+		"P1DT2H":     26 * 60,
+		"PT10M30S":   10,
+		"pt5m":       5,
+		"20 minutes": 0,
+		"":           0,
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, durationMinutes(in), in)
+	}
+}
@@ -0,0 +1,71 @@
+package scrape
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// skippedElements never contribute to a page's readable text.
+var skippedElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"nav": true, "header": true, "footer": true, "aside": true, "form": true,
+	"button": true, "iframe": true, "svg": true,
+}
+
+// blockElements start a new line in readable text.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "br": true, "dd": true,
+	"div": true, "dl": true, "dt": true, "figcaption": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "hr": true, "li": true,
+	"main": true, "ol": true, "p": true, "pre": true, "section": true,
+	"table": true, "td": true, "th": true, "tr": true, "ul": true,
+}
+
+// readableText renders the page's visible text with one line per block,
+// dropping navigation, scripts and other chrome.
+func readableText(doc *html.Node) string {
+	var b strings.Builder
+	writeText(&b, doc)
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// textContent returns n's text collapsed onto a single line.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	writeText(&b, n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func writeText(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if skippedElements[n.Data] {
+			return
+		}
+	case html.ErrorNode, html.DocumentNode, html.CommentNode, html.DoctypeNode, html.RawNode:
+	}
+
+	block := n.Type == html.ElementNode && blockElements[n.Data]
+	if block {
+		b.WriteByte('\n')
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.CommentNode {
+			writeText(b, c)
+		}
+	}
+	if block {
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// Duplicate match reasons.
+const (
+	DuplicateReasonSourceURL   = "source_url"
+	DuplicateReasonTitle       = "title"
+	DuplicateReasonIngredients = "ingredients"
+)
+
+const (
+	// DuplicateIngredientOverlap is the Jaccard overlap of ingredient sets
+	// at or above which two recipes are reported as likely duplicates.
+	DuplicateIngredientOverlap = 0.6
+
+	// minOverlapIngredients keeps tiny ingredient lists ("salt, pepper")
+	// from matching everything.
+	minOverlapIngredients = 3
+
+	maxDuplicateCandidates = 5
+)
+
+// RecipeFingerprint is the part of a recipe compared for duplicates.
+type RecipeFingerprint struct {
+	Title         string
+	SourceURL     string
+	IngredientIDs []uuid.UUID
+}
+
+// DuplicateCandidate is an existing recipe that looks like the same recipe.
+type DuplicateCandidate struct {
+	RecipeID          uuid.UUID `json:"recipe_id"`
+	Title             string    `json:"title"`
+	Reasons           []string  `json:"reasons"`
+	IngredientOverlap float64   `json:"ingredient_overlap,omitempty"`
+}
+
+// Fingerprint returns the duplicate-check fingerprint of a staged recipe.
+// Only ingredients the pipeline already resolved to an ID take part in the
+// overlap check; the rest are resolved on confirm.
+func (r *StagedRecipe) Fingerprint() RecipeFingerprint {
+	fp := RecipeFingerprint{Title: r.Title, SourceURL: r.SourceURL}
+	for _, ing := range r.Ingredients {
+		if id, err := uuid.Parse(ing.IngredientID); err == nil {
+			fp.IngredientIDs = append(fp.IngredientIDs, id)
+		}
+	}
+	return fp
+}
+
+// FindDuplicates returns the caller's existing recipes matching fp by
+// normalized title, source URL or ingredient overlap, strongest match first.
+func (s *Service) FindDuplicates(ctx context.Context, fp RecipeFingerprint) ([]DuplicateCandidate, error) {
+	household := tenant.FromContext(ctx)
+	byID := map[uuid.UUID]*DuplicateCandidate{}
+	add := func(id uuid.UUID, title, reason string) *DuplicateCandidate {
+		c, ok := byID[id]
+		if !ok {
+			c = &DuplicateCandidate{RecipeID: id, Title: title}
+			byID[id] = c
+		}
+		c.Reasons = append(c.Reasons, reason)
+		return c
+	}
+
+	if fp.SourceURL != "" {
+		rows, err := s.q.FindRecipesBySourceURL(ctx, db.FindRecipesBySourceURLParams{
+			SourceUrl:   fp.SourceURL,
+			HouseholdID: household,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("find recipes by source url: %w", err)
+		}
+		for _, row := range rows {
+			add(row.ID, row.Title, DuplicateReasonSourceURL)
+		}
+	}
+
+	if fp.Title != "" {
+		rows, err := s.q.FindRecipesByTitleKey(ctx, db.FindRecipesByTitleKeyParams{
+			Title:       fp.Title,
+			HouseholdID: household,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("find recipes by title: %w", err)
+		}
+		for _, row := range rows {
+			add(row.ID, row.Title, DuplicateReasonTitle)
+		}
+	}
+
+	ids := uniqueIDs(fp.IngredientIDs)
+	if len(ids) >= minOverlapIngredients {
+		rows, err := s.q.FindRecipesSharingIngredients(ctx, db.FindRecipesSharingIngredientsParams{
+			IngredientIds: ids,
+			HouseholdID:   household,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("find recipes sharing ingredients: %w", err)
+		}
+		for _, row := range rows {
+			overlap := jaccard(len(ids), int(row.Total), int(row.Shared))
+			if overlap < DuplicateIngredientOverlap {
+				continue
+			}
+			add(row.ID, row.Title, DuplicateReasonIngredients).IngredientOverlap = overlap
+		}
+	}
+
+	candidates := make([]DuplicateCandidate, 0, len(byID))
+	for _, c := range byID {
+		candidates = append(candidates, *c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if len(a.Reasons) != len(b.Reasons) {
+			return len(a.Reasons) > len(b.Reasons)
+		}
+		if a.IngredientOverlap != b.IngredientOverlap {
+			return a.IngredientOverlap > b.IngredientOverlap
+		}
+		return a.RecipeID.String() < b.RecipeID.String()
+	})
+	if len(candidates) > maxDuplicateCandidates {
+		candidates = candidates[:maxDuplicateCandidates]
+	}
+	return candidates, nil
+}
+
+// duplicatesJSON runs FindDuplicates for a staged recipe and encodes the
+// result for ingestion_jobs.duplicates. Detection is advisory, so failures
+// are logged and the job is staged without candidates.
+func (s *Service) duplicatesJSON(ctx context.Context, staged *StagedRecipe) *json.RawMessage {
+	candidates, err := s.FindDuplicates(ctx, staged.Fingerprint())
+	if err != nil {
+		slog.Default().WarnContext(ctx, "duplicate check failed", "title", staged.Title, "error", err)
+		return nil
+	}
+	data, err := json.Marshal(candidates)
+	if err != nil {
+		return nil
+	}
+	raw := json.RawMessage(data)
+	return &raw
+}
+
+func jaccard(a, b, shared int) float64 {
+	union := a + b - shared
+	if union <= 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestFindDuplicates_CombinesSignals(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	sameURL, sameTitle, similar, different := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	ingredients := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+
+	mockQ.EXPECT().FindRecipesBySourceURL(mock.Anything, db.FindRecipesBySourceURLParams{
+		SourceUrl: "https://example.com/chili",
+	}).Return([]db.FindRecipesBySourceURLRow{{ID: sameURL, Title: "Chili"}}, nil)
+	mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, db.FindRecipesByTitleKeyParams{Title: "Chili"}).
+		Return([]db.FindRecipesByTitleKeyRow{
+			{ID: sameURL, Title: "Chili"},
+			{ID: sameTitle, Title: "chili!"},
+		}, nil)
+	mockQ.EXPECT().FindRecipesSharingIngredients(mock.Anything, db.FindRecipesSharingIngredientsParams{
+		IngredientIds: ingredients,
+	}).Return([]db.FindRecipesSharingIngredientsRow{
+		{ID: similar, Title: "Beef Chili", Shared: 4, Total: 5},
+		{ID: different, Title: "Tacos", Shared: 2, Total: 6},
+	}, nil)
+
+	got, err := svc.FindDuplicates(context.Background(), service.RecipeFingerprint{
+		Title:         "Chili",
+		SourceURL:     "https://example.com/chili",
+		IngredientIDs: append(ingredients, ingredients[0]),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, sameURL, got[0].RecipeID)
+	assert.Equal(t, []string{service.DuplicateReasonSourceURL, service.DuplicateReasonTitle}, got[0].Reasons)
+	assert.Equal(t, similar, got[1].RecipeID)
+	assert.Equal(t, []string{service.DuplicateReasonIngredients}, got[1].Reasons)
+	assert.InDelta(t, 0.8, got[1].IngredientOverlap, 1e-9)
+	assert.Equal(t, sameTitle, got[2].RecipeID)
+}
+
+func TestFindDuplicates_SkipsOverlapForShortIngredientLists(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, db.FindRecipesByTitleKeyParams{Title: "Toast"}).Return(nil, nil)
+
+	got, err := svc.FindDuplicates(context.Background(), service.RecipeFingerprint{
+		Title:         "Toast",
+		IngredientIDs: []uuid.UUID{uuid.New(), uuid.New()},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -1,14 +1,22 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"math"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
 )
 
 func nullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: s != ""}
 }
 
+// actor is the authenticated caller recorded as created_by or updated_by.
+func actor(ctx context.Context) sql.NullString {
+	return nullString(auth.Subject(ctx))
+}
+
 func nullInt32(n int) sql.NullInt32 {
 	if n > math.MaxInt32 || n < math.MinInt32 {
 		return sql.NullInt32{Valid: false}
@@ -19,3 +27,10 @@ func nullInt32(n int) sql.NullInt32 {
 func nullFloat64(f float64) sql.NullFloat64 {
 	return sql.NullFloat64{Float64: f, Valid: f != 0}
 }
+
+func toInt32(n int) (int32, bool) {
+	if n > math.MaxInt32 || n < math.MinInt32 {
+		return 0, false
+	}
+	return int32(n), true
+}
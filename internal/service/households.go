@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// Recipe visibility. Private recipes are seen only by their household,
+// shared ones also by the households they are shared with, and public ones
+// by every household. Only the owning household can change a recipe.
+const (
+	VisibilityPrivate = "private"
+	VisibilityShared  = "shared"
+	VisibilityPublic  = "public"
+)
+
+// ErrInvalidVisibility is returned for an unknown visibility value.
+var ErrInvalidVisibility = errors.New("visibility must be private, shared or public")
+
+// ValidateVisibility accepts the visibility constants and "" (keep the
+// current value, or private for new recipes).
+func ValidateVisibility(v string) error {
+	switch v {
+	case "", VisibilityPrivate, VisibilityShared, VisibilityPublic:
+		return nil
+	}
+	return ErrInvalidVisibility
+}
+
+// ShareRecipe lets household read the caller's recipe once its visibility is
+// shared. Sharing is idempotent.
+func (s *Service) ShareRecipe(ctx context.Context, recipeID, household uuid.UUID) error {
+	return s.WithTx(ctx, func(q db.Querier) error {
+		if _, err := lockOwnRecipe(ctx, q, recipeID); err != nil {
+			return err
+		}
+		if err := q.AddRecipeShare(ctx, db.AddRecipeShareParams{
+			RecipeID:    recipeID,
+			HouseholdID: household,
+		}); err != nil {
+			return fmt.Errorf("add recipe share: %w", err)
+		}
+		return nil
+	})
+}
+
+// UnshareRecipe revokes household's access to the caller's recipe. It
+// returns sql.ErrNoRows if the recipe is unknown or was not shared with it.
+func (s *Service) UnshareRecipe(ctx context.Context, recipeID, household uuid.UUID) error {
+	return s.WithTx(ctx, func(q db.Querier) error {
+		if _, err := lockOwnRecipe(ctx, q, recipeID); err != nil {
+			return err
+		}
+		removed, err := q.RemoveRecipeShare(ctx, db.RemoveRecipeShareParams{
+			RecipeID:    recipeID,
+			HouseholdID: household,
+		})
+		if err != nil {
+			return fmt.Errorf("remove recipe share: %w", err)
+		}
+		if removed == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// ListShares returns the households the caller's recipe is shared with.
+func (s *Service) ListShares(ctx context.Context, recipeID uuid.UUID) ([]db.RecipeShare, error) {
+	var shares []db.RecipeShare
+	err := s.WithTx(ctx, func(q db.Querier) error {
+		if _, err := lockOwnRecipe(ctx, q, recipeID); err != nil {
+			return err
+		}
+		var err error
+		shares, err = q.ListRecipeShares(ctx, recipeID)
+		if err != nil {
+			return fmt.Errorf("list recipe shares: %w", err)
+		}
+		return nil
+	})
+	if shares == nil {
+		shares = []db.RecipeShare{}
+	}
+	return shares, err
+}
+
+// lockOwnRecipe locks a recipe owned by the caller's household, returning
+// sql.ErrNoRows for recipes of other households.
+func lockOwnRecipe(ctx context.Context, q db.Querier, id uuid.UUID) (db.Recipe, error) {
+	return q.LockRecipe(ctx, db.LockRecipeParams{ID: id, HouseholdID: tenant.FromContext(ctx)})
+}
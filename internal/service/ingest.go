@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,8 +13,14 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
 )
 
+// ErrJobNotStaged is returned when confirming a job that has no staged recipe.
+var ErrJobNotStaged = errors.New("job is not in staged status")
+
 // StagedIngredient is an ingredient as extracted by LLM before resolve.
 type StagedIngredient struct {
 	IngredientID     string  `json:"ingredient_id,omitempty"`
@@ -47,10 +54,16 @@ type resolveResponse struct {
 // DictionaryResolver implements IngredientResolver using the Dictionary HTTP API.
 type DictionaryResolver struct {
 	baseURL string
+	client  *http.Client
 }
 
-func NewDictionaryResolver(baseURL string) *DictionaryResolver {
-	return &DictionaryResolver{baseURL: baseURL}
+// NewDictionaryResolver resolves against the Dictionary at baseURL. A nil
+// client means http.DefaultClient.
+func NewDictionaryResolver(baseURL string, client *http.Client) *DictionaryResolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &DictionaryResolver{baseURL: baseURL, client: client}
 }
 
 func (d *DictionaryResolver) ResolveIngredient(ctx context.Context, name string) (uuid.UUID, error) {
@@ -64,7 +77,7 @@ func (d *DictionaryResolver) ResolveIngredient(ctx context.Context, name string)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := d.client.Do(req)
 	if err != nil {
 		return uuid.UUID{}, fmt.Errorf("resolve request: %w", err)
 	}
@@ -86,8 +99,90 @@ func (d *DictionaryResolver) ResolveIngredient(ctx context.Context, name string)
 	return id, nil
 }
 
-// CommitStagedRecipe resolves all ingredients and persists the recipe.
-func (s *Service) CommitStagedRecipe(ctx context.Context, job db.IngestionJob) (*db.Recipe, error) {
+// Ping checks that the Dictionary answers its health endpoint. It can serve
+// as a health.Check.
+func (d *DictionaryResolver) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.baseURL+"/healthz", nil)
+	if err != nil {
+		return fmt.Errorf("create health request: %w", err)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("dictionary health request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("dictionary health returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// IngestText creates a text_blob ingestion job and publishes it for
+// extraction.
+func (s *Service) IngestText(ctx context.Context, text string) (db.IngestionJob, error) {
+	return s.ingestText(ctx, db.CreateIngestionJobParams{Type: JobTypeTextBlob, RawInput: text})
+}
+
+func (s *Service) ingestText(ctx context.Context, params db.CreateIngestionJobParams) (db.IngestionJob, error) {
+	params.HouseholdID = tenant.FromContext(ctx)
+	params.CreatedBy = actor(ctx)
+	job, err := s.q.CreateIngestionJob(ctx, params)
+	if err != nil {
+		return db.IngestionJob{}, fmt.Errorf("create ingestion job: %w", err)
+	}
+	return s.requestExtraction(ctx, job, job.RawInput)
+}
+
+// requestExtraction optionally pre-fills job from text and publishes
+// recipe.import.requested. A job that cannot be published is marked failed.
+func (s *Service) requestExtraction(ctx context.Context, job db.IngestionJob, text string) (db.IngestionJob, error) {
+	ctx = logging.With(ctx, slog.Any("job_id", job.ID))
+	textJob := job
+	textJob.RawInput = text
+	if s.IngestPrefill() {
+		if prefilled, err := s.PrefillIngestionJob(ctx, textJob); err != nil {
+			slog.Default().WarnContext(ctx, "failed to prefill ingestion job", "error", err)
+		} else {
+			job.StagedData = prefilled.StagedData
+		}
+	}
+
+	if err := s.PublishRecipeImportRequested(ctx, textJob); err != nil {
+		s.failJob(ctx, job)
+		return job, err
+	}
+	return job, nil
+}
+
+// failJob marks a pending job failed. A job that was settled in the meantime,
+// e.g. rejected, is left alone.
+func (s *Service) failJob(ctx context.Context, job db.IngestionJob) {
+	_, err := s.q.UpdateIngestionJobStatus(ctx, db.UpdateIngestionJobStatusParams{
+		ID:          job.ID,
+		Status:      "failed",
+		HouseholdID: job.HouseholdID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.Default().ErrorContext(
+			logging.With(ctx, slog.Any("job_id", job.ID)),
+			"failed to mark ingestion job failed",
+			"error",
+			err,
+		)
+	}
+}
+
+// ConfirmIngestionJob commits a staged job's recipe and marks the job
+// confirmed, linking it to the new recipe, in one transaction. If the job
+// stopped being staged in the meantime, e.g. it was confirmed or rejected
+// concurrently, nothing is written and ErrJobNotStaged is returned.
+func (s *Service) ConfirmIngestionJob(ctx context.Context, job db.IngestionJob) (*db.Recipe, error) {
+	if job.Status != "staged" {
+		return nil, ErrJobNotStaged
+	}
+	ctx = tenant.WithHousehold(ctx, job.HouseholdID)
+	ctx = logging.With(ctx, slog.Any("job_id", job.ID))
+
 	var staged StagedRecipe
 	if job.StagedData == nil {
 		return nil, errors.New("staged data is nil")
@@ -95,33 +190,61 @@ func (s *Service) CommitStagedRecipe(ctx context.Context, job db.IngestionJob) (
 	if err := json.Unmarshal(*job.StagedData, &staged); err != nil {
 		return nil, fmt.Errorf("unmarshal staged data: %w", err)
 	}
+	if staged.SourceURL == "" && job.Type == JobTypeURL {
+		staged.SourceURL = job.RawInput
+	}
 
 	logger := slog.Default()
-	logger.InfoContext(
-		ctx,
-		"committing staged recipe",
-		"job_id",
-		job.ID,
-		"title",
-		staged.Title,
-		"ingredients",
-		len(staged.Ingredients),
-	)
+	logger.InfoContext(ctx, "committing staged recipe", "title", staged.Title, "ingredients", len(staged.Ingredients))
+
+	var recipe db.Recipe
+	err := s.WithTx(ctx, func(q db.Querier) error {
+		var err error
+		recipe, err = s.createRecipe(ctx, q, job.HouseholdID, "", staged)
+		if err != nil {
+			return err
+		}
 
-	tx, err := s.sqlDB.BeginTx(ctx, nil)
+		_, err = q.MarkIngestionJobConfirmed(ctx, db.MarkIngestionJobConfirmedParams{
+			ID:          job.ID,
+			RecipeID:    uuid.NullUUID{UUID: recipe.ID, Valid: true},
+			UpdatedBy:   actor(ctx),
+			HouseholdID: job.HouseholdID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrJobNotStaged
+		}
+		if err != nil {
+			return fmt.Errorf("mark job confirmed: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("begin tx: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback() //nolint:errcheck
 
-	qtx := db.New(tx)
+	logger.InfoContext(logging.With(ctx, slog.Any("recipe_id", recipe.ID)), "recipe committed", "title", recipe.Title)
+	return &recipe, nil
+}
 
+// createRecipe writes staged as a new recipe in household through q, which
+// should be bound to a transaction, and records its recipe.created event.
+// Ingredients without an ID are resolved by name through the Dictionary.
+func (s *Service) createRecipe(
+	ctx context.Context,
+	q db.Querier,
+	household uuid.UUID,
+	visibility string,
+	staged StagedRecipe,
+) (db.Recipe, error) {
 	tags := staged.Tags
 	if tags == nil {
 		tags = []string{}
 	}
 
-	recipe, err := qtx.CreateRecipe(ctx, db.CreateRecipeParams{
+	recipe, err := q.CreateRecipe(ctx, db.CreateRecipeParams{
+		HouseholdID: household,
+		Visibility:  visibility,
 		Title:       staged.Title,
 		Description: nullString(staged.Description),
 		SourceUrl:   nullString(staged.SourceURL),
@@ -129,41 +252,29 @@ func (s *Service) CommitStagedRecipe(ctx context.Context, job db.IngestionJob) (
 		PrepMinutes: nullInt32(staged.PrepMinutes),
 		CookMinutes: nullInt32(staged.CookMinutes),
 		Tags:        tags,
+		CreatedBy:   actor(ctx),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("create recipe: %w", err)
+		return db.Recipe{}, fmt.Errorf("create recipe: %w", err)
 	}
+	ctx = logging.With(ctx, slog.Any("recipe_id", recipe.ID))
 
 	for i, step := range staged.Steps {
-		if _, err := qtx.CreateStep(ctx, db.CreateStepParams{
+		if _, err := q.CreateStep(ctx, db.CreateStepParams{
 			RecipeID:    recipe.ID,
 			StepNumber:  int32(i + 1),
 			Instruction: step,
 		}); err != nil {
-			return nil, fmt.Errorf("create step %d: %w", i+1, err)
+			return db.Recipe{}, fmt.Errorf("create step %d: %w", i+1, err)
 		}
 	}
 
 	for _, ing := range staged.Ingredients {
-		var ingredientID uuid.UUID
-		if ing.IngredientID != "" {
-			ingredientID, err = uuid.Parse(ing.IngredientID)
-			if err != nil {
-				return nil, fmt.Errorf("parse ingredient_id %q: %w", ing.IngredientID, err)
-			}
-		} else {
-			if s.resolver == nil {
-				return nil, errors.New("ingredient resolver is not configured")
-			}
-
-			ingredientID, err = s.resolver.ResolveIngredient(ctx, ing.Name)
-			if err != nil {
-				return nil, fmt.Errorf("resolve ingredient %q: %w", ing.Name, err)
-			}
+		ingredientID, err := s.resolveStagedIngredient(ctx, ing)
+		if err != nil {
+			return db.Recipe{}, err
 		}
-
-		logger.InfoContext(ctx, "resolved ingredient", "name", ing.Name, "ingredient_id", ingredientID)
-		if _, err := qtx.CreateRecipeIngredient(ctx, db.CreateRecipeIngredientParams{
+		if _, err := q.CreateRecipeIngredient(ctx, db.CreateRecipeIngredientParams{
 			RecipeID:         recipe.ID,
 			IngredientID:     ingredientID,
 			Quantity:         nullFloat64(ing.Quantity),
@@ -171,14 +282,33 @@ func (s *Service) CommitStagedRecipe(ctx context.Context, job db.IngestionJob) (
 			IsOptional:       ing.IsOptional,
 			PreparationNotes: nullString(ing.PreparationNotes),
 		}); err != nil {
-			return nil, fmt.Errorf("create recipe ingredient: %w", err)
+			return db.Recipe{}, fmt.Errorf("create recipe ingredient: %w", err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit tx: %w", err)
+	if err := s.EnqueueRecipeEvent(ctx, q, events.RecipeCreatedEventType, recipe.ID); err != nil {
+		return db.Recipe{}, err
 	}
+	return recipe, nil
+}
 
-	logger.InfoContext(ctx, "recipe committed", "recipe_id", recipe.ID, "title", recipe.Title)
-	return &recipe, nil
+// resolveStagedIngredient returns ing's ingredient ID, resolving its name
+// through the Dictionary when the pipeline did not.
+func (s *Service) resolveStagedIngredient(ctx context.Context, ing StagedIngredient) (uuid.UUID, error) {
+	if ing.IngredientID != "" {
+		id, err := uuid.Parse(ing.IngredientID)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("parse ingredient_id %q: %w", ing.IngredientID, err)
+		}
+		return id, nil
+	}
+	if s.resolver == nil {
+		return uuid.Nil, errors.New("ingredient resolver is not configured")
+	}
+	id, err := s.resolver.ResolveIngredient(ctx, ing.Name)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("resolve ingredient %q: %w", ing.Name, err)
+	}
+	slog.Default().InfoContext(ctx, "resolved ingredient", "name", ing.Name, "ingredient_id", id)
+	return id, nil
 }
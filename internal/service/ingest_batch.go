@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// MaxBatchItems caps the number of inputs accepted in one batch submission.
+const MaxBatchItems = 100
+
+const (
+	defaultScrapeWorkers = 4
+	defaultBatchTimeout  = 10 * time.Minute
+)
+
+// Aggregate batch statuses reported by GetBatchStatus.
+const (
+	BatchStatusPending   = "pending"
+	BatchStatusStaged    = "staged"
+	BatchStatusCompleted = "completed"
+)
+
+// BatchItem is one input of a batch submission; exactly one of Text or URL
+// is set.
+type BatchItem struct {
+	Text string `json:"text,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// BatchItemResult reports what happened to one submitted item. Job is set
+// whenever a child job was created, including jobs that have already failed.
+type BatchItemResult struct {
+	Position int              `json:"position"`
+	Job      *db.IngestionJob `json:"job,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// BatchStatus aggregates the state of a batch's child jobs.
+type BatchStatus struct {
+	Batch  db.IngestionBatch
+	Status string
+	Counts map[string]int
+	Jobs   []db.IngestionJob
+}
+
+// BatchConfirmResult reports the outcome of confirming one child job.
+type BatchConfirmResult struct {
+	JobID    uuid.UUID  `json:"job_id"`
+	Status   string     `json:"status"`
+	RecipeID *uuid.UUID `json:"recipe_id,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// IngestBatch creates a batch with one child ingestion job per item and
+// submits each child as a single ingest would be, except that URL children
+// are returned pending and their pages are fetched in the background (see
+// scrapeBatch). A failing item does not stop the rest; its error is reported
+// in its result. Only a failure to create the batch itself is returned as an
+// error.
+func (s *Service) IngestBatch(ctx context.Context, items []BatchItem) (db.IngestionBatch, []BatchItemResult, error) {
+	batch, err := s.q.CreateIngestionBatch(ctx, tenant.FromContext(ctx))
+	if err != nil {
+		return db.IngestionBatch{}, nil, fmt.Errorf("create ingestion batch: %w", err)
+	}
+
+	results := make([]BatchItemResult, 0, len(items))
+	var urlJobs []db.IngestionJob
+	for i, item := range items {
+		params := db.CreateIngestionJobParams{
+			BatchID:       uuid.NullUUID{UUID: batch.ID, Valid: true},
+			BatchPosition: sql.NullInt32{Int32: int32(i), Valid: true}, //nolint:gosec // bounded by MaxBatchItems.
+		}
+
+		var job db.IngestionJob
+		if item.URL != "" {
+			params.Type, params.RawInput = JobTypeURL, item.URL
+			if job, err = s.createURLJob(ctx, params); err == nil {
+				urlJobs = append(urlJobs, job)
+			}
+		} else {
+			params.Type, params.RawInput = JobTypeTextBlob, item.Text
+			job, err = s.ingestText(ctx, params)
+		}
+
+		result := BatchItemResult{Position: i}
+		if job.ID != uuid.Nil {
+			result.Job = &job
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	if len(urlJobs) > 0 {
+		// The fetches outlive the request; they keep its values, such as the
+		// household and caller, but not its cancellation.
+		detached := context.WithoutCancel(ctx)
+		s.background.Go(func() { s.scrapeBatch(detached, batch.ID, urlJobs) })
+	}
+	return batch, results, nil
+}
+
+// scrapeBatch fetches the pages of a batch's url jobs, scrapeWorkers at a
+// time, and stages or forwards each one as IngestURL would. Jobs not fetched
+// within batchTimeout are marked failed. A job whose fetch was cut short by
+// a restart stays pending.
+func (s *Service) scrapeBatch(ctx context.Context, batchID uuid.UUID, jobs []db.IngestionJob) {
+	ctx = logging.With(ctx, slog.Any("batch_id", batchID))
+	logger := slog.Default()
+	fetchCtx, cancel := context.WithTimeout(ctx, s.batchTimeout)
+	defer cancel()
+
+	var (
+		workers     sync.WaitGroup
+		slots       = make(chan struct{}, max(s.scrapeWorkers, 1))
+		unprocessed int
+	)
+	for _, job := range jobs {
+		select {
+		case slots <- struct{}{}:
+		case <-fetchCtx.Done():
+		}
+		if fetchCtx.Err() != nil {
+			s.failJob(ctx, job)
+			unprocessed++
+			continue
+		}
+		workers.Go(func() {
+			defer func() { <-slots }()
+			if _, err := s.scrapeURLJob(fetchCtx, job); err != nil {
+				logger.WarnContext(logging.With(ctx, slog.Any("job_id", job.ID)), "failed to ingest batch url",
+					"url", job.RawInput, "error", err)
+			}
+		})
+	}
+	workers.Wait()
+
+	if unprocessed > 0 {
+		logger.WarnContext(ctx, "batch timed out; marked its unfetched urls failed",
+			"timeout", s.batchTimeout, "unfetched", unprocessed)
+	}
+}
+
+// GetBatchStatus loads a batch and summarises its children. The batch is
+// pending while any child is pending, staged while any child awaits
+// confirmation, and completed once every child is confirmed, failed or
+// rejected.
+func (s *Service) GetBatchStatus(ctx context.Context, id uuid.UUID) (BatchStatus, error) {
+	batch, err := s.q.GetIngestionBatch(ctx, db.GetIngestionBatchParams{ID: id, HouseholdID: tenant.FromContext(ctx)})
+	if err != nil {
+		return BatchStatus{}, fmt.Errorf("get ingestion batch: %w", err)
+	}
+
+	jobs, err := s.q.ListIngestionJobsByBatch(ctx, db.ListIngestionJobsByBatchParams{
+		BatchID:     uuid.NullUUID{UUID: id, Valid: true},
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return BatchStatus{}, fmt.Errorf("list batch jobs: %w", err)
+	}
+
+	counts := map[string]int{}
+	for _, job := range jobs {
+		counts[job.Status]++
+	}
+
+	status := BatchStatusCompleted
+	switch {
+	case counts["pending"] > 0:
+		status = BatchStatusPending
+	case counts["staged"] > 0:
+		status = BatchStatusStaged
+	}
+
+	return BatchStatus{Batch: batch, Status: status, Counts: counts, Jobs: jobs}, nil
+}
+
+// ConfirmBatch confirms every staged child of a batch. Children that are not
+// staged are skipped; a child that fails to commit is reported and does not
+// stop the others.
+func (s *Service) ConfirmBatch(ctx context.Context, id uuid.UUID) ([]BatchConfirmResult, error) {
+	if _, err := s.q.GetIngestionBatch(ctx, db.GetIngestionBatchParams{
+		ID:          id,
+		HouseholdID: tenant.FromContext(ctx),
+	}); err != nil {
+		return nil, fmt.Errorf("get ingestion batch: %w", err)
+	}
+
+	jobs, err := s.q.ListIngestionJobsByBatch(ctx, db.ListIngestionJobsByBatchParams{
+		BatchID:     uuid.NullUUID{UUID: id, Valid: true},
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list batch jobs: %w", err)
+	}
+
+	results := make([]BatchConfirmResult, 0, len(jobs))
+	for _, job := range jobs {
+		result := BatchConfirmResult{JobID: job.ID}
+		if job.Status != "staged" {
+			result.Status = "skipped"
+			results = append(results, result)
+			continue
+		}
+
+		recipe, err := s.ConfirmIngestionJob(ctx, job)
+		if err != nil {
+			result.Status = "error"
+			result.Error = err.Error()
+		} else {
+			result.Status = "confirmed"
+			result.RecipeID = &recipe.ID
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestIngestBatch_SubmitsEachItem(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	scraper := service.NewMockRecipeScraper(t)
+	publisher := &recordingImportPublisher{}
+	svc := service.New(mockQ, nil, nil, nil, publisher)
+	svc.SetScraper(scraper)
+
+	batch := db.IngestionBatch{ID: uuid.New()}
+	batchID := uuid.NullUUID{UUID: batch.ID, Valid: true}
+	mockQ.EXPECT().CreateIngestionBatch(mock.Anything, uuid.Nil).Return(batch, nil)
+
+	textJob := db.IngestionJob{ID: uuid.New(), Type: service.JobTypeTextBlob, RawInput: "Toast", Status: "pending"}
+	mockQ.EXPECT().CreateIngestionJob(mock.Anything, db.CreateIngestionJobParams{
+		Type:          service.JobTypeTextBlob,
+		RawInput:      "Toast",
+		BatchID:       batchID,
+		BatchPosition: sql.NullInt32{Int32: 0, Valid: true},
+	}).Return(textJob, nil)
+
+	urlJob := db.IngestionJob{ID: uuid.New(), Type: service.JobTypeURL, RawInput: recipeURL, Status: "pending"}
+	mockQ.EXPECT().CreateIngestionJob(mock.Anything, db.CreateIngestionJobParams{
+		Type:          service.JobTypeURL,
+		RawInput:      recipeURL,
+		BatchID:       batchID,
+		BatchPosition: sql.NullInt32{Int32: 1, Valid: true},
+	}).Return(urlJob, nil)
+	scraper.EXPECT().ScrapeRecipe(mock.Anything, recipeURL).Return(nil, errors.New("status 500"))
+	mockQ.EXPECT().UpdateIngestionJobStatus(mock.Anything, db.UpdateIngestionJobStatusParams{
+		ID:     urlJob.ID,
+		Status: "failed",
+	}).Return(db.IngestionJob{}, nil)
+
+	got, results, err := svc.IngestBatch(context.Background(), []service.BatchItem{
+		{Text: "Toast"},
+		{URL: recipeURL},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, batch, got)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, 0, results[0].Position)
+	assert.Equal(t, textJob.ID, results[0].Job.ID)
+	assert.Empty(t, results[0].Error)
+	assert.Equal(t, 1, results[1].Position)
+	assert.Equal(t, urlJob.ID, results[1].Job.ID)
+	assert.Equal(t, "pending", results[1].Job.Status, "the page is fetched after the batch is returned")
+	assert.Empty(t, results[1].Error)
+
+	svc.Wait()
+
+	require.Len(t, publisher.events, 1, "only the text item reaches the pipeline")
+	assert.Equal(t, textJob.ID, publisher.events[0].JobID)
+}
+
+func TestIngestBatch_FailsURLsNotFetchedInTime(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	scraper := service.NewMockRecipeScraper(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetScraper(scraper)
+	svc.SetBatchScraping(1, 50*time.Millisecond)
+
+	mockQ.EXPECT().CreateIngestionBatch(mock.Anything, uuid.Nil).Return(db.IngestionBatch{ID: uuid.New()}, nil)
+	var jobs []db.IngestionJob
+	mockQ.EXPECT().CreateIngestionJob(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, p db.CreateIngestionJobParams) (db.IngestionJob, error) {
+			job := db.IngestionJob{ID: uuid.New(), Type: p.Type, RawInput: p.RawInput, Status: "pending"}
+			jobs = append(jobs, job)
+			return job, nil
+		}).Times(3)
+	// The first page hangs past the deadline, holding the only worker, so
+	// the other two are never fetched.
+	scraper.EXPECT().ScrapeRecipe(mock.Anything, "https://example.com/slow").
+		RunAndReturn(func(ctx context.Context, _ string) (*service.ScrapedPage, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).Once()
+	var failed []uuid.UUID
+	var mu sync.Mutex
+	mockQ.EXPECT().UpdateIngestionJobStatus(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, p db.UpdateIngestionJobStatusParams) (db.IngestionJob, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, "failed", p.Status)
+			failed = append(failed, p.ID)
+			return db.IngestionJob{}, nil
+		}).Times(3)
+
+	_, results, err := svc.IngestBatch(context.Background(), []service.BatchItem{
+		{URL: "https://example.com/slow"},
+		{URL: "https://example.com/a"},
+		{URL: "https://example.com/b"},
+	})
+	require.NoError(t, err)
+	for _, result := range results {
+		assert.Empty(t, result.Error)
+	}
+
+	svc.Wait()
+	assert.ElementsMatch(t, []uuid.UUID{jobs[0].ID, jobs[1].ID, jobs[2].ID}, failed)
+}
+
+func TestGetBatchStatus_Aggregates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		statuses []string
+		want     string
+	}{
+		{"pending wins", []string{"staged", "pending", "failed"}, service.BatchStatusPending},
+		{"awaiting confirmation", []string{"staged", "confirmed", "failed"}, service.BatchStatusStaged},
+		{"all settled", []string{"confirmed", "failed"}, service.BatchStatusCompleted},
+		{"rejected settles", []string{"confirmed", "rejected"}, service.BatchStatusCompleted},
+		{"empty", nil, service.BatchStatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockQ := mocks.NewMockQuerier(t)
+			svc := service.New(mockQ, nil, nil, nil)
+			batchID := uuid.New()
+
+			var jobs []db.IngestionJob
+			for _, status := range tt.statuses {
+				jobs = append(jobs, db.IngestionJob{ID: uuid.New(), Status: status})
+			}
+			mockQ.EXPECT().GetIngestionBatch(mock.Anything, db.GetIngestionBatchParams{ID: batchID}).
+				Return(db.IngestionBatch{ID: batchID}, nil)
+			mockQ.EXPECT().ListIngestionJobsByBatch(mock.Anything, db.ListIngestionJobsByBatchParams{
+				BatchID: uuid.NullUUID{UUID: batchID, Valid: true},
+			}).Return(jobs, nil)
+
+			got, err := svc.GetBatchStatus(context.Background(), batchID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Status)
+			assert.Len(t, got.Jobs, len(tt.statuses))
+			for _, status := range tt.statuses {
+				assert.Positive(t, got.Counts[status])
+			}
+		})
+	}
+}
+
+func TestConfirmBatch_SkipsUnstagedJobs(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	batchID := uuid.New()
+
+	pending := db.IngestionJob{ID: uuid.New(), Status: "pending"}
+	confirmed := db.IngestionJob{ID: uuid.New(), Status: "confirmed"}
+	mockQ.EXPECT().GetIngestionBatch(mock.Anything, db.GetIngestionBatchParams{ID: batchID}).
+		Return(db.IngestionBatch{ID: batchID}, nil)
+	mockQ.EXPECT().ListIngestionJobsByBatch(mock.Anything, mock.Anything).
+		Return([]db.IngestionJob{pending, confirmed}, nil)
+
+	results, err := svc.ConfirmBatch(context.Background(), batchID)
+	require.NoError(t, err)
+	assert.Equal(t, []service.BatchConfirmResult{
+		{JobID: pending.ID, Status: "skipped"},
+		{JobID: confirmed.ID, Status: "skipped"},
+	}, results)
+}
+
+func TestConfirmBatch_UnknownBatch(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	mockQ.EXPECT().GetIngestionBatch(mock.Anything, mock.Anything).Return(db.IngestionBatch{}, sql.ErrNoRows)
+
+	_, err := svc.ConfirmBatch(context.Background(), uuid.New())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -2,29 +2,50 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/mwhite7112/woodpantry-recipes/internal/db"
 	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
 )
 
 // HandleRecipeImportedEvent applies queue results from the ingestion pipeline
-// to the local ingestion_jobs table.
+// to the local ingestion_jobs table. Only pending jobs are updated: a result
+// for a job that is already settled, e.g. rejected, confirmed or staged by an
+// earlier delivery, is logged and dropped rather than returned as an error,
+// which would requeue it forever.
 func (s *Service) HandleRecipeImportedEvent(ctx context.Context, event events.RecipeImportedEvent) error {
+	ctx = logging.With(ctx, slog.Any("job_id", event.JobID))
 	status := strings.TrimSpace(event.Status)
 	if status == "" {
 		status = "staged"
 	}
 
+	// Updates are scoped to the job's household, and duplicates are looked
+	// up among the recipes it can see, so scope the context to it.
+	household, err := s.q.GetIngestionJobHousehold(ctx, event.JobID)
+	if err != nil {
+		return fmt.Errorf("get ingestion job household: %w", err)
+	}
+	ctx = tenant.WithHousehold(ctx, household)
+
 	switch status {
 	case "failed":
-		_, err := s.q.UpdateIngestionJobStatus(ctx, db.UpdateIngestionJobStatusParams{
-			ID:     event.JobID,
-			Status: "failed",
+		_, err = s.q.UpdateIngestionJobStatus(ctx, db.UpdateIngestionJobStatusParams{
+			ID:          event.JobID,
+			Status:      "failed",
+			HouseholdID: household,
 		})
+		if errors.Is(err, sql.ErrNoRows) {
+			logSettledJob(ctx, status)
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("mark job failed: %w", err)
 		}
@@ -40,10 +61,16 @@ func (s *Service) HandleRecipeImportedEvent(ctx context.Context, event events.Re
 		}
 
 		raw := event.StagedData
-		_, err := s.q.UpdateIngestionJobStaged(ctx, db.UpdateIngestionJobStagedParams{
-			ID:         event.JobID,
-			StagedData: &raw,
+		_, err = s.q.UpdateIngestionJobStaged(ctx, db.UpdateIngestionJobStagedParams{
+			ID:          event.JobID,
+			StagedData:  &raw,
+			Duplicates:  s.duplicatesJSON(ctx, &staged),
+			HouseholdID: household,
 		})
+		if errors.Is(err, sql.ErrNoRows) {
+			logSettledJob(ctx, status)
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("stage imported recipe: %w", err)
 		}
@@ -52,3 +79,8 @@ func (s *Service) HandleRecipeImportedEvent(ctx context.Context, event events.Re
 		return fmt.Errorf("unsupported recipe.imported status: %q", status)
 	}
 }
+
+func logSettledJob(ctx context.Context, status string) {
+	slog.Default().WarnContext(ctx, "ignoring recipe.imported result for a job that is no longer pending",
+		"status", status)
+}
@@ -33,12 +33,16 @@ func TestHandleRecipeImportedEvent_DefaultsToStaged(t *testing.T) {
 	stagedJSON, err := json.Marshal(staged)
 	require.NoError(t, err)
 	stagedRaw := json.RawMessage(stagedJSON)
+	noDuplicates := json.RawMessage(`[]`)
 
+	mockQ.EXPECT().GetIngestionJobHousehold(mock.Anything, jobID).Return(uuid.Nil, nil)
+	mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, db.FindRecipesByTitleKeyParams{Title: "Pasta"}).Return(nil, nil)
 	mockQ.EXPECT().UpdateIngestionJobStaged(
 		mock.Anything,
 		db.UpdateIngestionJobStagedParams{
 			ID:         jobID,
 			StagedData: &stagedRaw,
+			Duplicates: &noDuplicates,
 		},
 	).Return(db.IngestionJob{ID: jobID, Status: "staged"}, nil)
 
@@ -49,18 +53,51 @@ func TestHandleRecipeImportedEvent_DefaultsToStaged(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestHandleRecipeImportedEvent_FailedStatus(t *testing.T) {
+func TestHandleRecipeImportedEvent_RecordsDuplicates(t *testing.T) {
 	t.Parallel()
 
 	mockQ := mocks.NewMockQuerier(t)
 	svc := service.New(mockQ, nil, nil, nil)
 
 	jobID := uuid.New()
+	existing := uuid.New()
+	household := uuid.New()
+	mockQ.EXPECT().GetIngestionJobHousehold(mock.Anything, jobID).Return(household, nil)
+	mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, db.FindRecipesByTitleKeyParams{
+		Title:       "Soup!",
+		HouseholdID: household,
+	}).
+		Return([]db.FindRecipesByTitleKeyRow{{ID: existing, Title: "soup"}}, nil)
+
+	var duplicates []service.DuplicateCandidate
+	mockQ.EXPECT().UpdateIngestionJobStaged(mock.Anything, mock.MatchedBy(func(p db.UpdateIngestionJobStagedParams) bool {
+		return p.Duplicates != nil && json.Unmarshal(*p.Duplicates, &duplicates) == nil
+	})).Return(db.IngestionJob{ID: jobID, Status: "staged"}, nil)
+
+	err := svc.HandleRecipeImportedEvent(context.Background(), events.RecipeImportedEvent{
+		JobID:      jobID,
+		StagedData: json.RawMessage(`{"title":"Soup!","ingredients":[]}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []service.DuplicateCandidate{
+		{RecipeID: existing, Title: "soup", Reasons: []string{service.DuplicateReasonTitle}},
+	}, duplicates)
+}
+
+func TestHandleRecipeImportedEvent_FailedStatus(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	jobID, household := uuid.New(), uuid.New()
+	mockQ.EXPECT().GetIngestionJobHousehold(mock.Anything, jobID).Return(household, nil)
 	mockQ.EXPECT().UpdateIngestionJobStatus(
 		mock.Anything,
 		db.UpdateIngestionJobStatusParams{
-			ID:     jobID,
-			Status: "failed",
+			ID:          jobID,
+			Status:      "failed",
+			HouseholdID: household,
 		},
 	).Return(db.IngestionJob{ID: jobID, Status: "failed"}, nil)
 
@@ -72,6 +109,34 @@ func TestHandleRecipeImportedEvent_FailedStatus(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestHandleRecipeImportedEvent_SettledJob(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []string{"staged", "failed"} {
+		t.Run(status, func(t *testing.T) {
+			t.Parallel()
+
+			mockQ := mocks.NewMockQuerier(t)
+			svc := service.New(mockQ, nil, nil, nil)
+
+			jobID, household := uuid.New(), uuid.New()
+			mockQ.EXPECT().GetIngestionJobHousehold(mock.Anything, jobID).Return(household, nil)
+			mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+			mockQ.EXPECT().UpdateIngestionJobStaged(mock.Anything, mock.Anything).
+				Return(db.IngestionJob{}, sql.ErrNoRows).Maybe()
+			mockQ.EXPECT().UpdateIngestionJobStatus(mock.Anything, mock.Anything).
+				Return(db.IngestionJob{}, sql.ErrNoRows).Maybe()
+
+			err := svc.HandleRecipeImportedEvent(context.Background(), events.RecipeImportedEvent{
+				JobID:      jobID,
+				Status:     status,
+				StagedData: json.RawMessage(`{"title":"Soup"}`),
+			})
+			require.NoError(t, err, "a rejected or confirmed job is acked, not requeued")
+		})
+	}
+}
+
 func TestHandleRecipeImportedEvent_UnknownJob(t *testing.T) {
 	t.Parallel()
 
@@ -80,14 +145,7 @@ func TestHandleRecipeImportedEvent_UnknownJob(t *testing.T) {
 
 	jobID := uuid.New()
 	stagedJSON := json.RawMessage(`{"title":"Soup","ingredients":[{"name":"water"}]}`)
-	stagedRaw := stagedJSON
-	mockQ.EXPECT().UpdateIngestionJobStaged(
-		mock.Anything,
-		db.UpdateIngestionJobStagedParams{
-			ID:         jobID,
-			StagedData: &stagedRaw,
-		},
-	).Return(db.IngestionJob{}, sql.ErrNoRows)
+	mockQ.EXPECT().GetIngestionJobHousehold(mock.Anything, jobID).Return(uuid.Nil, sql.ErrNoRows)
 
 	err := svc.HandleRecipeImportedEvent(context.Background(), events.RecipeImportedEvent{
 		JobID:      jobID,
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/document"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// File ingestion job types.
+const (
+	JobTypeMarkdown      = "markdown"
+	JobTypePDFText       = "pdf_text"
+	JobTypePlaintextFile = "plaintext_file"
+)
+
+// MaxUploadBytes is the largest file accepted for ingestion.
+const MaxUploadBytes = 10 << 20
+
+// ErrFileTooLarge is returned by IngestFile for uploads over MaxUploadBytes.
+var ErrFileTooLarge = errors.New("file exceeds upload size limit")
+
+// UploadedFile is a recipe file submitted for ingestion. ContentType is the
+// type the client declared; the stored type is sniffed from Data.
+type UploadedFile struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+var fileJobTypes = map[document.Format]string{
+	document.FormatMarkdown: JobTypeMarkdown,
+	document.FormatPDF:      JobTypePDFText,
+	document.FormatText:     JobTypePlaintextFile,
+}
+
+// IngestFile stores the original upload, extracts its text locally and
+// creates an ingestion job referencing the file. The extracted text becomes
+// the job's raw input, so the pipeline treats it like a text_blob.
+// Unsupported, textless or malformed files are rejected before anything is
+// stored with document.ErrUnsupportedFormat, document.ErrNoText or
+// document.ErrMalformed.
+func (s *Service) IngestFile(ctx context.Context, file UploadedFile) (db.IngestionJob, error) {
+	if len(file.Data) > MaxUploadBytes {
+		return db.IngestionJob{}, ErrFileTooLarge
+	}
+
+	format, err := document.Sniff(file.Filename, file.ContentType, file.Data)
+	if err != nil {
+		return db.IngestionJob{}, err
+	}
+	text, err := document.Text(format, file.Data)
+	if err != nil {
+		return db.IngestionJob{}, err
+	}
+
+	sum := sha256.Sum256(file.Data)
+	var job db.IngestionJob
+	err = s.WithTx(ctx, func(q db.Querier) error {
+		stored, err := q.CreateIngestionFile(ctx, db.CreateIngestionFileParams{
+			Filename:    file.Filename,
+			ContentType: format.ContentType(),
+			SizeBytes:   int64(len(file.Data)),
+			Sha256:      hex.EncodeToString(sum[:]),
+			Data:        file.Data,
+		})
+		if err != nil {
+			return fmt.Errorf("store ingestion file: %w", err)
+		}
+
+		job, err = q.CreateIngestionJob(ctx, db.CreateIngestionJobParams{
+			Type:         fileJobTypes[format],
+			RawInput:     text,
+			SourceFileID: uuid.NullUUID{UUID: stored.ID, Valid: true},
+			HouseholdID:  tenant.FromContext(ctx),
+			CreatedBy:    actor(ctx),
+		})
+		if err != nil {
+			return fmt.Errorf("create ingestion job: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return db.IngestionJob{}, err
+	}
+
+	return s.requestExtraction(ctx, job, job.RawInput)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/logging"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// ErrJobClosed is returned when rejecting a job that was already confirmed
+// or rejected, or when staging a url job that was rejected mid-fetch.
+var ErrJobClosed = errors.New("job is already confirmed or rejected")
+
+// ListIngestionJobs returns the caller's newest ingestion jobs, at most
+// limit of them. An empty status lists jobs in every status.
+func (s *Service) ListIngestionJobs(ctx context.Context, status string, limit int32) ([]db.IngestionJob, error) {
+	jobs, err := s.q.ListIngestionJobs(ctx, db.ListIngestionJobsParams{
+		HouseholdID: tenant.FromContext(ctx),
+		Status:      status,
+		MaxJobs:     limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list ingestion jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// RejectIngestionJob marks a pending, staged or failed job rejected so it is
+// never committed. Events the pipeline sends for it later are still applied
+// to the job, but it has to be rejected again.
+func (s *Service) RejectIngestionJob(ctx context.Context, job db.IngestionJob) (db.IngestionJob, error) {
+	rejected, err := s.q.RejectIngestionJob(ctx, db.RejectIngestionJobParams{
+		ID:          job.ID,
+		UpdatedBy:   actor(ctx),
+		HouseholdID: job.HouseholdID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.IngestionJob{}, ErrJobClosed
+	}
+	if err != nil {
+		return db.IngestionJob{}, fmt.Errorf("reject ingestion job: %w", err)
+	}
+	return rejected, nil
+}
+
+// ResolveStagedIngredients resolves the staged job's ingredients through the
+// Dictionary and stores their IDs in its staged recipe, refreshing its
+// duplicate candidates. Only ingredients without an ID are resolved unless
+// all is set. It returns the updated job and how many IDs changed.
+func (s *Service) ResolveStagedIngredients(
+	ctx context.Context,
+	job db.IngestionJob,
+	all bool,
+) (db.IngestionJob, int, error) {
+	if job.Status != "staged" {
+		return job, 0, ErrJobNotStaged
+	}
+	if job.StagedData == nil {
+		return job, 0, errors.New("staged data is nil")
+	}
+	if s.resolver == nil {
+		return job, 0, errors.New("ingredient resolver is not configured")
+	}
+	ctx = tenant.WithHousehold(ctx, job.HouseholdID)
+	ctx = logging.With(ctx, slog.Any("job_id", job.ID))
+
+	var staged StagedRecipe
+	if err := json.Unmarshal(*job.StagedData, &staged); err != nil {
+		return job, 0, fmt.Errorf("unmarshal staged data: %w", err)
+	}
+
+	changed := 0
+	for i, ing := range staged.Ingredients {
+		if ing.Name == "" || (ing.IngredientID != "" && !all) {
+			continue
+		}
+		id, err := s.resolver.ResolveIngredient(ctx, ing.Name)
+		if err != nil {
+			return job, 0, fmt.Errorf("resolve ingredient %q: %w", ing.Name, err)
+		}
+		if id.String() != ing.IngredientID {
+			staged.Ingredients[i].IngredientID = id.String()
+			changed++
+		}
+	}
+	if changed == 0 {
+		return job, 0, nil
+	}
+
+	data, err := json.Marshal(staged)
+	if err != nil {
+		return job, 0, fmt.Errorf("marshal staged data: %w", err)
+	}
+	raw := json.RawMessage(data)
+	updated, err := s.q.UpdateIngestionJobStagedData(ctx, db.UpdateIngestionJobStagedDataParams{
+		ID:          job.ID,
+		StagedData:  &raw,
+		Duplicates:  s.duplicatesJSON(ctx, &staged),
+		UpdatedBy:   actor(ctx),
+		HouseholdID: job.HouseholdID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return job, 0, ErrJobNotStaged
+	}
+	if err != nil {
+		return job, 0, fmt.Errorf("update staged data: %w", err)
+	}
+	return updated, changed, nil
+}
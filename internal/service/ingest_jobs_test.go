@@ -0,0 +1,172 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestRejectIngestionJob(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	job := db.IngestionJob{ID: uuid.New(), Status: "staged"}
+	mockQ.EXPECT().RejectIngestionJob(mock.Anything, db.RejectIngestionJobParams{ID: job.ID}).
+		Return(db.IngestionJob{ID: job.ID, Status: "rejected"}, nil)
+
+	got, err := svc.RejectIngestionJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", got.Status)
+}
+
+func TestRejectIngestionJob_Closed(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	job := db.IngestionJob{ID: uuid.New(), Status: "confirmed"}
+	mockQ.EXPECT().RejectIngestionJob(mock.Anything, mock.Anything).Return(db.IngestionJob{}, sql.ErrNoRows)
+
+	_, err := svc.RejectIngestionJob(context.Background(), job)
+	require.ErrorIs(t, err, service.ErrJobClosed)
+}
+
+func TestResolveStagedIngredients(t *testing.T) {
+	t.Parallel()
+
+	known, flour, salt := uuid.New(), uuid.New(), uuid.New()
+	staged := service.StagedRecipe{
+		Title: "Bread",
+		Ingredients: []service.StagedIngredient{
+			{Name: "flour"},
+			{Name: "salt", IngredientID: known.String()},
+		},
+	}
+	data, err := json.Marshal(staged)
+	require.NoError(t, err)
+	raw := json.RawMessage(data)
+
+	tests := []struct {
+		name    string
+		all     bool
+		resolve map[string]uuid.UUID
+		changed int
+		want    []string
+	}{
+		{"missing only", false, map[string]uuid.UUID{"flour": flour}, 1, []string{flour.String(), known.String()}},
+		{
+			"all",
+			true,
+			map[string]uuid.UUID{"flour": flour, "salt": salt},
+			2,
+			[]string{flour.String(), salt.String()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockQ := mocks.NewMockQuerier(t)
+			resolver := service.NewMockIngredientResolver(t)
+			svc := service.New(mockQ, nil, nil, resolver)
+
+			job := db.IngestionJob{ID: uuid.New(), Status: "staged", StagedData: &raw}
+			for name, id := range tt.resolve {
+				resolver.EXPECT().ResolveIngredient(mock.Anything, name).Return(id, nil)
+			}
+			mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, mock.Anything).Return(nil, nil)
+			var stored service.StagedRecipe
+			mockQ.EXPECT().UpdateIngestionJobStagedData(mock.Anything, mock.MatchedBy(
+				func(p db.UpdateIngestionJobStagedDataParams) bool {
+					return p.ID == job.ID && json.Unmarshal(*p.StagedData, &stored) == nil
+				},
+			)).Return(job, nil)
+
+			_, changed, err := svc.ResolveStagedIngredients(context.Background(), job, tt.all)
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, changed)
+			require.Len(t, stored.Ingredients, len(tt.want))
+			for i, id := range tt.want {
+				assert.Equal(t, id, stored.Ingredients[i].IngredientID)
+			}
+		})
+	}
+}
+
+func TestResolveStagedIngredients_NotStaged(t *testing.T) {
+	t.Parallel()
+
+	svc := service.New(mocks.NewMockQuerier(t), nil, nil, service.NewMockIngredientResolver(t))
+
+	_, _, err := svc.ResolveStagedIngredients(context.Background(), db.IngestionJob{Status: "confirmed"}, false)
+	require.ErrorIs(t, err, service.ErrJobNotStaged)
+}
+
+func TestConfirmIngestionJob(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetTransactor(transactor(t, mockQ))
+
+	household := uuid.New()
+	job := stagedJob(t, household)
+	created := db.Recipe{ID: uuid.New(), HouseholdID: household, Title: "Soup"}
+
+	mockQ.EXPECT().CreateRecipe(mock.Anything, mock.MatchedBy(func(p db.CreateRecipeParams) bool {
+		return p.HouseholdID == household && p.Title == "Soup"
+	})).Return(created, nil)
+	expectCreatedEvent(mockQ, created)
+	confirms := func(p db.MarkIngestionJobConfirmedParams) bool {
+		return p.ID == job.ID && p.HouseholdID == household && p.RecipeID.UUID == created.ID
+	}
+	mockQ.EXPECT().MarkIngestionJobConfirmed(mock.Anything, mock.MatchedBy(confirms)).Return(db.IngestionJob{ID: job.ID, Status: "confirmed"}, nil)
+
+	got, err := svc.ConfirmIngestionJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+}
+
+func TestConfirmIngestionJob_SettledConcurrently(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetTransactor(transactor(t, mockQ))
+
+	household := uuid.New()
+	job := stagedJob(t, household)
+	created := db.Recipe{ID: uuid.New(), HouseholdID: household, Title: "Soup"}
+
+	mockQ.EXPECT().CreateRecipe(mock.Anything, mock.Anything).Return(created, nil)
+	expectCreatedEvent(mockQ, created)
+	mockQ.EXPECT().MarkIngestionJobConfirmed(mock.Anything, mock.Anything).
+		Return(db.IngestionJob{}, sql.ErrNoRows)
+
+	_, err := svc.ConfirmIngestionJob(context.Background(), job)
+	require.ErrorIs(t, err, service.ErrJobNotStaged, "the recipe is rolled back")
+}
+
+// stagedJob returns a staged job in household holding a one-line recipe.
+func stagedJob(t *testing.T, household uuid.UUID) db.IngestionJob {
+	t.Helper()
+
+	data, err := json.Marshal(service.StagedRecipe{Title: "Soup"})
+	require.NoError(t, err)
+	raw := json.RawMessage(data)
+	return db.IngestionJob{ID: uuid.New(), Status: "staged", StagedData: &raw, HouseholdID: household}
+}
@@ -35,7 +35,7 @@ func TestDictionaryResolver_Success(t *testing.T) {
 	}))
 	defer server.Close()
 
-	resolver := NewDictionaryResolver(server.URL)
+	resolver := NewDictionaryResolver(server.URL, nil)
 	id, err := resolver.ResolveIngredient(context.Background(), "flour")
 	require.NoError(t, err)
 	assert.Equal(t, ingredientID, id)
@@ -57,7 +57,7 @@ func TestDictionaryResolver_Created(t *testing.T) {
 	}))
 	defer server.Close()
 
-	resolver := NewDictionaryResolver(server.URL)
+	resolver := NewDictionaryResolver(server.URL, nil)
 	id, err := resolver.ResolveIngredient(context.Background(), "new ingredient")
 	require.NoError(t, err)
 	assert.Equal(t, ingredientID, id)
@@ -71,7 +71,7 @@ func TestDictionaryResolver_ServerError(t *testing.T) {
 	}))
 	defer server.Close()
 
-	resolver := NewDictionaryResolver(server.URL)
+	resolver := NewDictionaryResolver(server.URL, nil)
 	_, err := resolver.ResolveIngredient(context.Background(), "flour")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "status 500")
@@ -87,7 +87,7 @@ func TestDictionaryResolver_InvalidJSON(t *testing.T) {
 	}))
 	defer server.Close()
 
-	resolver := NewDictionaryResolver(server.URL)
+	resolver := NewDictionaryResolver(server.URL, nil)
 	_, err := resolver.ResolveIngredient(context.Background(), "flour")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "decode resolve response")
@@ -107,7 +107,7 @@ func TestDictionaryResolver_InvalidUUID(t *testing.T) {
 	}))
 	defer server.Close()
 
-	resolver := NewDictionaryResolver(server.URL)
+	resolver := NewDictionaryResolver(server.URL, nil)
 	_, err := resolver.ResolveIngredient(context.Background(), "flour")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "parse ingredient id")
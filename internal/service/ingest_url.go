@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// Ingestion job types.
+const (
+	JobTypeTextBlob = "text_blob"
+	JobTypeURL      = "url"
+)
+
+// ErrScrapeFailed is returned by IngestURL when the page cannot be fetched.
+var ErrScrapeFailed = errors.New("fetch recipe page")
+
+// ScrapedPage is the result of fetching a recipe page. Recipe is set when the
+// page embeds schema.org Recipe data; otherwise Text holds the page's
+// readable text for the ingestion pipeline.
+type ScrapedPage struct {
+	Recipe *StagedRecipe
+	Text   string
+}
+
+// URLChecker is implemented by scrapers that refuse some URLs, such as ones
+// pointing into the service's own network, before anything is fetched.
+type URLChecker interface {
+	CheckURL(rawURL string) error
+}
+
+// CheckIngestURL returns the scraper's reason for refusing rawURL, if it is
+// a URLChecker, so that requests can be rejected before a job is created.
+func (s *Service) CheckIngestURL(rawURL string) error {
+	if checker, ok := s.scraper.(URLChecker); ok {
+		return checker.CheckURL(rawURL)
+	}
+	return nil
+}
+
+// IngestURL creates a url ingestion job and fetches the page. Pages with
+// embedded structured recipe data are staged immediately; anything else is
+// handed to the ingestion pipeline as cleaned text, like a text_blob job.
+func (s *Service) IngestURL(ctx context.Context, url string) (db.IngestionJob, error) {
+	return s.ingestURL(ctx, db.CreateIngestionJobParams{Type: JobTypeURL, RawInput: url})
+}
+
+func (s *Service) ingestURL(ctx context.Context, params db.CreateIngestionJobParams) (db.IngestionJob, error) {
+	job, err := s.createURLJob(ctx, params)
+	if err != nil {
+		return job, err
+	}
+	return s.scrapeURLJob(ctx, job)
+}
+
+// createURLJob creates a pending url job without fetching its page.
+func (s *Service) createURLJob(ctx context.Context, params db.CreateIngestionJobParams) (db.IngestionJob, error) {
+	if s.scraper == nil {
+		return db.IngestionJob{}, errors.New("url ingest is not configured")
+	}
+	params.HouseholdID = tenant.FromContext(ctx)
+	params.CreatedBy = actor(ctx)
+
+	job, err := s.q.CreateIngestionJob(ctx, params)
+	if err != nil {
+		return db.IngestionJob{}, fmt.Errorf("create ingestion job: %w", err)
+	}
+	return job, nil
+}
+
+// scrapeURLJob fetches a url job's page and stages the recipe embedded in it
+// or hands its text to the pipeline. A page that cannot be fetched fails the
+// job, even once ctx is done.
+func (s *Service) scrapeURLJob(ctx context.Context, job db.IngestionJob) (db.IngestionJob, error) {
+	url := job.RawInput
+	page, err := s.scraper.ScrapeRecipe(ctx, url)
+	if err != nil {
+		s.failJob(context.WithoutCancel(ctx), job)
+		return job, fmt.Errorf("%w: %w", ErrScrapeFailed, err)
+	}
+
+	if page.Recipe != nil {
+		page.Recipe.SourceURL = url
+		data, err := json.Marshal(page.Recipe)
+		if err != nil {
+			return job, fmt.Errorf("marshal scraped recipe: %w", err)
+		}
+		raw := json.RawMessage(data)
+		staged, err := s.q.UpdateIngestionJobStaged(ctx, db.UpdateIngestionJobStagedParams{
+			ID:          job.ID,
+			StagedData:  &raw,
+			Duplicates:  s.duplicatesJSON(ctx, page.Recipe),
+			HouseholdID: job.HouseholdID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return job, ErrJobClosed // rejected while the page was being fetched
+		}
+		if err != nil {
+			return job, fmt.Errorf("stage scraped recipe: %w", err)
+		}
+		return staged, nil
+	}
+
+	// The pipeline extracts from the page text, not the URL stored on the job.
+	return s.requestExtraction(ctx, job, page.Text)
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+const recipeURL = "https://example.com/chili"
+
+type recordingImportPublisher struct {
+	events []events.RecipeImportRequestedEvent
+}
+
+func (p *recordingImportPublisher) PublishRecipeImportRequested(
+	_ context.Context,
+	event events.RecipeImportRequestedEvent,
+) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func newURLJob(mockQ *mocks.MockQuerier) db.IngestionJob {
+	job := db.IngestionJob{ID: uuid.New(), Type: service.JobTypeURL, RawInput: recipeURL, Status: "pending"}
+	mockQ.EXPECT().CreateIngestionJob(mock.Anything, db.CreateIngestionJobParams{
+		Type:     service.JobTypeURL,
+		RawInput: recipeURL,
+	}).Return(job, nil)
+	return job
+}
+
+func TestIngestURL_StagesStructuredRecipe(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	scraper := service.NewMockRecipeScraper(t)
+	publisher := &recordingImportPublisher{}
+	svc := service.New(mockQ, nil, nil, nil, publisher)
+	svc.SetScraper(scraper)
+
+	job := newURLJob(mockQ)
+	scraper.EXPECT().ScrapeRecipe(mock.Anything, recipeURL).
+		Return(&service.ScrapedPage{Recipe: &service.StagedRecipe{Title: "Chili"}}, nil)
+
+	mockQ.EXPECT().FindRecipesBySourceURL(mock.Anything, db.FindRecipesBySourceURLParams{SourceUrl: recipeURL}).
+		Return(nil, nil)
+	mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, db.FindRecipesByTitleKeyParams{Title: "Chili"}).Return(nil, nil)
+
+	var staged service.StagedRecipe
+	mockQ.EXPECT().UpdateIngestionJobStaged(mock.Anything, mock.MatchedBy(func(p db.UpdateIngestionJobStagedParams) bool {
+		return p.ID == job.ID && json.Unmarshal(*p.StagedData, &staged) == nil
+	})).Return(db.IngestionJob{ID: job.ID, Status: "staged"}, nil)
+
+	got, err := svc.IngestURL(context.Background(), recipeURL)
+	require.NoError(t, err)
+	assert.Equal(t, "staged", got.Status)
+	assert.Equal(t, "Chili", staged.Title)
+	assert.Equal(t, recipeURL, staged.SourceURL)
+	assert.Empty(t, publisher.events, "structured recipes skip the pipeline")
+}
+
+func TestIngestURL_FallsBackToPipeline(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	scraper := service.NewMockRecipeScraper(t)
+	publisher := &recordingImportPublisher{}
+	svc := service.New(mockQ, nil, nil, nil, publisher)
+	svc.SetScraper(scraper)
+
+	job := newURLJob(mockQ)
+	scraper.EXPECT().ScrapeRecipe(mock.Anything, recipeURL).
+		Return(&service.ScrapedPage{Text: "Chili\n1 lb beef"}, nil)
+
+	got, err := svc.IngestURL(context.Background(), recipeURL)
+	require.NoError(t, err)
+	assert.Equal(t, job, got, "the job keeps the URL as its raw input")
+
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, job.ID, publisher.events[0].JobID)
+	assert.Equal(t, service.JobTypeURL, publisher.events[0].JobType)
+	assert.Equal(t, "Chili\n1 lb beef", publisher.events[0].RawInput)
+}
+
+func TestIngestURL_ScrapeFailureFailsJob(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	scraper := service.NewMockRecipeScraper(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetScraper(scraper)
+
+	job := newURLJob(mockQ)
+	scraper.EXPECT().ScrapeRecipe(mock.Anything, recipeURL).Return(nil, errors.New("status 404"))
+	mockQ.EXPECT().UpdateIngestionJobStatus(mock.Anything, db.UpdateIngestionJobStatusParams{
+		ID:     job.ID,
+		Status: "failed",
+	}).Return(db.IngestionJob{}, nil)
+
+	_, err := svc.IngestURL(context.Background(), recipeURL)
+	require.ErrorIs(t, err, service.ErrScrapeFailed)
+	assert.ErrorContains(t, err, "status 404")
+}
+
+func TestIngestURL_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	svc := service.New(mocks.NewMockQuerier(t), nil, nil, nil)
+	_, err := svc.IngestURL(context.Background(), recipeURL)
+	require.Error(t, err)
+}
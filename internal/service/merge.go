@@ -0,0 +1,339 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// Merge field choices. MergeTarget keeps the target's value and MergeSource
+// takes the source's; either falls back to the other side when its own value
+// is empty. MergeUnion is only valid for ingredients.
+const (
+	MergeTarget = "target"
+	MergeSource = "source"
+	MergeUnion  = "union"
+)
+
+// AuditActionMerge is the recipe_audit_log action recorded by MergeRecipes.
+const AuditActionMerge = "merge"
+
+// ErrInvalidMerge is returned for a merge request that cannot be applied.
+var ErrInvalidMerge = errors.New("invalid merge")
+
+// MergeStrategy selects, per field, which recipe's value survives a merge.
+// Empty fields default to MergeTarget. Tags are always combined.
+type MergeStrategy struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	SourceURL   string `json:"source_url,omitempty"`
+	Servings    string `json:"servings,omitempty"`
+	PrepMinutes string `json:"prep_minutes,omitempty"`
+	CookMinutes string `json:"cook_minutes,omitempty"`
+	Steps       string `json:"steps,omitempty"`
+	Ingredients string `json:"ingredients,omitempty"`
+}
+
+func (m MergeStrategy) validate() error {
+	fields := map[string]string{
+		"title":        m.Title,
+		"description":  m.Description,
+		"source_url":   m.SourceURL,
+		"servings":     m.Servings,
+		"prep_minutes": m.PrepMinutes,
+		"cook_minutes": m.CookMinutes,
+		"steps":        m.Steps,
+	}
+	for field, choice := range fields {
+		if choice != "" && choice != MergeTarget && choice != MergeSource {
+			return fmt.Errorf("%w: %s must be %q or %q", ErrInvalidMerge, field, MergeTarget, MergeSource)
+		}
+	}
+	switch m.Ingredients {
+	case "", MergeTarget, MergeSource, MergeUnion:
+		return nil
+	}
+	return fmt.Errorf("%w: ingredients must be %q, %q or %q", ErrInvalidMerge, MergeTarget, MergeSource, MergeUnion)
+}
+
+type mergeAuditDetails struct {
+	SourceID               uuid.UUID     `json:"source_id"`
+	SourceTitle            string        `json:"source_title"`
+	Strategy               MergeStrategy `json:"strategy"`
+	RepointedIngestionJobs int64         `json:"repointed_ingestion_jobs"`
+	RepointedShares        int64         `json:"repointed_shares"`
+}
+
+// MergeRecipes folds the source recipe into the target in one transaction:
+// fields are chosen per strategy, tags are combined, ingestion jobs that
+// produced the source and households it was shared with are repointed at the
+// target, the source is moved to the trash as TrashRecipe does and an audit
+// record is written. Lifecycle events are enqueued for the updated target
+// and the trashed source.
+//
+//nolint:gocognit,funlen // One transaction; splitting it scatters the merge rules.
+func (s *Service) MergeRecipes(
+	ctx context.Context,
+	targetID, sourceID uuid.UUID,
+	strategy MergeStrategy,
+) (db.Recipe, error) {
+	if targetID == sourceID {
+		return db.Recipe{}, fmt.Errorf("%w: a recipe cannot be merged into itself", ErrInvalidMerge)
+	}
+	if err := strategy.validate(); err != nil {
+		return db.Recipe{}, err
+	}
+
+	var merged db.Recipe
+	err := s.WithTx(ctx, func(q db.Querier) error {
+		household := tenant.FromContext(ctx)
+		target, source, err := lockMergePair(ctx, q, household, targetID, sourceID)
+		if err != nil {
+			return err
+		}
+
+		title := target.Title
+		if strategy.Title == MergeSource {
+			title = source.Title
+		}
+		merged, err = q.UpdateRecipe(ctx, db.UpdateRecipeParams{
+			ID:          targetID,
+			HouseholdID: household,
+			Title:       title,
+			Description: pickString(strategy.Description, target.Description, source.Description),
+			SourceUrl:   pickString(strategy.SourceURL, target.SourceUrl, source.SourceUrl),
+			Servings:    pickInt32(strategy.Servings, target.Servings, source.Servings),
+			PrepMinutes: pickInt32(strategy.PrepMinutes, target.PrepMinutes, source.PrepMinutes),
+			CookMinutes: pickInt32(strategy.CookMinutes, target.CookMinutes, source.CookMinutes),
+			Tags:        unionTags(target.Tags, source.Tags),
+			UpdatedBy:   actor(ctx),
+		})
+		if err != nil {
+			return fmt.Errorf("update target recipe: %w", err)
+		}
+
+		if err := mergeSteps(ctx, q, targetID, sourceID, strategy.Steps); err != nil {
+			return err
+		}
+		if err := mergeIngredients(ctx, q, targetID, sourceID, strategy.Ingredients); err != nil {
+			return err
+		}
+
+		repointed, err := q.RepointIngestionJobs(ctx, db.RepointIngestionJobsParams{
+			TargetID:    targetID,
+			SourceID:    sourceID,
+			HouseholdID: household,
+		})
+		if err != nil {
+			return fmt.Errorf("repoint ingestion jobs: %w", err)
+		}
+		shares, err := q.RepointRecipeShares(ctx, db.RepointRecipeSharesParams{
+			SourceID:    sourceID,
+			HouseholdID: household,
+			TargetID:    targetID,
+		})
+		if err != nil {
+			return fmt.Errorf("repoint recipe shares: %w", err)
+		}
+
+		if err := s.TrashRecipe(ctx, q, sourceID); err != nil {
+			return fmt.Errorf("trash source recipe: %w", err)
+		}
+		if err := s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, targetID); err != nil {
+			return err
+		}
+
+		details, err := json.Marshal(mergeAuditDetails{
+			SourceID:               sourceID,
+			SourceTitle:            source.Title,
+			Strategy:               strategy,
+			RepointedIngestionJobs: repointed,
+			RepointedShares:        shares,
+		})
+		if err != nil {
+			return fmt.Errorf("marshal audit details: %w", err)
+		}
+		if _, err := q.InsertRecipeAuditLog(ctx, db.InsertRecipeAuditLogParams{
+			RecipeID: targetID,
+			Action:   AuditActionMerge,
+			Details:  details,
+		}); err != nil {
+			return fmt.Errorf("insert audit log: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	return merged, nil
+}
+
+// lockMergePair locks the target and source rows in UUID order, so that two
+// merges of the same pair in opposite directions cannot deadlock.
+func lockMergePair(
+	ctx context.Context,
+	q db.Querier,
+	household, targetID, sourceID uuid.UUID,
+) (db.Recipe, db.Recipe, error) {
+	lock := func(id uuid.UUID, role string) (db.Recipe, error) {
+		recipe, err := q.LockRecipe(ctx, db.LockRecipeParams{ID: id, HouseholdID: household})
+		if err != nil {
+			return db.Recipe{}, fmt.Errorf("get %s recipe: %w", role, err)
+		}
+		return recipe, nil
+	}
+
+	if bytes.Compare(targetID[:], sourceID[:]) > 0 {
+		source, err := lock(sourceID, "source")
+		if err != nil {
+			return db.Recipe{}, db.Recipe{}, err
+		}
+		target, err := lock(targetID, "target")
+		return target, source, err
+	}
+	target, err := lock(targetID, "target")
+	if err != nil {
+		return db.Recipe{}, db.Recipe{}, err
+	}
+	source, err := lock(sourceID, "source")
+	return target, source, err
+}
+
+// pickString and pickInt32 return the chosen side's value, falling back to
+// the other side when the chosen one is NULL.
+func pickString(choice string, target, source sql.NullString) sql.NullString {
+	if choice == MergeSource {
+		target, source = source, target
+	}
+	if target.Valid {
+		return target
+	}
+	return source
+}
+
+func pickInt32(choice string, target, source sql.NullInt32) sql.NullInt32 {
+	if choice == MergeSource {
+		target, source = source, target
+	}
+	if target.Valid {
+		return target
+	}
+	return source
+}
+
+func unionTags(target, source []string) []string {
+	tags := make([]string, 0, len(target)+len(source))
+	seen := map[string]bool{}
+	for _, tag := range append(append([]string{}, target...), source...) {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// mergeSteps replaces the target's steps with the source's when the strategy
+// picks the source, or when the target has none.
+func mergeSteps(ctx context.Context, q db.Querier, targetID, sourceID uuid.UUID, choice string) error {
+	targetSteps, err := q.ListStepsByRecipe(ctx, db.ListStepsByRecipeParams{
+		RecipeID:    targetID,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("list target steps: %w", err)
+	}
+	if choice != MergeSource && len(targetSteps) > 0 {
+		return nil
+	}
+
+	sourceSteps, err := q.ListStepsByRecipe(ctx, db.ListStepsByRecipeParams{
+		RecipeID:    sourceID,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("list source steps: %w", err)
+	}
+	if len(sourceSteps) == 0 {
+		return nil
+	}
+
+	if err := q.DeleteStepsByRecipe(ctx, targetID); err != nil {
+		return fmt.Errorf("clear target steps: %w", err)
+	}
+	for _, step := range sourceSteps {
+		if _, err := q.CreateStep(ctx, db.CreateStepParams{
+			RecipeID:    targetID,
+			StepNumber:  step.StepNumber,
+			Instruction: step.Instruction,
+		}); err != nil {
+			return fmt.Errorf("copy step %d: %w", step.StepNumber, err)
+		}
+	}
+	return nil
+}
+
+// mergeIngredients copies source ingredients onto the target: all of them
+// (replacing the target's) for MergeSource or an empty target, and only
+// those whose ingredient the target lacks for MergeUnion.
+func mergeIngredients(ctx context.Context, q db.Querier, targetID, sourceID uuid.UUID, choice string) error {
+	targetIngredients, err := q.ListIngredientsByRecipe(ctx, db.ListIngredientsByRecipeParams{
+		RecipeID:    targetID,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("list target ingredients: %w", err)
+	}
+	replace := choice == MergeSource || len(targetIngredients) == 0
+	if !replace && choice != MergeUnion {
+		return nil
+	}
+
+	sourceIngredients, err := q.ListIngredientsByRecipe(ctx, db.ListIngredientsByRecipeParams{
+		RecipeID:    sourceID,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("list source ingredients: %w", err)
+	}
+	if replace && len(sourceIngredients) == 0 {
+		return nil
+	}
+
+	have := map[uuid.UUID]bool{}
+	if replace {
+		if err := q.DeleteIngredientsByRecipe(ctx, targetID); err != nil {
+			return fmt.Errorf("clear target ingredients: %w", err)
+		}
+	} else {
+		for _, ing := range targetIngredients {
+			have[ing.IngredientID] = true
+		}
+	}
+
+	for _, ing := range sourceIngredients {
+		if have[ing.IngredientID] {
+			continue
+		}
+		if _, err := q.CreateRecipeIngredient(ctx, db.CreateRecipeIngredientParams{
+			RecipeID:         targetID,
+			IngredientID:     ing.IngredientID,
+			Quantity:         ing.Quantity,
+			Unit:             ing.Unit,
+			IsOptional:       ing.IsOptional,
+			PreparationNotes: ing.PreparationNotes,
+		}); err != nil {
+			return fmt.Errorf("copy ingredient: %w", err)
+		}
+	}
+	return nil
+}
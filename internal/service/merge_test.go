@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestMergeRecipes(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetTransactor(transactor(t, mockQ))
+
+	targetID, sourceID := uuid.New(), uuid.New()
+	beef, beans := uuid.New(), uuid.New()
+	target := db.Recipe{
+		ID:          targetID,
+		Title:       "Chili",
+		Description: sql.NullString{String: "Target notes", Valid: true},
+		Tags:        []string{"dinner"},
+	}
+	source := db.Recipe{
+		ID:       sourceID,
+		Title:    "Best Chili",
+		Servings: sql.NullInt32{Int32: 4, Valid: true},
+		Tags:     []string{"dinner", "spicy"},
+	}
+
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: targetID}).Return(target, nil)
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: sourceID}).Return(source, nil)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: targetID}).Return(target, nil)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: sourceID}).Return(source, nil)
+	mockQ.EXPECT().UpdateRecipe(mock.Anything, db.UpdateRecipeParams{
+		ID:          targetID,
+		Title:       "Best Chili",
+		Description: target.Description,
+		Servings:    source.Servings,
+		Tags:        []string{"dinner", "spicy"},
+	}).Return(db.Recipe{ID: targetID, Title: "Best Chili"}, nil)
+
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{RecipeID: targetID}).
+		Return([]db.RecipeStep{{RecipeID: targetID, StepNumber: 1, Instruction: "Simmer."}}, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{RecipeID: sourceID}).Return(nil, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{RecipeID: targetID}).
+		Return([]db.RecipeIngredient{{RecipeID: targetID, IngredientID: beef}}, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{
+		RecipeID: sourceID,
+	}).Return([]db.RecipeIngredient{
+		{RecipeID: sourceID, IngredientID: beef},
+		{RecipeID: sourceID, IngredientID: beans, Unit: sql.NullString{String: "can", Valid: true}},
+	}, nil)
+	mockQ.EXPECT().CreateRecipeIngredient(mock.Anything, db.CreateRecipeIngredientParams{
+		RecipeID:     targetID,
+		IngredientID: beans,
+		Unit:         sql.NullString{String: "can", Valid: true},
+	}).Return(db.RecipeIngredient{}, nil).Once()
+
+	mockQ.EXPECT().RepointIngestionJobs(mock.Anything, db.RepointIngestionJobsParams{
+		TargetID: targetID,
+		SourceID: sourceID,
+	}).Return(2, nil)
+	mockQ.EXPECT().RepointRecipeShares(mock.Anything, db.RepointRecipeSharesParams{
+		SourceID: sourceID,
+		TargetID: targetID,
+	}).Return(1, nil)
+	mockQ.EXPECT().InsertOutboxEvent(mock.Anything, mock.MatchedBy(func(p db.InsertOutboxEventParams) bool {
+		return p.EventType == "recipe.deleted.v1"
+	})).Return(db.EventOutbox{}, nil).Once()
+	mockQ.EXPECT().SoftDeleteRecipe(mock.Anything, db.SoftDeleteRecipeParams{ID: sourceID}).
+		Return(db.Recipe{ID: sourceID}, nil)
+	mockQ.EXPECT().InsertOutboxEvent(mock.Anything, mock.MatchedBy(func(p db.InsertOutboxEventParams) bool {
+		return p.EventType == "recipe.updated.v1"
+	})).Return(db.EventOutbox{}, nil).Once()
+	mockQ.EXPECT().InsertRecipeRevision(mock.Anything, mock.MatchedBy(func(p db.InsertRecipeRevisionParams) bool {
+		return p.RecipeID == targetID
+	})).Return(db.RecipeRevision{}, nil).Once()
+
+	var audit map[string]any
+	mockQ.EXPECT().InsertRecipeAuditLog(mock.Anything, mock.MatchedBy(func(p db.InsertRecipeAuditLogParams) bool {
+		return p.RecipeID == targetID && p.Action == service.AuditActionMerge && json.Unmarshal(p.Details, &audit) == nil
+	})).Return(db.RecipeAuditLog{}, nil)
+
+	got, err := svc.MergeRecipes(context.Background(), targetID, sourceID, service.MergeStrategy{
+		Title:       service.MergeSource,
+		Ingredients: service.MergeUnion,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Best Chili", got.Title)
+	assert.Equal(t, sourceID.String(), audit["source_id"])
+	assert.Equal(t, "Best Chili", audit["source_title"])
+	assert.InDelta(t, 2, audit["repointed_ingestion_jobs"], 0)
+	assert.InDelta(t, 1, audit["repointed_shares"], 0)
+}
+
+func TestMergeRecipes_Invalid(t *testing.T) {
+	t.Parallel()
+
+	svc := service.New(mocks.NewMockQuerier(t), nil, nil, nil)
+	id := uuid.New()
+
+	_, err := svc.MergeRecipes(context.Background(), id, id, service.MergeStrategy{})
+	require.ErrorIs(t, err, service.ErrInvalidMerge)
+
+	_, err = svc.MergeRecipes(context.Background(), id, uuid.New(), service.MergeStrategy{Steps: service.MergeUnion})
+	require.ErrorIs(t, err, service.ErrInvalidMerge)
+}
+
+func TestMergeRecipes_SourceNotFound(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetTransactor(transactor(t, mockQ))
+	targetID, sourceID := uuid.New(), uuid.New()
+
+	// The target is only locked when its ID sorts before the source's.
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: targetID}).
+		Return(db.Recipe{ID: targetID}, nil).Maybe()
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: sourceID}).Return(db.Recipe{}, sql.ErrNoRows)
+
+	_, err := svc.MergeRecipes(context.Background(), targetID, sourceID, service.MergeStrategy{})
+	require.ErrorIs(t, err, sql.ErrNoRows, "the merge is rolled back")
+}
+
+func TestMergeRecipes_LocksInIDOrder(t *testing.T) {
+	t.Parallel()
+
+	low := uuid.MustParse("00000000-0000-4000-8000-000000000001")
+	high := uuid.MustParse("ffffffff-ffff-4fff-bfff-ffffffffffff")
+	for name, ids := range map[string][2]uuid.UUID{
+		"target first": {low, high},
+		"source first": {high, low},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockQ := mocks.NewMockQuerier(t)
+			svc := service.New(mockQ, nil, nil, nil)
+			svc.SetTransactor(transactor(t, mockQ))
+
+			// Failing the first lock proves which row was locked first: the
+			// other one is never requested.
+			mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: low}).Return(db.Recipe{}, sql.ErrNoRows)
+
+			_, err := svc.MergeRecipes(context.Background(), ids[0], ids[1], service.MergeStrategy{})
+			require.ErrorIs(t, err, sql.ErrNoRows)
+		})
+	}
+}
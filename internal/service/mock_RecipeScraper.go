@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRecipeScraper is an autogenerated mock type for the RecipeScraper type
+type MockRecipeScraper struct {
+	mock.Mock
+}
+
+type MockRecipeScraper_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRecipeScraper) EXPECT() *MockRecipeScraper_Expecter {
+	return &MockRecipeScraper_Expecter{mock: &_m.Mock}
+}
+
+// ScrapeRecipe provides a mock function with given fields: ctx, url
+func (_m *MockRecipeScraper) ScrapeRecipe(ctx context.Context, url string) (*ScrapedPage, error) {
+	ret := _m.Called(ctx, url)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScrapeRecipe")
+	}
+
+	var r0 *ScrapedPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*ScrapedPage, error)); ok {
+		return rf(ctx, url)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *ScrapedPage); ok {
+		r0 = rf(ctx, url)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ScrapedPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, url)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRecipeScraper_ScrapeRecipe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScrapeRecipe'
+type MockRecipeScraper_ScrapeRecipe_Call struct {
+	*mock.Call
+}
+
+// ScrapeRecipe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - url string
+func (_e *MockRecipeScraper_Expecter) ScrapeRecipe(ctx interface{}, url interface{}) *MockRecipeScraper_ScrapeRecipe_Call {
+	return &MockRecipeScraper_ScrapeRecipe_Call{Call: _e.mock.On("ScrapeRecipe", ctx, url)}
+}
+
+func (_c *MockRecipeScraper_ScrapeRecipe_Call) Run(run func(ctx context.Context, url string)) *MockRecipeScraper_ScrapeRecipe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockRecipeScraper_ScrapeRecipe_Call) Return(_a0 *ScrapedPage, _a1 error) *MockRecipeScraper_ScrapeRecipe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRecipeScraper_ScrapeRecipe_Call) RunAndReturn(run func(context.Context, string) (*ScrapedPage, error)) *MockRecipeScraper_ScrapeRecipe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRecipeScraper creates a new instance of MockRecipeScraper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRecipeScraper(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRecipeScraper {
+	mock := &MockRecipeScraper{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
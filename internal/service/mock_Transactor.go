@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	db "github.com/mwhite7112/woodpantry-recipes/internal/db"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTransactor is an autogenerated mock type for the Transactor type
+type MockTransactor struct {
+	mock.Mock
+}
+
+type MockTransactor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTransactor) EXPECT() *MockTransactor_Expecter {
+	return &MockTransactor_Expecter{mock: &_m.Mock}
+}
+
+// InTx provides a mock function with given fields: ctx, fn
+func (_m *MockTransactor) InTx(ctx context.Context, fn func(db.Querier) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InTx")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(db.Querier) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTransactor_InTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InTx'
+type MockTransactor_InTx_Call struct {
+	*mock.Call
+}
+
+// InTx is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(db.Querier) error
+func (_e *MockTransactor_Expecter) InTx(ctx interface{}, fn interface{}) *MockTransactor_InTx_Call {
+	return &MockTransactor_InTx_Call{Call: _e.mock.On("InTx", ctx, fn)}
+}
+
+func (_c *MockTransactor_InTx_Call) Run(run func(ctx context.Context, fn func(db.Querier) error)) *MockTransactor_InTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(db.Querier) error))
+	})
+	return _c
+}
+
+func (_c *MockTransactor_InTx_Call) Return(_a0 error) *MockTransactor_InTx_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTransactor_InTx_Call) RunAndReturn(run func(context.Context, func(db.Querier) error) error) *MockTransactor_InTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTransactor creates a new instance of MockTransactor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTransactor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTransactor {
+	mock := &MockTransactor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+const (
+	defaultOutboxInterval    = 2 * time.Second
+	defaultOutboxBatchSize   = 100
+	defaultOutboxMaxAttempts = 50
+)
+
+// EventPublisher publishes serialized events by routing key.
+type EventPublisher interface {
+	Publish(ctx context.Context, routingKey string, body []byte) error
+}
+
+// EnqueueRecipeEvent writes a recipe lifecycle event to the outbox. q should be
+// bound to the same transaction as the recipe write so the event is recorded
+// if and only if the write commits. For deletes, call it before deleting so
+// the payload can capture the recipe's final state. Creates and updates also
+// record the payload as the recipe's next revision, so history and events
+// never disagree.
+func (s *Service) EnqueueRecipeEvent(
+	ctx context.Context,
+	q db.Querier,
+	eventType string,
+	recipeID uuid.UUID,
+) error {
+	payload, err := loadRecipePayload(ctx, q, recipeID)
+	if err != nil {
+		return err
+	}
+	if eventType != events.RecipeDeletedEventType {
+		if _, err := recordRevision(ctx, q, payload); err != nil {
+			return err
+		}
+	}
+
+	event := events.NewRecipeLifecycleEvent(eventType, recipeID, payload)
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+
+	if _, err := q.InsertOutboxEvent(ctx, db.InsertOutboxEventParams{
+		EventType:  eventType,
+		RoutingKey: event.RoutingKey(),
+		Payload:    body,
+	}); err != nil {
+		return fmt.Errorf("insert %s outbox event: %w", eventType, err)
+	}
+
+	return nil
+}
+
+func loadRecipePayload(ctx context.Context, q db.Querier, id uuid.UUID) (*events.RecipePayload, error) {
+	recipe, err := q.GetRecipe(ctx, db.GetRecipeParams{ID: id, HouseholdID: tenant.FromContext(ctx)})
+	if err != nil {
+		return nil, fmt.Errorf("get recipe: %w", err)
+	}
+	steps, err := q.ListStepsByRecipe(ctx, db.ListStepsByRecipeParams{
+		RecipeID:    id,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list steps: %w", err)
+	}
+	ingredients, err := q.ListIngredientsByRecipe(ctx, db.ListIngredientsByRecipeParams{
+		RecipeID:    id,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list ingredients: %w", err)
+	}
+
+	payload := &events.RecipePayload{
+		ID:          recipe.ID,
+		Title:       recipe.Title,
+		Description: recipe.Description.String,
+		SourceURL:   recipe.SourceUrl.String,
+		Servings:    recipe.Servings.Int32,
+		PrepMinutes: recipe.PrepMinutes.Int32,
+		CookMinutes: recipe.CookMinutes.Int32,
+		Tags:        recipe.Tags,
+		Steps:       make([]events.RecipeStepPayload, 0, len(steps)),
+		Ingredients: make([]events.RecipeIngredientPayload, 0, len(ingredients)),
+		CreatedAt:   recipe.CreatedAt,
+		UpdatedAt:   recipe.UpdatedAt,
+	}
+	if payload.Tags == nil {
+		payload.Tags = []string{}
+	}
+	for _, step := range steps {
+		payload.Steps = append(payload.Steps, events.RecipeStepPayload{
+			StepNumber:  step.StepNumber,
+			Instruction: step.Instruction,
+		})
+	}
+	for _, ing := range ingredients {
+		payload.Ingredients = append(payload.Ingredients, events.RecipeIngredientPayload{
+			ID:               ing.ID,
+			IngredientID:     ing.IngredientID,
+			Quantity:         ing.Quantity.Float64,
+			Unit:             ing.Unit.String,
+			IsOptional:       ing.IsOptional,
+			PreparationNotes: ing.PreparationNotes.String,
+		})
+	}
+
+	return payload, nil
+}
+
+// OutboxRelay publishes pending event_outbox rows in insertion order, which
+// for any one recipe is the order its writes committed. Delivery is
+// at-least-once: a row is published again if marking it fails. Replicas take
+// turns through an advisory lock, so only one of them publishes at a time.
+//
+// A row that fails maxAttempts times is parked and skipped from then on, so
+// later events for its recipe can overtake it. Parked rows keep their
+// last_error and are retried once their attempts are reset.
+type OutboxRelay struct {
+	sqlDB       *sql.DB
+	publisher   EventPublisher
+	logger      *slog.Logger
+	interval    time.Duration
+	batchSize   int32
+	maxAttempts int32
+	decorate    func(db.Querier) db.Querier
+}
+
+func NewOutboxRelay(sqlDB *sql.DB, publisher EventPublisher, logger *slog.Logger) (*OutboxRelay, error) {
+	if sqlDB == nil {
+		return nil, errors.New("database is required")
+	}
+	if publisher == nil {
+		return nil, errors.New("publisher is required")
+	}
+	if logger == nil {
+		return nil, errors.New("logger is required")
+	}
+
+	return &OutboxRelay{
+		sqlDB:       sqlDB,
+		publisher:   publisher,
+		logger:      logger,
+		interval:    defaultOutboxInterval,
+		batchSize:   defaultOutboxBatchSize,
+		maxAttempts: defaultOutboxMaxAttempts,
+	}, nil
+}
+
+// SetQuerierDecorator wraps the Querier of each relay transaction with
+// decorate (e.g. to instrument queries).
+func (r *OutboxRelay) SetQuerierDecorator(decorate func(db.Querier) db.Querier) {
+	r.decorate = decorate
+}
+
+// Run relays pending events every interval until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) error {
+	r.logger.InfoContext(ctx, "outbox relay started", "interval", r.interval)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// A batch is finished even if ctx is cancelled part-way, so that
+		// published events are marked and not sent again after a restart.
+		if _, err := r.RelayPending(context.WithoutCancel(ctx)); err != nil {
+			r.logger.ErrorContext(ctx, "outbox relay failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.InfoContext(ctx, "outbox relay stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes one batch of pending events and returns how many
+// were published. It stops at the first publish failure so that the events
+// behind it wait rather than overtake it. It publishes nothing while another
+// replica holds the relay lock.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := decorateQuerier(db.New(tx), r.decorate)
+	locked, err := qtx.TryLockOutboxRelay(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("lock outbox relay: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	pending, err := qtx.ListPendingOutboxEvents(ctx, db.ListPendingOutboxEventsParams{
+		MaxAttempts: r.maxAttempts,
+		BatchSize:   r.batchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list pending outbox events: %w", err)
+	}
+
+	published := 0
+	for _, event := range pending {
+		if err := r.publisher.Publish(ctx, event.RoutingKey, event.Payload); err != nil {
+			r.logger.WarnContext(
+				ctx,
+				"failed to publish outbox event",
+				"event_id",
+				event.ID,
+				"event_type",
+				event.EventType,
+				"attempts",
+				event.Attempts+1,
+				"error",
+				err,
+			)
+			if markErr := qtx.MarkOutboxEventFailed(ctx, db.MarkOutboxEventFailedParams{
+				ID:        event.ID,
+				LastError: nullString(err.Error()),
+			}); markErr != nil {
+				return published, fmt.Errorf("mark outbox event failed: %w", markErr)
+			}
+			if event.Attempts+1 >= r.maxAttempts {
+				r.logger.ErrorContext(ctx, "parked outbox event after too many failed publishes",
+					"event_id", event.ID, "event_type", event.EventType, "attempts", event.Attempts+1)
+			}
+			break
+		}
+
+		if err := qtx.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+			return published, fmt.Errorf("mark outbox event published: %w", err)
+		}
+		published++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return published, nil
+}
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestEnqueueRecipeEvent_WritesFullPayload(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	recipeID := uuid.New()
+	ingredientID := uuid.New()
+	now := time.Now().UTC()
+
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: recipeID}).Return(db.Recipe{
+		ID:          recipeID,
+		Title:       "Pasta",
+		CookMinutes: sql.NullInt32{Int32: 20, Valid: true},
+		Tags:        []string{"dinner"},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{
+		RecipeID: recipeID,
+	}).Return([]db.RecipeStep{
+		{RecipeID: recipeID, StepNumber: 1, Instruction: "Boil pasta"},
+	}, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{
+		RecipeID: recipeID,
+	}).Return([]db.RecipeIngredient{
+		{RecipeID: recipeID, IngredientID: ingredientID, Unit: sql.NullString{String: "g", Valid: true}},
+	}, nil)
+
+	var revision db.InsertRecipeRevisionParams
+	mockQ.EXPECT().
+		InsertRecipeRevision(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, arg db.InsertRecipeRevisionParams) { revision = arg }).
+		Return(db.RecipeRevision{}, nil)
+
+	var inserted db.InsertOutboxEventParams
+	mockQ.EXPECT().
+		InsertOutboxEvent(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, arg db.InsertOutboxEventParams) { inserted = arg }).
+		Return(db.EventOutbox{}, nil)
+
+	err := svc.EnqueueRecipeEvent(context.Background(), mockQ, events.RecipeCreatedEventType, recipeID)
+	require.NoError(t, err)
+
+	assert.Equal(t, events.RecipeCreatedEventType, inserted.EventType)
+	assert.Equal(t, "recipe.created", inserted.RoutingKey)
+
+	var event events.RecipeLifecycleEvent
+	require.NoError(t, json.Unmarshal(inserted.Payload, &event))
+	assert.Equal(t, events.RecipeCreatedEventType, event.EventType)
+	assert.Equal(t, recipeID, event.RecipeID)
+	assert.NotEqual(t, uuid.Nil, event.EventID)
+	require.NotNil(t, event.Recipe)
+	assert.Equal(t, "Pasta", event.Recipe.Title)
+	assert.Equal(t, int32(20), event.Recipe.CookMinutes)
+	require.Len(t, event.Recipe.Steps, 1)
+	assert.Equal(t, "Boil pasta", event.Recipe.Steps[0].Instruction)
+	require.Len(t, event.Recipe.Ingredients, 1)
+	assert.Equal(t, ingredientID, event.Recipe.Ingredients[0].IngredientID)
+	assert.Equal(t, "g", event.Recipe.Ingredients[0].Unit)
+
+	assert.Equal(t, recipeID, revision.RecipeID)
+	var snapshot events.RecipePayload
+	require.NoError(t, json.Unmarshal(revision.Snapshot, &snapshot))
+	assert.Equal(t, *event.Recipe, snapshot)
+}
+
+func TestEnqueueRecipeEvent_UnknownRecipe(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	recipeID := uuid.New()
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: recipeID}).Return(db.Recipe{}, sql.ErrNoRows)
+
+	err := svc.EnqueueRecipeEvent(context.Background(), mockQ, events.RecipeDeletedEventType, recipeID)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
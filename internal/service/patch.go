@@ -0,0 +1,381 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+var (
+	// ErrInvalidPatch is returned for a patch or sub-resource edit that
+	// cannot be applied.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrStepNotFound is returned when a recipe has no step with the given number.
+	ErrStepNotFound = errors.New("step not found")
+	// ErrIngredientNotFound is returned when a recipe has no ingredient row
+	// with the given ID.
+	ErrIngredientNotFound = errors.New("ingredient not found")
+)
+
+// Patch is an RFC 7396 JSON merge patch: a member sets its field, a null
+// member clears it and an absent member leaves it unchanged.
+type Patch map[string]json.RawMessage
+
+// PatchRecipe applies patch to the recipe's scalar fields and tags. Tags are
+// replaced as a whole, as merge patch does for arrays. Steps and ingredients
+// are edited through their own sub-resources instead.
+//
+//nolint:gocognit // One case per patchable field.
+func (s *Service) PatchRecipe(ctx context.Context, q db.Querier, id uuid.UUID, patch Patch) (db.Recipe, error) {
+	current, err := lockOwnRecipe(ctx, q, id)
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	params := db.UpdateRecipeParams{
+		ID:          id,
+		HouseholdID: current.HouseholdID,
+		Visibility:  current.Visibility,
+		Title:       current.Title,
+		Description: current.Description,
+		SourceUrl:   current.SourceUrl,
+		Servings:    current.Servings,
+		PrepMinutes: current.PrepMinutes,
+		CookMinutes: current.CookMinutes,
+		Tags:        current.Tags,
+		UpdatedBy:   actor(ctx),
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(patch)) {
+		raw := patch[field]
+		switch field {
+		case "title":
+			var title string
+			if isNull(raw) || json.Unmarshal(raw, &title) != nil || title == "" {
+				return db.Recipe{}, fmt.Errorf("%w: title must be a non-empty string", ErrInvalidPatch)
+			}
+			params.Title = title
+		case "description":
+			params.Description, err = patchString(field, raw)
+		case "source_url":
+			params.SourceUrl, err = patchString(field, raw)
+		case "servings":
+			params.Servings, err = patchInt32(field, raw)
+		case "prep_minutes":
+			params.PrepMinutes, err = patchInt32(field, raw)
+		case "cook_minutes":
+			params.CookMinutes, err = patchInt32(field, raw)
+		case "visibility":
+			if isNull(raw) || json.Unmarshal(raw, &params.Visibility) != nil || params.Visibility == "" {
+				return db.Recipe{}, fmt.Errorf("%w: %w", ErrInvalidPatch, ErrInvalidVisibility)
+			}
+			if err = ValidateVisibility(params.Visibility); err != nil {
+				err = fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+			}
+		case "tags":
+			params.Tags = nil
+			if !isNull(raw) && json.Unmarshal(raw, &params.Tags) != nil {
+				err = fmt.Errorf("%w: tags must be an array of strings", ErrInvalidPatch)
+			}
+		case "steps", "ingredients":
+			err = fmt.Errorf("%w: %s are edited through /recipes/{id}/%s", ErrInvalidPatch, field, field)
+		default:
+			err = fmt.Errorf("%w: unknown field %q", ErrInvalidPatch, field)
+		}
+		if err != nil {
+			return db.Recipe{}, err
+		}
+	}
+	if params.Tags == nil {
+		params.Tags = []string{}
+	}
+
+	recipe, err := q.UpdateRecipe(ctx, params)
+	if err != nil {
+		return db.Recipe{}, fmt.Errorf("update recipe: %w", err)
+	}
+	if err := s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, id); err != nil {
+		return db.Recipe{}, err
+	}
+	return recipe, nil
+}
+
+// InsertStep adds a step at position, moving the step already there and
+// every later one down by one. A position of 0 appends the step.
+func (s *Service) InsertStep(
+	ctx context.Context,
+	q db.Querier,
+	recipeID uuid.UUID,
+	position int,
+	instruction string,
+) (db.Recipe, error) {
+	if instruction == "" {
+		return db.Recipe{}, fmt.Errorf("%w: instruction is required", ErrInvalidPatch)
+	}
+	recipe, err := touchRecipe(ctx, q, recipeID)
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	last, err := q.GetMaxStepNumber(ctx, recipeID)
+	if err != nil {
+		return db.Recipe{}, fmt.Errorf("get last step: %w", err)
+	}
+	if position == 0 {
+		position = int(last) + 1
+	}
+	stepNumber, ok := toInt32(position)
+	if !ok || stepNumber < 1 || stepNumber > last+1 {
+		return db.Recipe{}, fmt.Errorf("%w: step_number must be between 1 and %d", ErrInvalidPatch, last+1)
+	}
+
+	if err := q.ShiftStepsFrom(ctx, db.ShiftStepsFromParams{
+		Delta:    1,
+		RecipeID: recipeID,
+		FromStep: stepNumber,
+	}); err != nil {
+		return db.Recipe{}, fmt.Errorf("shift steps: %w", err)
+	}
+	if _, err := q.CreateStep(ctx, db.CreateStepParams{
+		RecipeID:    recipeID,
+		StepNumber:  stepNumber,
+		Instruction: instruction,
+	}); err != nil {
+		return db.Recipe{}, fmt.Errorf("create step: %w", err)
+	}
+	return recipe, s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, recipeID)
+}
+
+// PatchStep applies a merge patch to step n. "instruction" rewrites the step
+// and "step_number" moves it, shifting the steps in between to close the gap.
+//
+//nolint:gocognit // One case per patchable field.
+func (s *Service) PatchStep(
+	ctx context.Context,
+	q db.Querier,
+	recipeID uuid.UUID,
+	n int,
+	patch Patch,
+) (db.Recipe, error) {
+	recipe, err := touchRecipe(ctx, q, recipeID)
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	from, ok := toInt32(n)
+	if !ok {
+		return db.Recipe{}, ErrStepNotFound
+	}
+	if _, err := q.GetStep(ctx, db.GetStepParams{RecipeID: recipeID, StepNumber: from}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Recipe{}, ErrStepNotFound
+		}
+		return db.Recipe{}, fmt.Errorf("get step: %w", err)
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(patch)) {
+		raw := patch[field]
+		switch field {
+		case "instruction":
+			var instruction string
+			if isNull(raw) || json.Unmarshal(raw, &instruction) != nil || instruction == "" {
+				return db.Recipe{}, fmt.Errorf("%w: instruction must be a non-empty string", ErrInvalidPatch)
+			}
+			if _, err := q.UpdateStepInstruction(ctx, db.UpdateStepInstructionParams{
+				RecipeID:    recipeID,
+				StepNumber:  from,
+				Instruction: instruction,
+			}); err != nil {
+				return db.Recipe{}, fmt.Errorf("update step: %w", err)
+			}
+		case "step_number":
+		default:
+			return db.Recipe{}, fmt.Errorf("%w: unknown field %q", ErrInvalidPatch, field)
+		}
+	}
+
+	if raw, ok := patch["step_number"]; ok {
+		last, err := q.GetMaxStepNumber(ctx, recipeID)
+		if err != nil {
+			return db.Recipe{}, fmt.Errorf("get last step: %w", err)
+		}
+		var to int32
+		if isNull(raw) || json.Unmarshal(raw, &to) != nil || to < 1 || to > last {
+			return db.Recipe{}, fmt.Errorf("%w: step_number must be between 1 and %d", ErrInvalidPatch, last)
+		}
+		if err := q.MoveStep(ctx, db.MoveStepParams{FromStep: from, ToStep: to, RecipeID: recipeID}); err != nil {
+			return db.Recipe{}, fmt.Errorf("move step: %w", err)
+		}
+	}
+	return recipe, s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, recipeID)
+}
+
+// DeleteStep removes step n and moves every later step up by one.
+func (s *Service) DeleteStep(ctx context.Context, q db.Querier, recipeID uuid.UUID, n int) (db.Recipe, error) {
+	recipe, err := touchRecipe(ctx, q, recipeID)
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	stepNumber, ok := toInt32(n)
+	if !ok {
+		return db.Recipe{}, ErrStepNotFound
+	}
+	deleted, err := q.DeleteStep(ctx, db.DeleteStepParams{RecipeID: recipeID, StepNumber: stepNumber})
+	if err != nil {
+		return db.Recipe{}, fmt.Errorf("delete step: %w", err)
+	}
+	if deleted == 0 {
+		return db.Recipe{}, ErrStepNotFound
+	}
+	if err := q.ShiftStepsFrom(ctx, db.ShiftStepsFromParams{
+		Delta:    -1,
+		RecipeID: recipeID,
+		FromStep: stepNumber + 1,
+	}); err != nil {
+		return db.Recipe{}, fmt.Errorf("shift steps: %w", err)
+	}
+	return recipe, s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, recipeID)
+}
+
+// AddIngredient adds an ingredient row to the recipe.
+func (s *Service) AddIngredient(
+	ctx context.Context,
+	q db.Querier,
+	params db.CreateRecipeIngredientParams,
+) (db.Recipe, error) {
+	recipe, err := touchRecipe(ctx, q, params.RecipeID)
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	if _, err := q.CreateRecipeIngredient(ctx, params); err != nil {
+		return db.Recipe{}, fmt.Errorf("create recipe ingredient: %w", err)
+	}
+	return recipe, s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, params.RecipeID)
+}
+
+// PatchIngredient applies a merge patch to one ingredient row of the recipe.
+func (s *Service) PatchIngredient(
+	ctx context.Context,
+	q db.Querier,
+	recipeID, rowID uuid.UUID,
+	patch Patch,
+) (db.Recipe, error) {
+	recipe, err := touchRecipe(ctx, q, recipeID)
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	current, err := q.GetRecipeIngredient(ctx, db.GetRecipeIngredientParams{RecipeID: recipeID, ID: rowID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Recipe{}, ErrIngredientNotFound
+		}
+		return db.Recipe{}, fmt.Errorf("get recipe ingredient: %w", err)
+	}
+	params := db.UpdateRecipeIngredientParams{
+		RecipeID:         recipeID,
+		ID:               rowID,
+		IngredientID:     current.IngredientID,
+		Quantity:         current.Quantity,
+		Unit:             current.Unit,
+		IsOptional:       current.IsOptional,
+		PreparationNotes: current.PreparationNotes,
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(patch)) {
+		raw := patch[field]
+		switch field {
+		case "ingredient_id":
+			if isNull(raw) || json.Unmarshal(raw, &params.IngredientID) != nil {
+				err = fmt.Errorf("%w: ingredient_id must be a UUID", ErrInvalidPatch)
+			}
+		case "quantity":
+			var quantity float64
+			if !isNull(raw) && json.Unmarshal(raw, &quantity) != nil {
+				err = fmt.Errorf("%w: quantity must be a number", ErrInvalidPatch)
+			}
+			params.Quantity = nullFloat64(quantity)
+		case "unit":
+			params.Unit, err = patchString(field, raw)
+		case "preparation_notes":
+			params.PreparationNotes, err = patchString(field, raw)
+		case "is_optional":
+			params.IsOptional = false
+			if !isNull(raw) && json.Unmarshal(raw, &params.IsOptional) != nil {
+				err = fmt.Errorf("%w: is_optional must be a boolean", ErrInvalidPatch)
+			}
+		default:
+			err = fmt.Errorf("%w: unknown field %q", ErrInvalidPatch, field)
+		}
+		if err != nil {
+			return db.Recipe{}, err
+		}
+	}
+
+	if _, err := q.UpdateRecipeIngredient(ctx, params); err != nil {
+		return db.Recipe{}, fmt.Errorf("update recipe ingredient: %w", err)
+	}
+	return recipe, s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, recipeID)
+}
+
+// DeleteIngredient removes one ingredient row from the recipe.
+func (s *Service) DeleteIngredient(ctx context.Context, q db.Querier, recipeID, rowID uuid.UUID) (db.Recipe, error) {
+	recipe, err := touchRecipe(ctx, q, recipeID)
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	deleted, err := q.DeleteRecipeIngredient(ctx, db.DeleteRecipeIngredientParams{RecipeID: recipeID, ID: rowID})
+	if err != nil {
+		return db.Recipe{}, fmt.Errorf("delete recipe ingredient: %w", err)
+	}
+	if deleted == 0 {
+		return db.Recipe{}, ErrIngredientNotFound
+	}
+	return recipe, s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, recipeID)
+}
+
+// touchRecipe bumps the version of a recipe owned by the caller's household.
+func touchRecipe(ctx context.Context, q db.Querier, id uuid.UUID) (db.Recipe, error) {
+	return q.TouchRecipe(ctx, db.TouchRecipeParams{
+		ID:          id,
+		HouseholdID: tenant.FromContext(ctx),
+		UpdatedBy:   actor(ctx),
+	})
+}
+
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// patchString decodes a nullable text member. Null and "" both clear the
+// field, matching how create and update store empty strings.
+func patchString(field string, raw json.RawMessage) (sql.NullString, error) {
+	if isNull(raw) {
+		return sql.NullString{}, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return sql.NullString{}, fmt.Errorf("%w: %s must be a string", ErrInvalidPatch, field)
+	}
+	return nullString(s), nil
+}
+
+// patchInt32 decodes a nullable integer member. Null and 0 both clear the
+// field, matching how create and update store zero values.
+func patchInt32(field string, raw json.RawMessage) (sql.NullInt32, error) {
+	if isNull(raw) {
+		return sql.NullInt32{}, nil
+	}
+	var n int32
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return sql.NullInt32{}, fmt.Errorf("%w: %s must be an integer", ErrInvalidPatch, field)
+	}
+	return nullInt32(int(n)), nil
+}
@@ -0,0 +1,219 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/auth"
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// expectUpdatedEvent mocks the reads and writes of recording recipe.updated.
+func expectUpdatedEvent(mockQ *mocks.MockQuerier, recipeID uuid.UUID) {
+	mockQ.EXPECT().GetRecipe(mock.Anything, mock.MatchedBy(func(p db.GetRecipeParams) bool {
+		return p.ID == recipeID
+	})).Return(db.Recipe{ID: recipeID, Title: "Soup"}, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, mock.MatchedBy(func(p db.ListStepsByRecipeParams) bool {
+		return p.RecipeID == recipeID
+	})).Return(nil, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, mock.MatchedBy(func(p db.ListIngredientsByRecipeParams) bool {
+		return p.RecipeID == recipeID
+	})).Return(nil, nil)
+	mockQ.EXPECT().InsertRecipeRevision(mock.Anything, mock.Anything).Return(db.RecipeRevision{}, nil)
+	mockQ.EXPECT().InsertOutboxEvent(mock.Anything, mock.Anything).Return(db.EventOutbox{}, nil)
+}
+
+func TestPatchRecipe(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	recipeID, household := uuid.New(), uuid.New()
+	current := db.Recipe{
+		ID:          recipeID,
+		HouseholdID: household,
+		Visibility:  service.VisibilityPrivate,
+		Title:       "Soup",
+		Description: sql.NullString{String: "Warming", Valid: true},
+		Servings:    sql.NullInt32{Int32: 4, Valid: true},
+		CookMinutes: sql.NullInt32{Int32: 30, Valid: true},
+		Tags:        []string{"dinner"},
+	}
+	mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: recipeID, HouseholdID: household}).
+		Return(current, nil)
+	mockQ.EXPECT().UpdateRecipe(mock.Anything, db.UpdateRecipeParams{
+		ID:          recipeID,
+		HouseholdID: household,
+		Visibility:  service.VisibilityPublic,
+		Title:       "Soup",
+		Servings:    sql.NullInt32{Int32: 6, Valid: true},
+		CookMinutes: sql.NullInt32{Int32: 30, Valid: true},
+		Tags:        []string{"soup", "winter"},
+		UpdatedBy:   sql.NullString{String: "alice", Valid: true},
+	}).Return(db.Recipe{ID: recipeID, Title: "Soup", Version: 2}, nil)
+	expectUpdatedEvent(mockQ, recipeID)
+
+	ctx := tenant.WithHousehold(context.Background(), household)
+	ctx = auth.WithPrincipal(ctx, auth.Principal{Subject: "alice", Roles: []auth.Role{auth.RoleEditor}})
+	got, err := svc.PatchRecipe(ctx, mockQ, recipeID, service.Patch{
+		"description": json.RawMessage(`null`),
+		"servings":    json.RawMessage(`6`),
+		"tags":        json.RawMessage(`["soup", "winter"]`),
+		"visibility":  json.RawMessage(`"public"`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), got.Version)
+}
+
+func TestPatchRecipe_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]service.Patch{
+		"null title":    {"title": json.RawMessage(`null`)},
+		"wrong type":    {"servings": json.RawMessage(`"four"`)},
+		"steps":         {"steps": json.RawMessage(`[]`)},
+		"unknown field": {"colour": json.RawMessage(`"red"`)},
+		"visibility":    {"visibility": json.RawMessage(`"everyone"`)},
+	}
+	for name, patch := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockQ := mocks.NewMockQuerier(t)
+			svc := service.New(mockQ, nil, nil, nil)
+			recipeID := uuid.New()
+			mockQ.EXPECT().LockRecipe(mock.Anything, db.LockRecipeParams{ID: recipeID}).
+				Return(db.Recipe{ID: recipeID, Title: "Soup"}, nil)
+
+			_, err := svc.PatchRecipe(context.Background(), mockQ, recipeID, patch)
+			require.ErrorIs(t, err, service.ErrInvalidPatch)
+		})
+	}
+}
+
+func TestInsertStep_ShiftsLaterSteps(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	recipeID := uuid.New()
+	mockQ.EXPECT().TouchRecipe(mock.Anything, db.TouchRecipeParams{ID: recipeID}).
+		Return(db.Recipe{ID: recipeID, Version: 5}, nil)
+	mockQ.EXPECT().GetMaxStepNumber(mock.Anything, recipeID).Return(3, nil)
+	shift := mockQ.EXPECT().ShiftStepsFrom(mock.Anything, db.ShiftStepsFromParams{
+		Delta:    1,
+		RecipeID: recipeID,
+		FromStep: 2,
+	}).Return(nil).Call
+	mockQ.EXPECT().CreateStep(mock.Anything, db.CreateStepParams{
+		RecipeID:    recipeID,
+		StepNumber:  2,
+		Instruction: "Season.",
+	}).Return(db.RecipeStep{}, nil).NotBefore(shift)
+	expectUpdatedEvent(mockQ, recipeID)
+
+	got, err := svc.InsertStep(context.Background(), mockQ, recipeID, 2, "Season.")
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), got.Version)
+}
+
+func TestInsertStep_OutOfRange(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	recipeID := uuid.New()
+	mockQ.EXPECT().TouchRecipe(mock.Anything, db.TouchRecipeParams{ID: recipeID}).Return(db.Recipe{ID: recipeID}, nil)
+	mockQ.EXPECT().GetMaxStepNumber(mock.Anything, recipeID).Return(3, nil)
+
+	_, err := svc.InsertStep(context.Background(), mockQ, recipeID, 5, "Season.")
+	require.ErrorIs(t, err, service.ErrInvalidPatch)
+}
+
+func TestPatchStep_EditsAndMoves(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	recipeID := uuid.New()
+	mockQ.EXPECT().TouchRecipe(mock.Anything, db.TouchRecipeParams{ID: recipeID}).Return(db.Recipe{ID: recipeID}, nil)
+	mockQ.EXPECT().GetStep(mock.Anything, db.GetStepParams{RecipeID: recipeID, StepNumber: 3}).
+		Return(db.RecipeStep{StepNumber: 3}, nil)
+	mockQ.EXPECT().UpdateStepInstruction(mock.Anything, db.UpdateStepInstructionParams{
+		RecipeID:    recipeID,
+		StepNumber:  3,
+		Instruction: "Serve hot.",
+	}).Return(db.RecipeStep{}, nil)
+	mockQ.EXPECT().GetMaxStepNumber(mock.Anything, recipeID).Return(4, nil)
+	mockQ.EXPECT().MoveStep(mock.Anything, db.MoveStepParams{FromStep: 3, ToStep: 1, RecipeID: recipeID}).
+		Return(nil)
+	expectUpdatedEvent(mockQ, recipeID)
+
+	_, err := svc.PatchStep(context.Background(), mockQ, recipeID, 3, service.Patch{
+		"instruction": json.RawMessage(`"Serve hot."`),
+		"step_number": json.RawMessage(`1`),
+	})
+	require.NoError(t, err)
+}
+
+func TestDeleteStep_NotFound(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	recipeID := uuid.New()
+	mockQ.EXPECT().TouchRecipe(mock.Anything, db.TouchRecipeParams{ID: recipeID}).Return(db.Recipe{ID: recipeID}, nil)
+	mockQ.EXPECT().DeleteStep(mock.Anything, db.DeleteStepParams{RecipeID: recipeID, StepNumber: 7}).
+		Return(0, nil)
+
+	_, err := svc.DeleteStep(context.Background(), mockQ, recipeID, 7)
+	require.ErrorIs(t, err, service.ErrStepNotFound)
+}
+
+func TestPatchIngredient(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	recipeID, rowID, salt := uuid.New(), uuid.New(), uuid.New()
+	mockQ.EXPECT().TouchRecipe(mock.Anything, db.TouchRecipeParams{ID: recipeID}).Return(db.Recipe{ID: recipeID}, nil)
+	mockQ.EXPECT().GetRecipeIngredient(mock.Anything, db.GetRecipeIngredientParams{RecipeID: recipeID, ID: rowID}).
+		Return(db.RecipeIngredient{
+			ID:           rowID,
+			RecipeID:     recipeID,
+			IngredientID: salt,
+			Quantity:     sql.NullFloat64{Float64: 1, Valid: true},
+			Unit:         sql.NullString{String: "tsp", Valid: true},
+		}, nil)
+	mockQ.EXPECT().UpdateRecipeIngredient(mock.Anything, db.UpdateRecipeIngredientParams{
+		RecipeID:     recipeID,
+		ID:           rowID,
+		IngredientID: salt,
+		Quantity:     sql.NullFloat64{Float64: 2, Valid: true},
+		IsOptional:   true,
+	}).Return(db.RecipeIngredient{}, nil)
+	expectUpdatedEvent(mockQ, recipeID)
+
+	_, err := svc.PatchIngredient(context.Background(), mockQ, recipeID, rowID, service.Patch{
+		"quantity":    json.RawMessage(`2`),
+		"unit":        json.RawMessage(`null`),
+		"is_optional": json.RawMessage(`true`),
+	})
+	require.NoError(t, err)
+}
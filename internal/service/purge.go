@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+const (
+	// DefaultTrashRetention is how long a deleted recipe stays in the trash
+	// before TrashPurger removes it for good.
+	DefaultTrashRetention = 30 * 24 * time.Hour
+
+	defaultPurgeInterval = time.Hour
+)
+
+// TrashRecipe moves one of the caller's recipes to the trash through q and
+// enqueues recipe.deleted. The event is recorded first so its payload
+// captures the recipe as it was; this also surfaces sql.ErrNoRows for
+// unknown IDs.
+func (s *Service) TrashRecipe(ctx context.Context, q db.Querier, id uuid.UUID) error {
+	if err := s.EnqueueRecipeEvent(ctx, q, events.RecipeDeletedEventType, id); err != nil {
+		return err
+	}
+	if _, err := q.SoftDeleteRecipe(ctx, db.SoftDeleteRecipeParams{
+		ID:          id,
+		HouseholdID: tenant.FromContext(ctx),
+		UpdatedBy:   actor(ctx),
+	}); err != nil {
+		return fmt.Errorf("trash recipe: %w", err)
+	}
+	return nil
+}
+
+// TrashPurger hard-deletes recipes that have been in the trash for longer
+// than the retention period. Steps, ingredients and revisions go with them.
+// The recipe.deleted event was already emitted when the recipe was trashed.
+type TrashPurger struct {
+	q         db.Querier
+	logger    *slog.Logger
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewTrashPurger returns a purger; a retention of zero uses
+// DefaultTrashRetention.
+func NewTrashPurger(q db.Querier, retention time.Duration, logger *slog.Logger) (*TrashPurger, error) {
+	if q == nil {
+		return nil, errors.New("querier is required")
+	}
+	if logger == nil {
+		return nil, errors.New("logger is required")
+	}
+	if retention < 0 {
+		return nil, errors.New("retention must not be negative")
+	}
+	if retention == 0 {
+		retention = DefaultTrashRetention
+	}
+
+	return &TrashPurger{
+		q:         q,
+		logger:    logger,
+		retention: retention,
+		interval:  defaultPurgeInterval,
+	}, nil
+}
+
+// Run purges expired recipes every interval until ctx is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) error {
+	p.logger.InfoContext(ctx, "trash purger started", "retention", p.retention, "interval", p.interval)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.PurgeExpired(context.WithoutCancel(ctx)); err != nil {
+			p.logger.ErrorContext(ctx, "trash purge failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			p.logger.InfoContext(ctx, "trash purger stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired hard-deletes recipes trashed before the retention cutoff and
+// returns how many were removed.
+func (p *TrashPurger) PurgeExpired(ctx context.Context) (int64, error) {
+	purged, err := p.q.PurgeDeletedRecipes(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted recipes: %w", err)
+	}
+	if purged > 0 {
+		p.logger.InfoContext(ctx, "purged trashed recipes", "count", purged)
+	}
+	return purged, nil
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestTrashPurger_PurgeExpired(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	purger, err := service.NewTrashPurger(mockQ, 48*time.Hour, slog.Default())
+	require.NoError(t, err)
+
+	before := time.Now().Add(-48 * time.Hour)
+	mockQ.EXPECT().PurgeDeletedRecipes(mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return !cutoff.Before(before) && cutoff.Before(time.Now().Add(-47*time.Hour))
+	})).Return(3, nil)
+
+	purged, err := purger.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
+
+func TestTrashPurger_PurgeError(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	purger, err := service.NewTrashPurger(mockQ, 0, slog.Default())
+	require.NoError(t, err)
+
+	mockQ.EXPECT().PurgeDeletedRecipes(mock.Anything, mock.Anything).Return(0, errors.New("boom"))
+
+	_, err = purger.PurgeExpired(context.Background())
+	require.Error(t, err)
+}
+
+func TestNewTrashPurger_NegativeRetention(t *testing.T) {
+	t.Parallel()
+
+	_, err := service.NewTrashPurger(mocks.NewMockQuerier(t), -time.Hour, slog.Default())
+	require.Error(t, err)
+}
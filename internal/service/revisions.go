@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// AuditActionRevert is the recipe_audit_log action recorded by
+// RevertRecipe.
+const AuditActionRevert = "revert"
+
+// Diff change kinds.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// ErrRevisionNotFound is returned for a revision number the recipe does not
+// have.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// RecipeRevision is one snapshot in a recipe's history. Revision 1 is the
+// recipe as created; each update adds the next number.
+type RecipeRevision struct {
+	Revision  int                   `json:"revision"`
+	CreatedAt time.Time             `json:"created_at"`
+	Recipe    *events.RecipePayload `json:"recipe"`
+}
+
+// FieldChange is a changed scalar field.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// StepChange is an added, removed or modified step, keyed by step number.
+type StepChange struct {
+	StepNumber int32  `json:"step_number"`
+	Change     string `json:"change"`
+	From       string `json:"from,omitempty"`
+	To         string `json:"to,omitempty"`
+}
+
+// IngredientChange is an added, removed or modified ingredient, keyed by
+// ingredient ID.
+type IngredientChange struct {
+	IngredientID uuid.UUID                       `json:"ingredient_id"`
+	Change       string                          `json:"change"`
+	From         *events.RecipeIngredientPayload `json:"from,omitempty"`
+	To           *events.RecipeIngredientPayload `json:"to,omitempty"`
+}
+
+// RevisionDiff describes how revision To differs from revision From.
+type RevisionDiff struct {
+	From        int                `json:"from"`
+	To          int                `json:"to"`
+	Fields      []FieldChange      `json:"fields"`
+	TagsAdded   []string           `json:"tags_added"`
+	TagsRemoved []string           `json:"tags_removed"`
+	Steps       []StepChange       `json:"steps"`
+	Ingredients []IngredientChange `json:"ingredients"`
+}
+
+func recordRevision(ctx context.Context, q db.Querier, payload *events.RecipePayload) (db.RecipeRevision, error) {
+	snapshot, err := json.Marshal(payload)
+	if err != nil {
+		return db.RecipeRevision{}, fmt.Errorf("marshal revision: %w", err)
+	}
+	rev, err := q.InsertRecipeRevision(ctx, db.InsertRecipeRevisionParams{
+		RecipeID:    payload.ID,
+		Snapshot:    snapshot,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return db.RecipeRevision{}, fmt.Errorf("insert revision: %w", err)
+	}
+	return rev, nil
+}
+
+func toRecipeRevision(row db.RecipeRevision) (RecipeRevision, error) {
+	var payload events.RecipePayload
+	if err := json.Unmarshal(row.Snapshot, &payload); err != nil {
+		return RecipeRevision{}, fmt.Errorf("decode revision %d: %w", row.Revision, err)
+	}
+	return RecipeRevision{
+		Revision:  int(row.Revision),
+		CreatedAt: row.CreatedAt,
+		Recipe:    &payload,
+	}, nil
+}
+
+// ListRevisions returns a recipe's revisions, newest first.
+func (s *Service) ListRevisions(ctx context.Context, recipeID uuid.UUID) ([]RecipeRevision, error) {
+	rows, err := s.q.ListRecipeRevisions(ctx, db.ListRecipeRevisionsParams{
+		RecipeID:    recipeID,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list revisions: %w", err)
+	}
+	revisions := make([]RecipeRevision, 0, len(rows))
+	for _, row := range rows {
+		rev, err := toRecipeRevision(row)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
+
+// GetRevision returns revision n of a recipe.
+func (s *Service) GetRevision(ctx context.Context, recipeID uuid.UUID, n int) (RecipeRevision, error) {
+	return getRevision(ctx, s.q, recipeID, n)
+}
+
+func getRevision(ctx context.Context, q db.Querier, recipeID uuid.UUID, n int) (RecipeRevision, error) {
+	revision, ok := toInt32(n)
+	if !ok || n < 1 {
+		return RecipeRevision{}, ErrRevisionNotFound
+	}
+	row, err := q.GetRecipeRevision(ctx, db.GetRecipeRevisionParams{
+		RecipeID:    recipeID,
+		Revision:    revision,
+		HouseholdID: tenant.FromContext(ctx),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return RecipeRevision{}, ErrRevisionNotFound
+	}
+	if err != nil {
+		return RecipeRevision{}, fmt.Errorf("get revision: %w", err)
+	}
+	return toRecipeRevision(row)
+}
+
+// DiffRevisions compares two revisions of a recipe.
+func (s *Service) DiffRevisions(ctx context.Context, recipeID uuid.UUID, from, to int) (RevisionDiff, error) {
+	a, err := s.GetRevision(ctx, recipeID, from)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	b, err := s.GetRevision(ctx, recipeID, to)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	diff := DiffRecipes(a.Recipe, b.Recipe)
+	diff.From, diff.To = from, to
+	return diff, nil
+}
+
+// DiffRecipes reports field, tag, step and ingredient changes from a to b.
+func DiffRecipes(a, b *events.RecipePayload) RevisionDiff {
+	diff := RevisionDiff{
+		Fields:      []FieldChange{},
+		TagsAdded:   difference(b.Tags, a.Tags),
+		TagsRemoved: difference(a.Tags, b.Tags),
+		Steps:       []StepChange{},
+		Ingredients: []IngredientChange{},
+	}
+
+	for _, f := range []struct {
+		name     string
+		from, to any
+	}{
+		{"title", a.Title, b.Title},
+		{"description", a.Description, b.Description},
+		{"source_url", a.SourceURL, b.SourceURL},
+		{"servings", a.Servings, b.Servings},
+		{"prep_minutes", a.PrepMinutes, b.PrepMinutes},
+		{"cook_minutes", a.CookMinutes, b.CookMinutes},
+	} {
+		if f.from != f.to {
+			diff.Fields = append(diff.Fields, FieldChange{Field: f.name, From: f.from, To: f.to})
+		}
+	}
+
+	diff.Steps = diffSteps(a.Steps, b.Steps)
+	diff.Ingredients = diffIngredients(a.Ingredients, b.Ingredients)
+	return diff
+}
+
+func diffSteps(a, b []events.RecipeStepPayload) []StepChange {
+	changes := []StepChange{}
+	before := map[int32]string{}
+	for _, step := range a {
+		before[step.StepNumber] = step.Instruction
+	}
+	after := map[int32]bool{}
+	for _, step := range b {
+		after[step.StepNumber] = true
+		old, ok := before[step.StepNumber]
+		switch {
+		case !ok:
+			changes = append(changes, StepChange{StepNumber: step.StepNumber, Change: ChangeAdded, To: step.Instruction})
+		case old != step.Instruction:
+			changes = append(changes, StepChange{
+				StepNumber: step.StepNumber,
+				Change:     ChangeModified,
+				From:       old,
+				To:         step.Instruction,
+			})
+		}
+	}
+	for _, step := range a {
+		if !after[step.StepNumber] {
+			changes = append(changes, StepChange{
+				StepNumber: step.StepNumber,
+				Change:     ChangeRemoved,
+				From:       step.Instruction,
+			})
+		}
+	}
+	return changes
+}
+
+// diffIngredients matches ingredients by ingredient ID, ignoring row IDs,
+// which change on every rewrite.
+func diffIngredients(a, b []events.RecipeIngredientPayload) []IngredientChange {
+	changes := []IngredientChange{}
+	key := func(list []events.RecipeIngredientPayload) (map[string]events.RecipeIngredientPayload, []string) {
+		byKey := map[string]events.RecipeIngredientPayload{}
+		order := make([]string, 0, len(list))
+		seen := map[uuid.UUID]int{}
+		for _, ing := range list {
+			k := ing.IngredientID.String() + "#" + strconv.Itoa(seen[ing.IngredientID])
+			seen[ing.IngredientID]++
+			ing.ID = uuid.Nil
+			byKey[k] = ing
+			order = append(order, k)
+		}
+		return byKey, order
+	}
+	before, beforeOrder := key(a)
+	after, afterOrder := key(b)
+
+	for _, k := range afterOrder {
+		to := after[k]
+		from, ok := before[k]
+		switch {
+		case !ok:
+			changes = append(changes, IngredientChange{IngredientID: to.IngredientID, Change: ChangeAdded, To: &to})
+		case !reflect.DeepEqual(from, to):
+			changes = append(changes, IngredientChange{
+				IngredientID: to.IngredientID,
+				Change:       ChangeModified,
+				From:         &from,
+				To:           &to,
+			})
+		}
+	}
+	for _, k := range beforeOrder {
+		if _, ok := after[k]; !ok {
+			from := before[k]
+			changes = append(changes, IngredientChange{IngredientID: from.IngredientID, Change: ChangeRemoved, From: &from})
+		}
+	}
+	return changes
+}
+
+// difference returns the elements of a not in b.
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	out := []string{}
+	for _, s := range a {
+		if !in[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// RevertRecipe restores revision n as the recipe's current state. The revert
+// is itself an update, so it adds a new revision rather than discarding the
+// ones after n.
+func (s *Service) RevertRecipe(ctx context.Context, recipeID uuid.UUID, n int) (db.Recipe, error) {
+	var recipe db.Recipe
+	err := s.WithTx(ctx, func(q db.Querier) error {
+		rev, err := getRevision(ctx, q, recipeID, n)
+		if err != nil {
+			return err
+		}
+		snap := rev.Recipe
+		if snap.Tags == nil {
+			snap.Tags = []string{}
+		}
+
+		recipe, err = q.UpdateRecipe(ctx, db.UpdateRecipeParams{
+			ID:          recipeID,
+			HouseholdID: tenant.FromContext(ctx),
+			Title:       snap.Title,
+			Description: nullString(snap.Description),
+			SourceUrl:   nullString(snap.SourceURL),
+			Servings:    nullInt32(int(snap.Servings)),
+			PrepMinutes: nullInt32(int(snap.PrepMinutes)),
+			CookMinutes: nullInt32(int(snap.CookMinutes)),
+			Tags:        snap.Tags,
+			UpdatedBy:   actor(ctx),
+		})
+		if err != nil {
+			return fmt.Errorf("update recipe: %w", err)
+		}
+
+		if err := q.DeleteStepsByRecipe(ctx, recipeID); err != nil {
+			return fmt.Errorf("clear steps: %w", err)
+		}
+		for _, step := range snap.Steps {
+			if _, err := q.CreateStep(ctx, db.CreateStepParams{
+				RecipeID:    recipeID,
+				StepNumber:  step.StepNumber,
+				Instruction: step.Instruction,
+			}); err != nil {
+				return fmt.Errorf("restore step %d: %w", step.StepNumber, err)
+			}
+		}
+
+		if err := q.DeleteIngredientsByRecipe(ctx, recipeID); err != nil {
+			return fmt.Errorf("clear ingredients: %w", err)
+		}
+		for _, ing := range snap.Ingredients {
+			if _, err := q.CreateRecipeIngredient(ctx, db.CreateRecipeIngredientParams{
+				RecipeID:         recipeID,
+				IngredientID:     ing.IngredientID,
+				Quantity:         nullFloat64(ing.Quantity),
+				Unit:             nullString(ing.Unit),
+				IsOptional:       ing.IsOptional,
+				PreparationNotes: nullString(ing.PreparationNotes),
+			}); err != nil {
+				return fmt.Errorf("restore ingredient: %w", err)
+			}
+		}
+
+		if err := s.EnqueueRecipeEvent(ctx, q, events.RecipeUpdatedEventType, recipeID); err != nil {
+			return err
+		}
+
+		details, err := json.Marshal(map[string]int{"revision": n})
+		if err != nil {
+			return fmt.Errorf("marshal audit details: %w", err)
+		}
+		if _, err := q.InsertRecipeAuditLog(ctx, db.InsertRecipeAuditLogParams{
+			RecipeID: recipeID,
+			Action:   AuditActionRevert,
+			Details:  details,
+		}); err != nil {
+			return fmt.Errorf("insert audit log: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return db.Recipe{}, err
+	}
+	return recipe, nil
+}
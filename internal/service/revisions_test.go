@@ -0,0 +1,157 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/events"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestDiffRecipes(t *testing.T) {
+	t.Parallel()
+
+	garlic, pasta, oil := uuid.New(), uuid.New(), uuid.New()
+	before := &events.RecipePayload{
+		Title:    "Pasta",
+		Servings: 2,
+		Tags:     []string{"dinner", "quick"},
+		Steps: []events.RecipeStepPayload{
+			{StepNumber: 1, Instruction: "Boil pasta."},
+			{StepNumber: 2, Instruction: "Fry garlic."},
+			{StepNumber: 3, Instruction: "Serve."},
+		},
+		Ingredients: []events.RecipeIngredientPayload{
+			{ID: uuid.New(), IngredientID: pasta, Quantity: 200, Unit: "g"},
+			{ID: uuid.New(), IngredientID: garlic, Quantity: 2},
+		},
+	}
+	after := &events.RecipePayload{
+		Title:    "Garlic Pasta",
+		Servings: 2,
+		Tags:     []string{"dinner", "italian"},
+		Steps: []events.RecipeStepPayload{
+			{StepNumber: 1, Instruction: "Boil pasta."},
+			{StepNumber: 2, Instruction: "Fry the garlic in oil."},
+		},
+		Ingredients: []events.RecipeIngredientPayload{
+			{ID: uuid.New(), IngredientID: pasta, Quantity: 250, Unit: "g"},
+			{ID: uuid.New(), IngredientID: oil},
+		},
+	}
+
+	diff := service.DiffRecipes(before, after)
+
+	assert.Equal(t, []service.FieldChange{{Field: "title", From: "Pasta", To: "Garlic Pasta"}}, diff.Fields)
+	assert.Equal(t, []string{"italian"}, diff.TagsAdded)
+	assert.Equal(t, []string{"quick"}, diff.TagsRemoved)
+	assert.Equal(t, []service.StepChange{
+		{StepNumber: 2, Change: service.ChangeModified, From: "Fry garlic.", To: "Fry the garlic in oil."},
+		{StepNumber: 3, Change: service.ChangeRemoved, From: "Serve."},
+	}, diff.Steps)
+
+	require.Len(t, diff.Ingredients, 3)
+	assert.Equal(t, service.ChangeModified, diff.Ingredients[0].Change)
+	assert.Equal(t, pasta, diff.Ingredients[0].IngredientID)
+	assert.InDelta(t, 250, diff.Ingredients[0].To.Quantity, 0)
+	assert.Equal(t, service.ChangeAdded, diff.Ingredients[1].Change)
+	assert.Equal(t, oil, diff.Ingredients[1].IngredientID)
+	assert.Equal(t, service.ChangeRemoved, diff.Ingredients[2].Change)
+	assert.Equal(t, garlic, diff.Ingredients[2].IngredientID)
+}
+
+func TestDiffRecipes_IgnoresRowIDs(t *testing.T) {
+	t.Parallel()
+
+	salt := uuid.New()
+	a := &events.RecipePayload{Ingredients: []events.RecipeIngredientPayload{{ID: uuid.New(), IngredientID: salt}}}
+	b := &events.RecipePayload{Ingredients: []events.RecipeIngredientPayload{{ID: uuid.New(), IngredientID: salt}}}
+
+	diff := service.DiffRecipes(a, b)
+	assert.Empty(t, diff.Fields)
+	assert.Empty(t, diff.Steps)
+	assert.Empty(t, diff.Ingredients)
+}
+
+func TestRevertRecipe(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetTransactor(transactor(t, mockQ))
+
+	recipeID, salt := uuid.New(), uuid.New()
+	snapshot, err := json.Marshal(events.RecipePayload{
+		ID:          recipeID,
+		Title:       "Old Soup",
+		CookMinutes: 30,
+		Tags:        []string{"soup"},
+		Steps:       []events.RecipeStepPayload{{StepNumber: 1, Instruction: "Simmer."}},
+		Ingredients: []events.RecipeIngredientPayload{{IngredientID: salt, Unit: "pinch"}},
+	})
+	require.NoError(t, err)
+
+	mockQ.EXPECT().GetRecipeRevision(mock.Anything, db.GetRecipeRevisionParams{RecipeID: recipeID, Revision: 2}).
+		Return(db.RecipeRevision{RecipeID: recipeID, Revision: 2, Snapshot: snapshot}, nil)
+	mockQ.EXPECT().UpdateRecipe(mock.Anything, db.UpdateRecipeParams{
+		ID:          recipeID,
+		Title:       "Old Soup",
+		CookMinutes: sql.NullInt32{Int32: 30, Valid: true},
+		Tags:        []string{"soup"},
+	}).Return(db.Recipe{ID: recipeID, Title: "Old Soup"}, nil)
+	mockQ.EXPECT().DeleteStepsByRecipe(mock.Anything, recipeID).Return(nil)
+	mockQ.EXPECT().CreateStep(mock.Anything, db.CreateStepParams{
+		RecipeID:    recipeID,
+		StepNumber:  1,
+		Instruction: "Simmer.",
+	}).Return(db.RecipeStep{}, nil)
+	mockQ.EXPECT().DeleteIngredientsByRecipe(mock.Anything, recipeID).Return(nil)
+	mockQ.EXPECT().CreateRecipeIngredient(mock.Anything, db.CreateRecipeIngredientParams{
+		RecipeID:     recipeID,
+		IngredientID: salt,
+		Unit:         sql.NullString{String: "pinch", Valid: true},
+	}).Return(db.RecipeIngredient{}, nil)
+
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: recipeID}).
+		Return(db.Recipe{ID: recipeID, Title: "Old Soup"}, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{RecipeID: recipeID}).Return(nil, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{RecipeID: recipeID}).
+		Return(nil, nil)
+	mockQ.EXPECT().InsertRecipeRevision(mock.Anything, mock.Anything).Return(db.RecipeRevision{Revision: 4}, nil)
+	mockQ.EXPECT().InsertOutboxEvent(mock.Anything, mock.Anything).Return(db.EventOutbox{}, nil)
+	mockQ.EXPECT().InsertRecipeAuditLog(mock.Anything, db.InsertRecipeAuditLogParams{
+		RecipeID: recipeID,
+		Action:   service.AuditActionRevert,
+		Details:  json.RawMessage(`{"revision":2}`),
+	}).Return(db.RecipeAuditLog{}, nil)
+
+	got, err := svc.RevertRecipe(context.Background(), recipeID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Old Soup", got.Title)
+}
+
+func TestRevertRecipe_UnknownRevision(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetTransactor(transactor(t, mockQ))
+
+	recipeID := uuid.New()
+	mockQ.EXPECT().GetRecipeRevision(mock.Anything, mock.Anything).Return(db.RecipeRevision{}, sql.ErrNoRows)
+
+	_, err := svc.RevertRecipe(context.Background(), recipeID, 9)
+	require.ErrorIs(t, err, service.ErrRevisionNotFound)
+
+	_, err = svc.RevertRecipe(context.Background(), recipeID, 0)
+	require.ErrorIs(t, err, service.ErrRevisionNotFound)
+}
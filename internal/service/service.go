@@ -3,8 +3,11 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -22,11 +25,27 @@ type IngredientResolver interface {
 	ResolveIngredient(ctx context.Context, name string) (uuid.UUID, error)
 }
 
+// RecipeScraper fetches a web page for URL ingest.
+type RecipeScraper interface {
+	ScrapeRecipe(ctx context.Context, url string) (*ScrapedPage, error)
+}
+
 // ImportRequestPublisher publishes recipe.import.requested events.
 type ImportRequestPublisher interface {
 	PublishRecipeImportRequested(ctx context.Context, event events.RecipeImportRequestedEvent) error
 }
 
+// ErrNoTransactions is returned by WithTx when the service has neither a
+// database nor a Transactor to run transactions with.
+var ErrNoTransactions = errors.New("service has no database to run transactions on")
+
+// Transactor runs fn in a transaction, handing it a Querier bound to that
+// transaction, and commits if fn returns nil. It stands in for the database
+// in unit tests.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(q db.Querier) error) error
+}
+
 // Service holds dependencies for recipe business logic.
 type Service struct {
 	q               db.Querier
@@ -34,6 +53,14 @@ type Service struct {
 	extractor       LLMExtractor
 	resolver        IngredientResolver
 	importPublisher ImportRequestPublisher
+	scraper         RecipeScraper
+	transactor      Transactor
+	prefill         bool
+	decorate        func(db.Querier) db.Querier
+
+	scrapeWorkers int
+	batchTimeout  time.Duration
+	background    sync.WaitGroup
 }
 
 func New(
@@ -54,6 +81,8 @@ func New(
 		extractor:       extractor,
 		resolver:        resolver,
 		importPublisher: publisher,
+		scrapeWorkers:   defaultScrapeWorkers,
+		batchTimeout:    defaultBatchTimeout,
 	}
 }
 
@@ -61,6 +90,75 @@ func (s *Service) Queries() db.Querier     { return s.q }
 func (s *Service) DB() *sql.DB             { return s.sqlDB }
 func (s *Service) Extractor() LLMExtractor { return s.extractor }
 
+// SetScraper configures the page fetcher used by IngestURL.
+func (s *Service) SetScraper(scraper RecipeScraper) { s.scraper = scraper }
+
+// SetTransactor makes WithTx run its transactions through t instead of the
+// service's *sql.DB.
+func (s *Service) SetTransactor(t Transactor) { s.transactor = t }
+
+// SetBatchScraping sets how many of a batch's URLs are fetched at once and
+// how long fetching all of them may take.
+func (s *Service) SetBatchScraping(workers int, timeout time.Duration) {
+	s.scrapeWorkers, s.batchTimeout = workers, timeout
+}
+
+// Wait blocks until the batch URLs being fetched in the background are done.
+func (s *Service) Wait() { s.background.Wait() }
+
+// SetIngestPrefill controls whether new ingest jobs are pre-filled by the
+// local extractor while they wait for the pipeline. See PrefillIngestionJob.
+func (s *Service) SetIngestPrefill(enabled bool) { s.prefill = enabled }
+func (s *Service) IngestPrefill() bool           { return s.prefill && s.extractor != nil }
+
+// SetQuerierDecorator wraps the service's Querier, and the Querier of every
+// transaction it hands out, with decorate (e.g. to instrument queries).
+func (s *Service) SetQuerierDecorator(decorate func(db.Querier) db.Querier) {
+	s.q = decorate(s.q)
+	s.decorate = decorate
+}
+
+// TxQueries returns a Querier bound to tx.
+func (s *Service) TxQueries(tx *sql.Tx) db.Querier {
+	return decorateQuerier(db.New(tx), s.decorate)
+}
+
+func decorateQuerier(q db.Querier, decorate func(db.Querier) db.Querier) db.Querier {
+	if decorate == nil {
+		return q
+	}
+	return decorate(q)
+}
+
+// WithTx runs fn inside a database transaction, committing if it returns nil.
+// The transaction is bound to ctx: beginning it waits for a connection no
+// longer than ctx's deadline, and it is rolled back once ctx is done.
+// A Transactor set with SetTransactor takes the place of the *sql.DB; with
+// neither, WithTx returns ErrNoTransactions.
+func (s *Service) WithTx(ctx context.Context, fn func(q db.Querier) error) error {
+	if s.transactor != nil {
+		return s.transactor.InTx(ctx, fn)
+	}
+	if s.sqlDB == nil {
+		return ErrNoTransactions
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := fn(s.TxQueries(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
 // ExtractRecipe delegates to the configured LLM extractor.
 func (s *Service) ExtractRecipe(ctx context.Context, rawText string) (*StagedRecipe, error) {
 	if s.extractor == nil {
@@ -79,6 +177,36 @@ func (s *Service) PublishRecipeImportRequested(ctx context.Context, job db.Inges
 	return nil
 }
 
+// PrefillIngestionJob runs the local extractor on a pending job and stores
+// the result as a preview in staged_data. The job stays pending, so it cannot
+// be confirmed until the pipeline answers and overwrites the preview; a job
+// the pipeline has already staged is left untouched.
+func (s *Service) PrefillIngestionJob(ctx context.Context, job db.IngestionJob) (db.IngestionJob, error) {
+	staged, err := s.ExtractRecipe(ctx, job.RawInput)
+	if err != nil {
+		return job, fmt.Errorf("prefill extract: %w", err)
+	}
+
+	data, err := json.Marshal(staged)
+	if err != nil {
+		return job, fmt.Errorf("marshal prefill: %w", err)
+	}
+	raw := json.RawMessage(data)
+
+	updated, err := s.q.PrefillIngestionJob(ctx, db.PrefillIngestionJobParams{
+		ID:          job.ID,
+		StagedData:  &raw,
+		HouseholdID: job.HouseholdID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return job, nil
+	}
+	if err != nil {
+		return job, fmt.Errorf("store prefill: %w", err)
+	}
+	return updated, nil
+}
+
 type noopImportRequestPublisher struct{}
 
 func (noopImportRequestPublisher) PublishRecipeImportRequested(
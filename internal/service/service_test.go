@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+)
+
+func TestPrefillIngestionJob_StoresPreview(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	extractor := service.NewMockLLMExtractor(t)
+	svc := service.New(mockQ, nil, extractor, nil)
+
+	job := db.IngestionJob{ID: uuid.New(), RawInput: "Toast", Status: "pending"}
+	extractor.EXPECT().ExtractRecipe(mock.Anything, "Toast").
+		Return(&service.StagedRecipe{Title: "Toast"}, nil)
+
+	var stored *json.RawMessage
+	mockQ.EXPECT().PrefillIngestionJob(mock.Anything, mock.MatchedBy(func(p db.PrefillIngestionJobParams) bool {
+		stored = p.StagedData
+		return p.ID == job.ID
+	})).RunAndReturn(func(_ context.Context, p db.PrefillIngestionJobParams) (db.IngestionJob, error) {
+		return db.IngestionJob{ID: p.ID, Status: "pending", StagedData: p.StagedData}, nil
+	})
+
+	got, err := svc.PrefillIngestionJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", got.Status)
+	require.NotNil(t, stored)
+
+	var staged service.StagedRecipe
+	require.NoError(t, json.Unmarshal(*stored, &staged))
+	assert.Equal(t, "Toast", staged.Title)
+}
+
+func TestPrefillIngestionJob_AlreadyStaged(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	extractor := service.NewMockLLMExtractor(t)
+	svc := service.New(mockQ, nil, extractor, nil)
+
+	job := db.IngestionJob{ID: uuid.New(), RawInput: "Toast", Status: "pending"}
+	extractor.EXPECT().ExtractRecipe(mock.Anything, "Toast").
+		Return(&service.StagedRecipe{Title: "Toast"}, nil)
+	mockQ.EXPECT().PrefillIngestionJob(mock.Anything, mock.Anything).
+		Return(db.IngestionJob{}, sql.ErrNoRows)
+
+	got, err := svc.PrefillIngestionJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, job, got)
+}
+
+func TestPrefillIngestionJob_ExtractError(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	extractor := service.NewMockLLMExtractor(t)
+	svc := service.New(mockQ, nil, extractor, nil)
+
+	job := db.IngestionJob{ID: uuid.New(), RawInput: "?"}
+	extractor.EXPECT().ExtractRecipe(mock.Anything, "?").Return(nil, errors.New("no recipe"))
+
+	got, err := svc.PrefillIngestionJob(context.Background(), job)
+	require.ErrorContains(t, err, "no recipe")
+	assert.Equal(t, job, got)
+}
+
+func TestIngestPrefill_RequiresExtractor(t *testing.T) {
+	t.Parallel()
+
+	svc := service.New(nil, nil, nil, nil)
+	svc.SetIngestPrefill(true)
+	assert.False(t, svc.IngestPrefill())
+
+	svc = service.New(nil, nil, service.NewMockLLMExtractor(t), nil)
+	assert.False(t, svc.IngestPrefill())
+	svc.SetIngestPrefill(true)
+	assert.True(t, svc.IngestPrefill())
+}
+
+func TestWithTx_RequiresDatabase(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	ran := false
+	err := svc.WithTx(context.Background(), func(db.Querier) error {
+		ran = true
+		return nil
+	})
+	require.ErrorIs(t, err, service.ErrNoTransactions)
+	assert.False(t, ran, "fn must not run outside a transaction")
+
+	tx := transactor(t, mockQ)
+	svc.SetTransactor(tx)
+	require.NoError(t, svc.WithTx(context.Background(), func(q db.Querier) error {
+		assert.Same(t, mockQ, q)
+		return nil
+	}))
+	tx.AssertNumberOfCalls(t, "InTx", 1)
+}
+
+// transactor returns a Transactor that runs every transaction against q.
+func transactor(t *testing.T, q db.Querier) *service.MockTransactor {
+	t.Helper()
+
+	tx := service.NewMockTransactor(t)
+	tx.EXPECT().InTx(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(db.Querier) error) error { return fn(q) }).
+		Maybe()
+	return tx
+}
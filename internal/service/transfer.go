@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+// ErrDuplicateRecipe is returned when importing a recipe that looks like one
+// the household already has.
+var ErrDuplicateRecipe = errors.New("recipe looks like a duplicate")
+
+// ExportedRecipe is one recipe in an export file: a StagedRecipe whose
+// ingredients carry their Dictionary IDs, plus its visibility. IDs and
+// timestamps of the exporting database are left out, so an import always
+// creates new recipes.
+type ExportedRecipe struct {
+	StagedRecipe
+
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// ExportRecipes returns the recipes with the given IDs, or every recipe the
+// caller's household owns when ids is empty. Recipes shared with the
+// household are not its to export.
+func (s *Service) ExportRecipes(ctx context.Context, ids []uuid.UUID) ([]ExportedRecipe, error) {
+	household := tenant.FromContext(ctx)
+	var recipes []db.Recipe
+	if len(ids) == 0 {
+		all, err := s.q.ListRecipes(ctx, household)
+		if err != nil {
+			return nil, fmt.Errorf("list recipes: %w", err)
+		}
+		for _, recipe := range all {
+			if recipe.HouseholdID == household {
+				recipes = append(recipes, recipe)
+			}
+		}
+	}
+	for _, id := range ids {
+		recipe, err := s.q.GetRecipe(ctx, db.GetRecipeParams{ID: id, HouseholdID: household})
+		if err == nil && recipe.HouseholdID != household {
+			err = fmt.Errorf("recipe is owned by household %s", recipe.HouseholdID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get recipe %s: %w", id, err)
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	exported := make([]ExportedRecipe, 0, len(recipes))
+	for _, recipe := range recipes {
+		payload, err := loadRecipePayload(ctx, s.q, recipe.ID)
+		if err != nil {
+			return nil, fmt.Errorf("export recipe %s: %w", recipe.ID, err)
+		}
+		out := ExportedRecipe{
+			StagedRecipe: StagedRecipe{
+				Title:       payload.Title,
+				Description: payload.Description,
+				SourceURL:   payload.SourceURL,
+				Servings:    int(payload.Servings),
+				PrepMinutes: int(payload.PrepMinutes),
+				CookMinutes: int(payload.CookMinutes),
+				Tags:        payload.Tags,
+				Steps:       make([]string, 0, len(payload.Steps)),
+				Ingredients: make([]StagedIngredient, 0, len(payload.Ingredients)),
+			},
+			Visibility: recipe.Visibility,
+		}
+		for _, step := range payload.Steps {
+			out.Steps = append(out.Steps, step.Instruction)
+		}
+		for _, ing := range payload.Ingredients {
+			out.Ingredients = append(out.Ingredients, StagedIngredient{
+				IngredientID:     ing.IngredientID.String(),
+				Quantity:         ing.Quantity,
+				Unit:             ing.Unit,
+				IsOptional:       ing.IsOptional,
+				PreparationNotes: ing.PreparationNotes,
+			})
+		}
+		exported = append(exported, out)
+	}
+	return exported, nil
+}
+
+// ImportRecipe creates recipe in the caller's household. Ingredients without
+// an ID are resolved by name through the Dictionary. Unless force is set, a
+// recipe that looks like an existing one is refused with ErrDuplicateRecipe.
+func (s *Service) ImportRecipe(ctx context.Context, recipe ExportedRecipe, force bool) (db.Recipe, error) {
+	if recipe.Title == "" {
+		return db.Recipe{}, errors.New("title is required")
+	}
+	if err := ValidateVisibility(recipe.Visibility); err != nil {
+		return db.Recipe{}, err
+	}
+	if !force {
+		duplicates, err := s.FindDuplicates(ctx, recipe.Fingerprint())
+		if err != nil {
+			return db.Recipe{}, fmt.Errorf("check for duplicates: %w", err)
+		}
+		if len(duplicates) > 0 {
+			return db.Recipe{}, fmt.Errorf(
+				"%w of %q (%s)", ErrDuplicateRecipe, duplicates[0].Title, duplicates[0].RecipeID,
+			)
+		}
+	}
+
+	var created db.Recipe
+	err := s.WithTx(ctx, func(q db.Querier) error {
+		var err error
+		created, err = s.createRecipe(ctx, q, tenant.FromContext(ctx), recipe.Visibility, recipe.StagedRecipe)
+		return err
+	})
+	return created, err
+}
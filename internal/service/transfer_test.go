@@ -0,0 +1,168 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/db"
+	"github.com/mwhite7112/woodpantry-recipes/internal/mocks"
+	"github.com/mwhite7112/woodpantry-recipes/internal/service"
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+func TestExportRecipes_OwnedOnly(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	household := uuid.New()
+	ctx := tenant.WithHousehold(context.Background(), household)
+	owned := db.Recipe{
+		ID:          uuid.New(),
+		HouseholdID: household,
+		Visibility:  service.VisibilityPrivate,
+		Title:       "Soup",
+		Servings:    sql.NullInt32{Int32: 4, Valid: true},
+		Tags:        []string{"dinner"},
+	}
+	shared := db.Recipe{ID: uuid.New(), HouseholdID: uuid.New(), Title: "Borrowed"}
+	ingredientID := uuid.New()
+
+	mockQ.EXPECT().ListRecipes(mock.Anything, household).Return([]db.Recipe{owned, shared}, nil)
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: owned.ID, HouseholdID: household}).
+		Return(owned, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, db.ListStepsByRecipeParams{
+		RecipeID:    owned.ID,
+		HouseholdID: household,
+	}).
+		Return([]db.RecipeStep{{StepNumber: 1, Instruction: "Simmer"}}, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, db.ListIngredientsByRecipeParams{
+		RecipeID:    owned.ID,
+		HouseholdID: household,
+	}).Return([]db.RecipeIngredient{{
+		ID:           uuid.New(),
+		IngredientID: ingredientID,
+		Quantity:     sql.NullFloat64{Float64: 2, Valid: true},
+		Unit:         sql.NullString{String: "cup", Valid: true},
+	}}, nil)
+
+	got, err := svc.ExportRecipes(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Soup", got[0].Title)
+	assert.Equal(t, service.VisibilityPrivate, got[0].Visibility)
+	assert.Equal(t, 4, got[0].Servings)
+	assert.Equal(t, []string{"Simmer"}, got[0].Steps)
+	require.Len(t, got[0].Ingredients, 1)
+	assert.Equal(t, ingredientID.String(), got[0].Ingredients[0].IngredientID)
+	assert.InDelta(t, 2.0, got[0].Ingredients[0].Quantity, 0.000001)
+	assert.Equal(t, "cup", got[0].Ingredients[0].Unit)
+}
+
+func TestExportRecipes_RefusesSharedRecipe(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	household := uuid.New()
+	ctx := tenant.WithHousehold(context.Background(), household)
+	shared := db.Recipe{ID: uuid.New(), HouseholdID: uuid.New(), Title: "Borrowed"}
+	mockQ.EXPECT().GetRecipe(mock.Anything, db.GetRecipeParams{ID: shared.ID, HouseholdID: household}).
+		Return(shared, nil)
+
+	_, err := svc.ExportRecipes(ctx, []uuid.UUID{shared.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "owned by household")
+}
+
+func TestImportRecipe_RefusesDuplicate(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+
+	existing := uuid.New()
+	mockQ.EXPECT().FindRecipesByTitleKey(mock.Anything, mock.Anything).
+		Return([]db.FindRecipesByTitleKeyRow{{ID: existing, Title: "Soup"}}, nil)
+
+	_, err := svc.ImportRecipe(context.Background(), service.ExportedRecipe{
+		StagedRecipe: service.StagedRecipe{Title: "Soup"},
+	}, false)
+	require.ErrorIs(t, err, service.ErrDuplicateRecipe)
+	assert.Contains(t, err.Error(), existing.String())
+}
+
+func TestImportRecipe_Force(t *testing.T) {
+	t.Parallel()
+
+	mockQ := mocks.NewMockQuerier(t)
+	svc := service.New(mockQ, nil, nil, nil)
+	svc.SetTransactor(transactor(t, mockQ))
+
+	household := uuid.New()
+	ctx := tenant.WithHousehold(context.Background(), household)
+	ingredientID := uuid.New()
+	created := db.Recipe{ID: uuid.New(), HouseholdID: household, Title: "Soup"}
+
+	mockQ.EXPECT().CreateRecipe(mock.Anything, mock.MatchedBy(func(p db.CreateRecipeParams) bool {
+		return p.HouseholdID == household && p.Title == "Soup" && p.Visibility == service.VisibilityPrivate
+	})).Return(created, nil)
+	mockQ.EXPECT().CreateStep(mock.Anything, db.CreateStepParams{
+		RecipeID:    created.ID,
+		StepNumber:  1,
+		Instruction: "Simmer",
+	}).Return(db.RecipeStep{}, nil)
+	mockQ.EXPECT().CreateRecipeIngredient(mock.Anything, mock.MatchedBy(func(p db.CreateRecipeIngredientParams) bool {
+		return p.RecipeID == created.ID && p.IngredientID == ingredientID
+	})).Return(db.RecipeIngredient{}, nil)
+	expectCreatedEvent(mockQ, created)
+
+	got, err := svc.ImportRecipe(ctx, service.ExportedRecipe{
+		StagedRecipe: service.StagedRecipe{
+			Title:       "Soup",
+			Steps:       []string{"Simmer"},
+			Ingredients: []service.StagedIngredient{{IngredientID: ingredientID.String()}},
+		},
+		Visibility: service.VisibilityPrivate,
+	}, true)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+}
+
+func TestImportRecipe_Invalid(t *testing.T) {
+	t.Parallel()
+
+	svc := service.New(mocks.NewMockQuerier(t), nil, nil, nil)
+
+	_, err := svc.ImportRecipe(context.Background(), service.ExportedRecipe{}, true)
+	require.Error(t, err)
+
+	_, err = svc.ImportRecipe(context.Background(), service.ExportedRecipe{
+		StagedRecipe: service.StagedRecipe{Title: "Soup"},
+		Visibility:   "everyone",
+	}, true)
+	require.ErrorIs(t, err, service.ErrInvalidVisibility)
+}
+
+// expectCreatedEvent mocks the reads and writes of recording recipe.created.
+func expectCreatedEvent(mockQ *mocks.MockQuerier, recipe db.Recipe) {
+	mockQ.EXPECT().GetRecipe(mock.Anything, mock.MatchedBy(func(p db.GetRecipeParams) bool {
+		return p.ID == recipe.ID
+	})).Return(recipe, nil)
+	mockQ.EXPECT().ListStepsByRecipe(mock.Anything, mock.MatchedBy(func(p db.ListStepsByRecipeParams) bool {
+		return p.RecipeID == recipe.ID
+	})).Return(nil, nil)
+	mockQ.EXPECT().ListIngredientsByRecipe(mock.Anything, mock.MatchedBy(func(p db.ListIngredientsByRecipeParams) bool {
+		return p.RecipeID == recipe.ID
+	})).Return(nil, nil)
+	mockQ.EXPECT().InsertRecipeRevision(mock.Anything, mock.Anything).Return(db.RecipeRevision{}, nil)
+	mockQ.EXPECT().InsertOutboxEvent(mock.Anything, mock.Anything).Return(db.EventOutbox{}, nil)
+}
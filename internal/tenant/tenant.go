@@ -0,0 +1,69 @@
+// Package tenant carries the caller's household through request contexts.
+// Recipes, ingestion jobs and batches belong to exactly one household, and
+// every query on them is scoped to the household found here.
+package tenant
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/httperr"
+)
+
+// Header is the trusted request header naming the caller's household. It must
+// be set by the gateway in front of this service, never by end users. With
+// authentication enabled, auth.Middleware only lets it through for service
+// and admin callers.
+const Header = "X-Household-ID"
+
+// DefaultHousehold owns recipes created before households existed, and
+// requests without a household when one is not required.
+var DefaultHousehold = uuid.Nil
+
+type contextKey struct{}
+
+// WithHousehold returns a copy of ctx scoped to household id.
+func WithHousehold(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the household ctx is scoped to, or DefaultHousehold.
+func FromContext(ctx context.Context) uuid.UUID {
+	if id, ok := ctx.Value(contextKey{}).(uuid.UUID); ok {
+		return id
+	}
+	return DefaultHousehold
+}
+
+// Middleware scopes each request to the household in the Header. A malformed
+// header is rejected with 400. A missing header is rejected with 401 when
+// required is set and otherwise falls back to DefaultHousehold. Requests
+// already scoped upstream, e.g. from a verified token, keep their household
+// and the header is ignored.
+func Middleware(required bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, scoped := r.Context().Value(contextKey{}).(uuid.UUID); scoped {
+				next.ServeHTTP(w, r)
+				return
+			}
+			v := r.Header.Get(Header)
+			if v == "" {
+				if required {
+					httperr.Write(w, Header+" header is required", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			id, err := uuid.Parse(v)
+			if err != nil {
+				httperr.Write(w, "invalid "+Header+" header", http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithHousehold(r.Context(), id)))
+		})
+	}
+}
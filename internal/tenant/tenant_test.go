@@ -0,0 +1,50 @@
+package tenant_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mwhite7112/woodpantry-recipes/internal/tenant"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	household := uuid.New()
+	tests := []struct {
+		name     string
+		header   string
+		required bool
+		status   int
+		want     uuid.UUID
+	}{
+		{name: "header", header: household.String(), status: http.StatusOK, want: household},
+		{name: "missing falls back to default", status: http.StatusOK, want: tenant.DefaultHousehold},
+		{name: "missing when required", required: true, status: http.StatusUnauthorized},
+		{name: "malformed", header: "household-1", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got uuid.UUID
+			handler := tenant.Middleware(tt.required)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = tenant.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/recipes", nil)
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -22,6 +22,25 @@ import (
 // The container is torn down via t.Cleanup.
 func SetupDB(t *testing.T) *sql.DB {
 	t.Helper()
+
+	sqlDB, err := sql.Open("postgres", SetupEmptyDB(t))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := sqlDB.Ping(); err != nil {
+		t.Fatalf("ping db: %v", err)
+	}
+
+	runMigrations(t, sqlDB)
+	return sqlDB
+}
+
+// SetupEmptyDB starts a Postgres container without running migrations and
+// returns its connection string. The container is torn down via t.Cleanup.
+func SetupEmptyDB(t *testing.T) string {
+	t.Helper()
 	ctx := context.Background()
 
 	// Disable Ryuk reaper — it doesn't work with rootless Podman.
@@ -52,19 +71,7 @@ func SetupDB(t *testing.T) *sql.DB {
 	if err != nil {
 		t.Fatalf("get connection string: %v", err)
 	}
-
-	sqlDB, err := sql.Open("postgres", connStr)
-	if err != nil {
-		t.Fatalf("open db: %v", err)
-	}
-	t.Cleanup(func() { sqlDB.Close() })
-
-	if err := sqlDB.Ping(); err != nil {
-		t.Fatalf("ping db: %v", err)
-	}
-
-	runMigrations(t, sqlDB)
-	return sqlDB
+	return connStr
 }
 
 func runMigrations(t *testing.T, sqlDB *sql.DB) {